@@ -2,12 +2,41 @@ package artifactory
 
 import (
 	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
 )
 
 const (
+	accessFederationEndpoint         = "access/api/v1/system/federation"
 	accessFederationValidateEndpoint = "access/api/v1/system/federation/validate_server"
+	accessTokenInfoEndpoint          = "access/api/v1/tokens/me"
+	accessTokensEndpoint             = "access/api/v1/tokens"
+	adminScope                       = "applied-permissions/admin"
 )
 
+// adminScopedOptionalMetrics lists optional metrics whose endpoints are only
+// available to tokens with admin scope.
+var adminScopedOptionalMetrics = []string{
+	"replication_status",
+	"federation_status",
+	"open_metrics",
+	"access_federation_validate",
+	"background_tasks",
+	"permission_target_repos",
+	"backups",
+	"access_tokens",
+	"federation_mirror_lags",
+	"federation_unavailable_mirrors",
+	"system_info",
+	"remote_repos",
+	"garbage_collection",
+	"repo_layouts",
+	"access_federation_servers",
+	"native_metrics",
+	"virtual_repos",
+}
+
 type AccessFederationValid struct {
 	Status bool
 	NodeId string
@@ -24,13 +53,23 @@ func (c *Client) FetchAccessFederationValidStatus() (AccessFederationValid, erro
 	}
 	accessFederationValid.NodeId = resp.NodeId
 
+	if err := c.ValidateAccessFederationServer(c.accessFederationTarget); err != nil {
+		return accessFederationValid, err
+	}
+	accessFederationValid.Status = true
+	return accessFederationValid, nil
+}
+
+// ValidateAccessFederationServer validates whether trust is established
+// towards the JFrog Access Federation server at url.
+func (c *Client) ValidateAccessFederationServer(url string) error {
 	jsonBody := map[string]string{
-		"url": c.accessFederationTarget,
+		"url": url,
 	}
 	jsonBytes, err := json.Marshal(jsonBody)
 	if err != nil {
 		c.logger.Error("issue when trying to marshal JSON body")
-		return accessFederationValid, err
+		return err
 	}
 	headers := map[string]string{
 		"Content-Type": "application/json",
@@ -38,12 +77,193 @@ func (c *Client) FetchAccessFederationValidStatus() (AccessFederationValid, erro
 	c.logger.Debug(
 		"Fetching JFrog Access Federation validation status",
 		"endpoint", accessFederationValidateEndpoint,
-		"target", c.accessFederationTarget,
+		"target", url,
 	)
 	_, err = c.PostHTTP(accessFederationValidateEndpoint, jsonBytes, &headers)
+	return err
+}
+
+// AccessFederationServer represents a single server of the JFrog Access
+// Federation (Circle of Trust)
+type AccessFederationServer struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Active bool   `json:"active"`
+}
+
+// AccessFederation represents API response from the JFrog Access Federation
+// configuration endpoint
+type AccessFederation struct {
+	Servers []AccessFederationServer
+	// Configured is false if JFrog Access Federation isn't configured.
+	Configured bool
+	NodeId     string
+}
+
+// FetchAccessFederation makes the API call to the JFrog Access Federation
+// configuration endpoint and returns the servers of the Circle of Trust.
+func (c *Client) FetchAccessFederation() (AccessFederation, error) {
+	var accessFederation AccessFederation
+	c.logger.Debug("Fetching JFrog Access Federation servers")
+	resp, err := c.GetHTTP(accessFederationEndpoint)
 	if err != nil {
-		return accessFederationValid, err
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.status == 404 {
+			return accessFederation, nil
+		}
+		return accessFederation, err
+	}
+	accessFederation.NodeId = resp.NodeId
+	if err := c.unmarshalJSON(accessFederationEndpoint, resp.Body, &accessFederation.Servers); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal JFrog Access Federation respond")
+		return accessFederation, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: accessFederationEndpoint,
+		}
+	}
+	accessFederation.Configured = true
+	return accessFederation, nil
+}
+
+// TokenInfo represents API response from the access token info endpoint
+type TokenInfo struct {
+	TokenId string `json:"token_id"`
+	Subject string `json:"subject"`
+	Scope   string `json:"scope"`
+	Expiry  int64  `json:"expiry"`
+}
+
+// Scopes returns the individual scopes granted to the token.
+func (t TokenInfo) Scopes() []string {
+	return strings.Fields(t.Scope)
+}
+
+// IsAdmin reports whether the token was granted admin scope.
+func (t TokenInfo) IsAdmin() bool {
+	return slices.Contains(t.Scopes(), adminScope)
+}
+
+// FetchTokenInfo makes the API call to the access token info endpoint and returns TokenInfo
+func (c *Client) FetchTokenInfo() (TokenInfo, error) {
+	var tokenInfo TokenInfo
+	c.logger.Debug("Fetching access token info")
+	resp, err := c.GetHTTP(accessTokenInfoEndpoint)
+	if err != nil {
+		return tokenInfo, err
+	}
+	if err := c.unmarshalJSON(accessTokenInfoEndpoint, resp.Body, &tokenInfo); err != nil {
+		c.logger.Error("There was an issue when trying to unmarshal token info response")
+		return tokenInfo, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: accessTokenInfoEndpoint,
+		}
+	}
+	return tokenInfo, nil
+}
+
+// ActiveTokens represents API response from the access tokens endpoint
+type ActiveTokens struct {
+	Tokens []TokenInfo
+	NodeId string
+}
+
+// FetchActiveTokens makes the API call to the access tokens endpoint and returns
+// the access tokens which haven't been revoked. Paginated responses are
+// followed until all pages have been fetched.
+func (c *Client) FetchActiveTokens() (ActiveTokens, error) {
+	var tokens ActiveTokens
+	c.logger.Debug("Fetching access tokens")
+	items, nodeId, err := c.fetchPagesWith(c.GetHTTP, accessTokensEndpoint, "tokens")
+	if err != nil {
+		return tokens, err
+	}
+	tokens.NodeId = nodeId
+	tokens.Tokens = make([]TokenInfo, len(items))
+	for i, item := range items {
+		if err := c.unmarshalJSON(accessTokensEndpoint, item, &tokens.Tokens[i]); err != nil {
+			c.logger.Error("There was an issue when trying to unmarshal access tokens response")
+			return tokens, &UnmarshalError{
+				message:  err.Error(),
+				endpoint: accessTokensEndpoint,
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// CheckOptionalMetricsAccess warns about enabled optional metrics which can't
+// work with the configured auth and, if federation metrics are enabled, logs
+// the result of a preflight call of the federation endpoints. It is
+// diagnostic only, so errors are logged and never returned.
+func (c *Client) CheckOptionalMetricsAccess() {
+	switch c.authMethod {
+	case "accessToken":
+		c.CheckTokenScope()
+	case "anonymous":
+		for _, metric := range c.OptionalMetrics.Enabled() {
+			if slices.Contains(adminScopedOptionalMetrics, metric) {
+				c.logger.Warn(
+					"Optional metric requires admin permissions, which anonymous access doesn't have",
+					"metric", metric,
+				)
+			}
+		}
+	}
+	if c.OptionalMetrics.Federation() {
+		c.checkFederation()
+	}
+}
+
+// checkFederation logs a one-line summary of whether the enabled federation
+// metrics can be fetched.
+func (c *Client) checkFederation() {
+	state, err := c.FederationState()
+	switch state {
+	case FederationEnabled:
+		c.logger.Info("Federation preflight succeeded", "state", state)
+	case FederationRTFS:
+		c.logger.Warn("Federation preflight: RTFS is enabled, federation mirror metrics will be empty", "state", state)
+	case FederationUnauthorized:
+		c.logger.Warn("Federation preflight: credentials aren't authorized, federation metrics require admin permissions", "state", state, "err", err.Error())
+	default:
+		c.logger.Warn("Federation preflight: federation is unavailable, federation metrics will be skipped", "state", state, "err", err.Error())
+	}
+}
+
+// CheckTokenScope logs the scopes of the configured access token and warns
+// about enabled optional metrics which will fail due to insufficient scope.
+// It is diagnostic only, so errors are logged and never returned.
+func (c *Client) CheckTokenScope() {
+	if c.authMethod != "accessToken" {
+		return
+	}
+	tokenInfo, err := c.FetchTokenInfo()
+	if err != nil {
+		c.logger.Warn(
+			"Couldn't fetch access token info to check its scope",
+			"err", err.Error(),
+		)
+		return
+	}
+	c.logger.Info(
+		"Access token scope",
+		"subject", tokenInfo.Subject,
+		"scopes", tokenInfo.Scopes(),
+	)
+	if tokenInfo.IsAdmin() {
+		return
+	}
+	c.logger.Warn(
+		"Access token does not have admin scope, security and storage metrics will fail with 403",
+		"required_scope", adminScope,
+	)
+	for _, metric := range c.OptionalMetrics.Enabled() {
+		if slices.Contains(adminScopedOptionalMetrics, metric) {
+			c.logger.Warn(
+				"Optional metric requires an access token with admin scope",
+				"metric", metric,
+			)
+		}
 	}
-	accessFederationValid.Status = true
-	return accessFederationValid, nil
 }
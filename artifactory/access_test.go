@@ -0,0 +1,225 @@
+package artifactory
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestFetchTokenInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		serverResponse string
+		statusCode     int
+		expectedScopes int
+		expectAdmin    bool
+		expectError    bool
+	}{
+		{
+			name:           "Admin token",
+			serverResponse: `{"token_id":"abc","subject":"jfac@01/users/admin","scope":"applied-permissions/admin","expiry":0}`,
+			statusCode:     http.StatusOK,
+			expectedScopes: 1,
+			expectAdmin:    true,
+		},
+		{
+			name:           "Scoped token",
+			serverResponse: `{"token_id":"def","subject":"jfac@01/users/reader","scope":"applied-permissions/user applied-permissions/groups:readers"}`,
+			statusCode:     http.StatusOK,
+			expectedScopes: 2,
+			expectAdmin:    false,
+		},
+		{
+			name:           "Invalid JSON response",
+			serverResponse: `{"token_id": abc}`,
+			statusCode:     http.StatusOK,
+			expectError:    true,
+		},
+		{
+			name:           "Forbidden",
+			serverResponse: `{"errors":[{"status":403,"message":"Forbidden"}]}`,
+			statusCode:     http.StatusForbidden,
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/access/api/v1/tokens/me" {
+					t.Errorf("Expected request to /access/api/v1/tokens/me, got %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.serverResponse))
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL + "/artifactory"
+			client := NewClient(conf)
+
+			tokenInfo, err := client.FetchTokenInfo()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchTokenInfo() error = %v", err)
+			}
+			if len(tokenInfo.Scopes()) != tt.expectedScopes {
+				t.Errorf("Scopes() = %v, want %d scopes", tokenInfo.Scopes(), tt.expectedScopes)
+			}
+			if tokenInfo.IsAdmin() != tt.expectAdmin {
+				t.Errorf("IsAdmin() = %v, want %v", tokenInfo.IsAdmin(), tt.expectAdmin)
+			}
+		})
+	}
+}
+
+func TestFetchActiveTokensPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/access/api/v1/tokens" {
+			t.Errorf("Expected request to /access/api/v1/tokens, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{"tokens":[{"token_id":"a","subject":"jfac@01/users/admin","expiry":1700000000},{"token_id":"b","subject":"jfac@01/users/ci"}],"cursor":"next"}`))
+		case "next":
+			w.Write([]byte(`{"tokens":[{"token_id":"c","subject":"jfac@01/users/reader","expiry":1800000000}],"cursor":""}`))
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL + "/artifactory"
+	client := NewClient(conf)
+
+	tokens, err := client.FetchActiveTokens()
+	if err != nil {
+		t.Fatalf("FetchActiveTokens() error = %v", err)
+	}
+	if len(tokens.Tokens) != 3 {
+		t.Fatalf("FetchActiveTokens() returned %d tokens, want 3", len(tokens.Tokens))
+	}
+	if tokens.Tokens[1].Expiry != 0 {
+		t.Errorf("Token without expiry has Expiry = %d, want 0", tokens.Tokens[1].Expiry)
+	}
+	if tokens.Tokens[2].Expiry != 1800000000 {
+		t.Errorf("Expiry = %d, want 1800000000", tokens.Tokens[2].Expiry)
+	}
+}
+
+func TestFetchAccessFederation(t *testing.T) {
+	tests := []struct {
+		name             string
+		responseBody     string
+		responseCode     int
+		expectError      bool
+		expectConfigured bool
+		expected         []AccessFederationServer
+	}{
+		{
+			name: "Federation configured",
+			responseBody: `[
+				{"id": "jpd-eu", "name": "Europe", "url": "https://eu.example.com/access", "active": true},
+				{"id": "jpd-us", "name": "US", "url": "https://us.example.com/access", "active": false}
+			]`,
+			responseCode:     http.StatusOK,
+			expectConfigured: true,
+			expected: []AccessFederationServer{
+				{Id: "jpd-eu", Name: "Europe", URL: "https://eu.example.com/access", Active: true},
+				{Id: "jpd-us", Name: "US", URL: "https://us.example.com/access", Active: false},
+			},
+		},
+		{
+			name:         "Federation not configured",
+			responseBody: `{"errors":[{"code":"NOT_FOUND","message":"Not Found"}]}`,
+			responseCode: http.StatusNotFound,
+		},
+		{
+			name:         "Server error",
+			responseBody: `{"errors":[{"code":"INTERNAL_ERROR","message":"Internal Server Error"}]}`,
+			responseCode: http.StatusInternalServerError,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/access/api/v1/system/federation" {
+					t.Errorf("Expected request to /access/api/v1/system/federation, got %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.responseCode)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL + "/artifactory"
+			client := NewClient(conf)
+
+			accessFederation, err := client.FetchAccessFederation()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchAccessFederation() error = %v", err)
+			}
+			if accessFederation.Configured != tt.expectConfigured {
+				t.Errorf("Configured = %v, want %v", accessFederation.Configured, tt.expectConfigured)
+			}
+			if len(accessFederation.Servers) != len(tt.expected) {
+				t.Fatalf("FetchAccessFederation() returned %d servers, want %d", len(accessFederation.Servers), len(tt.expected))
+			}
+			for i, expected := range tt.expected {
+				if accessFederation.Servers[i] != expected {
+					t.Errorf("Servers[%d] = %+v, want %+v", i, accessFederation.Servers[i], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckOptionalMetricsAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/federation/status/unavailableMirrors" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":[{"status":403,"message":"Forbidden"}]}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.Credentials = &config.Credentials{AuthMethod: "anonymous"}
+	conf.ExporterRuntimeConfig.OptionalMetrics = config.OptionalMetrics{FederationMirrorLags: true, Docker: true}
+	conf.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+	client := NewClient(conf)
+
+	client.CheckOptionalMetricsAccess()
+
+	log := buf.String()
+	if !strings.Contains(log, "anonymous access doesn't have") || !strings.Contains(log, "metric=federation_mirror_lags") {
+		t.Errorf("Expected a warning about federation_mirror_lags requiring admin permissions, got log %q", log)
+	}
+	if strings.Contains(log, "metric=docker") {
+		t.Errorf("Expected no warning about docker, got log %q", log)
+	}
+	if strings.Count(log, "Federation preflight") != 1 || !strings.Contains(log, "state=unauthorized") {
+		t.Errorf("Expected a single federation preflight summary with state unauthorized, got log %q", log)
+	}
+}
@@ -0,0 +1,82 @@
+package artifactory
+
+import (
+	"fmt"
+	"strings"
+)
+
+const aqlEndpoint = "search/aql"
+
+// AQLItem represents single item of an AQL items.find query result.
+// Only the fields included in the query are set.
+type AQLItem struct {
+	Repo     string `json:"repo"`
+	Path     string `json:"path"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Size     int64  `json:"size"`
+	Created  string `json:"created"`
+	Modified string `json:"modified"`
+}
+
+// AQLRange represents the range of an AQL query result.
+type AQLRange struct {
+	StartPos int `json:"start_pos"`
+	EndPos   int `json:"end_pos"`
+	Total    int `json:"total"`
+}
+
+// AQLResult represents API response from AQL endpoint
+type AQLResult struct {
+	Results []AQLItem `json:"results"`
+	Range   AQLRange  `json:"range"`
+	NodeId  string
+}
+
+// itemsFindQuery builds an AQL items.find query from the JSON criteria,
+// including the given fields in the result.
+func itemsFindQuery(criteria string, fields ...string) string {
+	query := fmt.Sprintf("items.find(%s)", criteria)
+	if len(fields) > 0 {
+		quoted := make([]string, len(fields))
+		for i, field := range fields {
+			quoted[i] = fmt.Sprintf("%q", field)
+		}
+		query += fmt.Sprintf(".include(%s)", strings.Join(quoted, ", "))
+	}
+	return query
+}
+
+// ExecuteAQL runs the AQL query and returns its parsed result.
+func (c *Client) ExecuteAQL(query string) (AQLResult, error) {
+	var result AQLResult
+	c.logger.Debug(
+		"Executing AQL query",
+		"query", query,
+	)
+	resp, err := c.QueryAQL([]byte(query))
+	if err != nil {
+		return result, err
+	}
+	result.NodeId = resp.NodeId
+	if err := c.unmarshalJSON(aqlEndpoint, resp.Body, &result); err != nil {
+		c.logger.Error("There was an issue when trying to unmarshal AQL response")
+		return result, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: aqlEndpoint,
+		}
+	}
+	return result, nil
+}
+
+// FindItemsLimit runs an AQL items.find query like FindItems, returning at
+// most limit items.
+func (c *Client) FindItemsLimit(criteria string, limit int, fields ...string) (AQLResult, error) {
+	return c.ExecuteAQL(fmt.Sprintf("%s.limit(%d)", itemsFindQuery(criteria, fields...), limit))
+}
+
+// FindItems runs an AQL items.find query with the JSON criteria and returns
+// the found items. Only the given fields of the items are included.
+func (c *Client) FindItems(criteria string, fields ...string) (AQLResult, error) {
+	return c.ExecuteAQL(itemsFindQuery(criteria, fields...))
+}
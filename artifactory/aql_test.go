@@ -0,0 +1,71 @@
+package artifactory
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestItemsFindQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria string
+		fields   []string
+		expected string
+	}{
+		{
+			name:     "Without fields",
+			criteria: `{"repo": "libs-release"}`,
+			expected: `items.find({"repo": "libs-release"})`,
+		},
+		{
+			name:     "With fields",
+			criteria: `{"created": {"$last": "15minutes"}}`,
+			fields:   []string{"name", "repo"},
+			expected: `items.find({"created": {"$last": "15minutes"}}).include("name", "repo")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if query := itemsFindQuery(tt.criteria, tt.fields...); query != tt.expected {
+				t.Errorf("itemsFindQuery() = %s, want %s", query, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/search/aql" {
+			t.Errorf("Expected POST to /api/search/aql, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `items.find({"type": "file"}).include("name", "repo")` {
+			t.Errorf("Unexpected AQL query: %s", body)
+		}
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"results":[{"repo":"libs-release","name":"a.jar"},{"repo":"libs-release","name":"b.jar"}],"range":{"start_pos":0,"end_pos":2,"total":2}}`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	result, err := client.FindItems(`{"type": "file"}`, "name", "repo")
+	if err != nil {
+		t.Fatalf("FindItems() error = %v", err)
+	}
+	if len(result.Results) != 2 || result.Range.Total != 2 {
+		t.Fatalf("FindItems() returned %d items with total %d, want 2", len(result.Results), result.Range.Total)
+	}
+	if result.Results[1].Name != "b.jar" || result.Results[1].Repo != "libs-release" {
+		t.Errorf("Results[1] = %+v, want libs-release/b.jar", result.Results[1])
+	}
+	if result.NodeId != "test-node" {
+		t.Errorf("NodeId = %s, want test-node", result.NodeId)
+	}
+}
@@ -0,0 +1,42 @@
+package artifactory
+
+import (
+	"encoding/xml"
+)
+
+const systemConfigurationEndpoint = "system/configuration"
+
+// Backup represents a backup configured in Artifactory
+type Backup struct {
+	Key     string `xml:"key"`
+	Enabled bool   `xml:"enabled"`
+	CronExp string `xml:"cronExp"`
+}
+
+// Backups represents the backups section of the Artifactory configuration.
+// The configuration doesn't include the backup history.
+type Backups struct {
+	Backups []Backup `xml:"backups>backup"`
+	NodeId  string   `xml:"-"`
+}
+
+// FetchBackups makes the API call to the system configuration endpoint and
+// returns the configured backups.
+func (c *Client) FetchBackups() (Backups, error) {
+	var backups Backups
+	c.logger.Debug("Fetching backups configuration")
+	resp, err := c.FetchHTTP(systemConfigurationEndpoint)
+	if err != nil {
+		return backups, err
+	}
+	backups.NodeId = resp.NodeId
+
+	if err := xml.Unmarshal(resp.Body, &backups); err != nil {
+		c.logger.Error("There was an issue when trying to unmarshal backups configuration response")
+		return backups, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: systemConfigurationEndpoint,
+		}
+	}
+	return backups, nil
+}
@@ -0,0 +1,88 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchBackups(t *testing.T) {
+	tests := []struct {
+		name            string
+		response        string
+		expectedBackups []Backup
+		expectError     bool
+	}{
+		{
+			name: "Backups configured",
+			response: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<config xmlns="http://artifactory.jfrog.org/xsd/3.1.17">
+    <backups>
+        <backup>
+            <key>backup-daily</key>
+            <enabled>true</enabled>
+            <cronExp>0 0 2 ? * MON-FRI</cronExp>
+            <retentionPeriodHours>0</retentionPeriodHours>
+        </backup>
+        <backup>
+            <key>backup-weekly</key>
+            <enabled>false</enabled>
+            <cronExp>0 0 2 ? * SAT</cronExp>
+        </backup>
+    </backups>
+</config>`,
+			expectedBackups: []Backup{
+				{Key: "backup-daily", Enabled: true, CronExp: "0 0 2 ? * MON-FRI"},
+				{Key: "backup-weekly", Enabled: false, CronExp: "0 0 2 ? * SAT"},
+			},
+		},
+		{
+			name:     "No backups configured",
+			response: `<config xmlns="http://artifactory.jfrog.org/xsd/3.1.17"><backups/></config>`,
+		},
+		{
+			name:        "Invalid response",
+			response:    `{"errors": []}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/system/configuration" {
+					t.Errorf("Unexpected request to %s", r.URL.Path)
+				}
+				w.Header().Set("X-Artifactory-Node-Id", "test-node")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			client := NewClient(conf)
+
+			backups, err := client.FetchBackups()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchBackups() error = %v", err)
+			}
+			if backups.NodeId != "test-node" {
+				t.Errorf("NodeId = %s, want test-node", backups.NodeId)
+			}
+			if len(backups.Backups) != len(tt.expectedBackups) {
+				t.Fatalf("FetchBackups() returned %d backups, want %d", len(backups.Backups), len(tt.expectedBackups))
+			}
+			for i, expected := range tt.expectedBackups {
+				if backups.Backups[i] != expected {
+					t.Errorf("Backup %d = %+v, want %+v", i, backups.Backups[i], expected)
+				}
+			}
+		})
+	}
+}
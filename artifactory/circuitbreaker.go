@@ -0,0 +1,122 @@
+package artifactory
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// CircuitState represents the state of the circuit for a single endpoint.
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+// worseThan reports whether s is a worse state than other. An open circuit is
+// worse than a half-open one, which is still recovering, and both are worse
+// than a closed one.
+func (s CircuitState) worseThan(other CircuitState) bool {
+	severity := map[CircuitState]int{CircuitClosed: 0, CircuitHalfOpen: 1, CircuitOpen: 2}
+	return severity[s] > severity[other]
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// CircuitBreaker protects Artifactory from repeated requests to failing endpoints.
+// After threshold consecutive failures the circuit of an endpoint opens and
+// requests are skipped for the cooldown period. Afterwards a single request is
+// let through to test whether the endpoint has recovered.
+type CircuitBreaker struct {
+	mutex     sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	circuits  map[string]*circuit
+}
+
+// NewCircuitBreaker returns an initialized CircuitBreaker, or nil if it's disabled.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		circuits:  make(map[string]*circuit),
+	}
+}
+
+// Allow reports whether a request to the endpoint may be made.
+func (b *CircuitBreaker) Allow(endpoint string) bool {
+	if b == nil {
+		return true
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, exists := b.circuits[endpoint]
+	if !exists {
+		return true
+	}
+	switch c.state {
+	case CircuitOpen:
+		if time.Since(c.openedAt) < b.cooldown {
+			return false
+		}
+		c.state = CircuitHalfOpen
+		return true
+	case CircuitHalfOpen:
+		// A request testing the recovery is already in flight.
+		return false
+	}
+	return true
+}
+
+// Report records the outcome of a request to the endpoint.
+// Endpoints which don't exist or deny access are not considered failing, as
+// backing off won't change their response.
+func (b *CircuitBreaker) Report(endpoint string, err error) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, exists := b.circuits[endpoint]
+	if !exists {
+		c = &circuit{}
+		b.circuits[endpoint] = c
+	}
+	var apiErr *APIError
+	if err == nil || (errors.As(err, &apiErr) && apiErr.status == 404) || errors.Is(err, ErrAdminRequired) {
+		c.state = CircuitClosed
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.state == CircuitHalfOpen || c.failures >= b.threshold {
+		c.state = CircuitOpen
+		c.openedAt = time.Now()
+	}
+}
+
+// States returns the current circuit state of every endpoint requested so far.
+func (b *CircuitBreaker) States() map[string]CircuitState {
+	if b == nil {
+		return nil
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	states := make(map[string]CircuitState, len(b.circuits))
+	for endpoint, c := range b.circuits {
+		states[endpoint] = c.state
+	}
+	return states
+}
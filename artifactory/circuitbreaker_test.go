@@ -0,0 +1,134 @@
+package artifactory
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	breaker := NewCircuitBreaker(0, time.Minute)
+	if breaker != nil {
+		t.Fatal("NewCircuitBreaker() should return nil when threshold is 0")
+	}
+	breaker.Report("system/ping", errors.New("failure"))
+	if !breaker.Allow("system/ping") {
+		t.Error("Disabled circuit breaker should always allow requests")
+	}
+	if states := breaker.States(); states != nil {
+		t.Errorf("States() = %v, want nil", states)
+	}
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	breaker := NewCircuitBreaker(2, 50*time.Millisecond)
+	failure := errors.New("failure")
+
+	breaker.Report("storageinfo", failure)
+	if !breaker.Allow("storageinfo") {
+		t.Fatal("Circuit should stay closed below the threshold")
+	}
+	breaker.Report("storageinfo", failure)
+	if breaker.Allow("storageinfo") {
+		t.Fatal("Circuit should open once the threshold is reached")
+	}
+	if state := breaker.States()["storageinfo"]; state != CircuitOpen {
+		t.Errorf("State = %v, want %v", state, CircuitOpen)
+	}
+	if !breaker.Allow("system/ping") {
+		t.Error("Circuits of other endpoints should not be affected")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !breaker.Allow("storageinfo") {
+		t.Fatal("Circuit should half-open after the cooldown")
+	}
+	if state := breaker.States()["storageinfo"]; state != CircuitHalfOpen {
+		t.Errorf("State = %v, want %v", state, CircuitHalfOpen)
+	}
+	if breaker.Allow("storageinfo") {
+		t.Error("Only a single request should be let through while half-open")
+	}
+
+	breaker.Report("storageinfo", failure)
+	if state := breaker.States()["storageinfo"]; state != CircuitOpen {
+		t.Errorf("Failing half-open circuit state = %v, want %v", state, CircuitOpen)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	breaker.Allow("storageinfo")
+	breaker.Report("storageinfo", nil)
+	if state := breaker.States()["storageinfo"]; state != CircuitClosed {
+		t.Errorf("Recovered circuit state = %v, want %v", state, CircuitClosed)
+	}
+}
+
+func TestCircuitBreakerIgnoresNotFound(t *testing.T) {
+	breaker := NewCircuitBreaker(1, time.Minute)
+	breaker.Report("storage/quota", &APIError{status: http.StatusNotFound})
+	if !breaker.Allow("storage/quota") {
+		t.Error("Circuit should not open for endpoints which don't exist")
+	}
+}
+
+func TestFetchHTTPCircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"errors":[{"status":500,"message":"Internal Server Error"}]}`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.CircuitBreakerThreshold = 2
+	conf.CircuitBreakerCooldown = time.Minute
+	client := NewClient(conf)
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.FetchHTTP("storageinfo"); err == nil {
+			t.Fatal("Expected error but got none")
+		}
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Server received %d requests, want 2", n)
+	}
+
+	_, err := client.FetchHTTP("storageinfo")
+	var circuitErr *CircuitOpenError
+	if !errors.As(err, &circuitErr) {
+		t.Errorf("Expected CircuitOpenError, got %v", err)
+	}
+	if state := client.CircuitStates()["storageinfo"]; state != CircuitOpen {
+		t.Errorf("CircuitStates()[storageinfo] = %v, want %v", state, CircuitOpen)
+	}
+}
+
+func TestCircuitStatesNormalized(t *testing.T) {
+	conf := createTestConfig()
+	conf.CircuitBreakerThreshold = 1
+	conf.CircuitBreakerCooldown = time.Minute
+	client := NewClient(conf)
+
+	client.circuitBreaker.Report("storage/libs-release?list&deep=1", nil)
+	client.circuitBreaker.Report("storage/libs-snapshot?list&deep=1", &APIError{status: http.StatusInternalServerError})
+	client.circuitBreaker.Report("repositories/npm-remote", nil)
+
+	expected := map[string]CircuitState{
+		"storage/{path}":      CircuitOpen,
+		"repositories/{repo}": CircuitClosed,
+	}
+	states := client.CircuitStates()
+	if len(states) != len(expected) {
+		t.Fatalf("CircuitStates() = %v, want %v", states, expected)
+	}
+	for endpoint, state := range expected {
+		if states[endpoint] != state {
+			t.Errorf("CircuitStates()[%s] = %v, want %v", endpoint, states[endpoint], state)
+		}
+	}
+}
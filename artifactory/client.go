@@ -3,55 +3,151 @@ package artifactory
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
-	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/common/version"
+
 	"github.com/peimanja/artifactory_exporter/config"
 )
 
+const (
+	// unixSocketScheme is the scheme of scrape URIs of an Artifactory
+	// reachable over a Unix domain socket, e.g. unix:///run/artifactory.sock.
+	unixSocketScheme = "unix://"
+	// unixSocketBaseURI is the base URI of the requests sent over a Unix
+	// domain socket. Its host is ignored, as all connections use the socket.
+	unixSocketBaseURI = "http://localhost/artifactory"
+)
+
 // Client represents Artifactory HTTP Client
 type Client struct {
 	URI                    string
 	authMethod             string
+	userAgent              string
 	cred                   config.Credentials
+	customAuthHeader       *config.CustomAuthHeader
 	OptionalMetrics        config.OptionalMetrics
 	accessFederationTarget string
+	maxPages               int
+	dockerRepos            []string
+	dockerConcurrency      int
+	storageCalculate       bool
+	storageCalcTimeout     time.Duration
+	federationTimeout      time.Duration
 	client                 *http.Client
 	logger                 *slog.Logger
 	responseCache          *ResponseCache
+	circuitBreaker         *CircuitBreaker
+	globalRetries          int
+	subsystemRetries       map[string]int
+	retryWait              time.Duration
+	limiter                *requestLimiter
+	rtfsEnabled            *atomic.Bool
+	certExpiry             *atomic.Int64
+	traceHook              TraceHook
+	requestHook            RequestHook
+	unmarshalErrorHook     UnmarshalErrorHook
+	requestInterceptor     RequestInterceptor
+	strictJSON             bool
+	schemaDrift            *sync.Map
+	ctx                    context.Context
+	cancel                 context.CancelFunc
 }
 
 // NewClient returns an initialized Artifactory HTTP Client.
 func NewClient(conf *config.Config) *Client {
-	tr := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: !conf.ArtiSSLVerify}}
+	minTLSVersion := conf.MinTLSVersion
+	if minTLSVersion == 0 {
+		minTLSVersion = tls.VersionTLS12
+	}
+	rootCAs, err := conf.RootCAs()
+	if err != nil {
+		conf.Logger.Error(
+			"Couldn't load CA bundle, using system CAs",
+			"err", err.Error(),
+		)
+	}
+	tr := &http.Transport{TLSClientConfig: &tls.Config{
+		InsecureSkipVerify: !conf.ArtiSSLVerify,
+		MinVersion:         minTLSVersion,
+		RootCAs:            rootCAs,
+	}}
+	uri := conf.ArtiScrapeURI
+	if socket, ok := strings.CutPrefix(uri, unixSocketScheme); ok {
+		dialer := &net.Dialer{}
+		tr.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		uri = unixSocketBaseURI
+	}
 	client := &http.Client{
 		Timeout:   conf.ArtiTimeout,
 		Transport: tr,
 	}
+	if conf.ArtiNoFollowRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	dockerConcurrency := conf.DockerConcurrency
+	if dockerConcurrency < 1 {
+		dockerConcurrency = 1
+	}
+	userAgent := conf.UserAgent
+	if userAgent == "" {
+		userAgent = "artifactory_exporter/" + version.Version
+	}
 	responseCache := NewResponseCache(conf.UseCache, conf.CacheTTL, conf.CacheTimeout)
 	logger := conf.Logger
+	ctx, cancel := context.WithCancel(context.Background())
 	if responseCache != nil {
 		go func() {
 			for {
-				time.Sleep(300 * time.Second)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(300 * time.Second):
+				}
 				n := responseCache.Prune()
 				logger.Debug("Pruned ResponseCache", "removed_items", n)
 			}
 		}()
 	}
 	return &Client{
-		URI:                    conf.ArtiScrapeURI,
+		URI:                    uri,
 		authMethod:             conf.Credentials.AuthMethod,
+		userAgent:              userAgent,
 		cred:                   *conf.Credentials,
+		customAuthHeader:       conf.CustomAuthHeader,
 		OptionalMetrics:        conf.ExporterRuntimeConfig.OptionalMetrics,
 		accessFederationTarget: conf.AccessFederationTarget,
+		maxPages:               conf.ArtiMaxPages,
+		dockerRepos:            conf.DockerRepos,
+		dockerConcurrency:      dockerConcurrency,
+		storageCalculate:       conf.StorageCalculate,
+		storageCalcTimeout:     conf.StorageCalculateTimeout,
+		federationTimeout:      conf.FederationTimeout,
 		client:                 client,
 		logger:                 logger,
 		responseCache:          responseCache,
+		circuitBreaker:         NewCircuitBreaker(conf.CircuitBreakerThreshold, conf.CircuitBreakerCooldown),
+		globalRetries:          conf.MaxRetries,
+		subsystemRetries:       conf.SubsystemRetries,
+		retryWait:              defaultRetryWait,
+		limiter:                newRequestLimiter(conf.MaxConcurrentRequests),
+		rtfsEnabled:            &atomic.Bool{},
+		certExpiry:             &atomic.Int64{},
+		strictJSON:             conf.StrictJSON,
+		schemaDrift:            &sync.Map{},
+		ctx:                    ctx,
+		cancel:                 cancel,
 	}
 }
 
@@ -59,19 +155,83 @@ func (c *Client) GetAccessFederationTarget() string {
 	return c.accessFederationTarget
 }
 
+// CircuitStates returns the circuit breaker state of every normalized
+// endpoint, or nil if the circuit breaker is disabled. Endpoints normalized
+// alike, e.g. those of different repositories, report their worst state.
+func (c *Client) CircuitStates() map[string]CircuitState {
+	states := c.circuitBreaker.States()
+	if states == nil {
+		return nil
+	}
+	normalized := make(map[string]CircuitState, len(states))
+	for endpoint, state := range states {
+		endpoint = normalizeEndpoint(endpoint)
+		if current, ok := normalized[endpoint]; !ok || state.worseThan(current) {
+			normalized[endpoint] = state
+		}
+	}
+	return normalized
+}
+
+// NodeClient returns a client which sends its requests to the given base URI
+// of a single HA node. It shares the HTTP client, cache, credentials and
+// concurrent request limit with c but has no circuit breaker, as the circuits are tracked per endpoint only.
+// The certificate of the node isn't tracked either.
+func (c *Client) NodeClient(baseURI string) *Client {
+	nodeClient := *c
+	nodeClient.URI = baseURI
+	nodeClient.circuitBreaker = nil
+	nodeClient.certExpiry = &atomic.Int64{}
+	return &nodeClient
+}
+
+// CertExpiry returns the expiry time of the TLS certificate presented by
+// Artifactory in the last HTTPS response. The second return value is false
+// if the scrape URI isn't HTTPS or no response was received yet.
+func (c *Client) CertExpiry() (time.Time, bool) {
+	if !strings.HasPrefix(strings.ToLower(c.URI), "https://") {
+		return time.Time{}, false
+	}
+	expiry := c.certExpiry.Load()
+	if expiry == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(expiry, 0), true
+}
+
+// recordCertExpiry remembers the expiry time of the leaf certificate of an
+// HTTPS response.
+func (c *Client) recordCertExpiry(resp *http.Response) {
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return
+	}
+	c.certExpiry.Store(resp.TLS.PeerCertificates[0].NotAfter.Unix())
+}
+
+// CancelRequests aborts all in-flight requests to Artifactory.
+// The client can't be used to make further requests afterwards.
+func (c *Client) CancelRequests() {
+	c.cancel()
+}
+
 // FetchHTTPWithContext makes a GET request to the Artifactory API with a context-aware timeout.
 func (c *Client) FetchHTTPWithContext(ctx context.Context, endpoint string) (*ApiResponse, error) {
-	fullURL := fmt.Sprintf("%s/api/%s", c.URI, endpoint)
+	fullURL := c.apiURL(endpoint)
 	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if err := c.setAuthHeader(req); err != nil {
+		return nil, err
+	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	c.recordCertExpiry(resp)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -98,7 +258,7 @@ func (c *Client) FetchBackgroundTasks() ([]BackgroundTask, error) {
 		return nil, err
 	}
 
-	if err := json.Unmarshal(resp.Body, &tasksResponse); err != nil {
+	if err := c.unmarshalJSON(backgroundTasksEndpoint, resp.Body, &tasksResponse); err != nil {
 		c.logger.Error("There was an issue when trying to unmarshal background tasks response")
 		return nil, &UnmarshalError{
 			message:  err.Error(),
@@ -115,4 +275,43 @@ type BackgroundTask struct {
 	State       string `json:"state"`
 	Description string `json:"description"`
 	NodeID      string `json:"nodeId"`
+	Started     string `json:"started"`
+}
+
+// IsRunning returns true if the task is currently running.
+func (t BackgroundTask) IsRunning() bool {
+	return strings.EqualFold(t.State, "running")
+}
+
+// IsConversion returns true if the task converts or migrates data,
+// e.g. the database conversions run after an upgrade.
+func (t BackgroundTask) IsConversion() bool {
+	taskType := strings.ToLower(t.Type)
+	return strings.Contains(taskType, "conversion") || strings.Contains(taskType, "migration")
+}
+
+// IsStorageCalculation returns true if the task calculates the storage summary.
+func (t BackgroundTask) IsStorageCalculation() bool {
+	taskType := strings.ToLower(t.Type)
+	return strings.Contains(taskType, "storagesummary") || strings.Contains(taskType, "storageinfo")
+}
+
+// IsPending returns true if the task is scheduled or running.
+func (t BackgroundTask) IsPending() bool {
+	return t.IsRunning() || strings.EqualFold(t.State, "scheduled")
+}
+
+// RunningSeconds returns how long a running task has been running at now,
+// floored at 0 if the clock of Artifactory is ahead of the exporter's. The
+// second return value is false if the task isn't running or its start time is
+// unknown.
+func (t BackgroundTask) RunningSeconds(now time.Time) (float64, bool) {
+	if !t.IsRunning() || t.Started == "" {
+		return 0, false
+	}
+	started, err := parseArtiTime(t.Started)
+	if err != nil {
+		return 0, false
+	}
+	return max(now.Sub(started).Seconds(), 0), true
 }
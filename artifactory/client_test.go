@@ -2,12 +2,20 @@ package artifactory
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/common/version"
+
 	"github.com/peimanja/artifactory_exporter/config"
 	l "github.com/peimanja/artifactory_exporter/logger"
 )
@@ -70,6 +78,97 @@ func TestNewClientWithCache(t *testing.T) {
 	}
 }
 
+func TestNewClientUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "artifactory.sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	var requestPath string
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.Path
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		w.Write([]byte("OK"))
+	})}
+	go server.Serve(ln)
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = "unix://" + socket
+	client := NewClient(conf)
+
+	health, err := client.FetchHealth()
+	if err != nil {
+		t.Fatalf("FetchHealth() error = %v", err)
+	}
+	if !health.Healthy {
+		t.Error("FetchHealth() Healthy = false, want true")
+	}
+	if health.NodeId != "test-node" {
+		t.Errorf("FetchHealth() NodeId = %s, want test-node", health.NodeId)
+	}
+	if requestPath != "/artifactory/api/system/ping" {
+		t.Errorf("Request path = %s, want /artifactory/api/system/ping", requestPath)
+	}
+}
+
+func TestFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/elsewhere/ping" {
+			w.Write([]byte("OK"))
+			return
+		}
+		http.Redirect(w, r, "/elsewhere/ping", http.StatusFound)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		followRedirects bool
+		expectError     bool
+	}{
+		{
+			name:            "Follow redirects",
+			followRedirects: true,
+		},
+		{
+			name:            "Don't follow redirects",
+			followRedirects: false,
+			expectError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL + "/artifactory"
+			conf.ArtiNoFollowRedirects = !tt.followRedirects
+			client := NewClient(conf)
+
+			health, err := client.FetchHealth()
+			if !tt.expectError {
+				if err != nil {
+					t.Fatalf("FetchHealth() error = %v", err)
+				}
+				if !health.Healthy {
+					t.Error("FetchHealth() Healthy = false, want true")
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("FetchHealth() error = %v, want APIError", err)
+			}
+			if apiErr.status != http.StatusFound {
+				t.Errorf("APIError status = %d, want %d", apiErr.status, http.StatusFound)
+			}
+			if !strings.Contains(apiErr.Error(), "/elsewhere/ping") {
+				t.Errorf("APIError = %v, want the redirect location", apiErr)
+			}
+		})
+	}
+}
+
 func TestGetAccessFederationTarget(t *testing.T) {
 	conf := createTestConfig()
 	conf.AccessFederationTarget = "https://example.com"
@@ -114,6 +213,159 @@ func TestFetchHTTPWithContext(t *testing.T) {
 	}
 }
 
+func TestAuthHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials config.Credentials
+		header      string
+		expected    string
+	}{
+		{
+			name:        "Basic auth",
+			credentials: config.Credentials{AuthMethod: "userPass", Username: "test", Password: "test"},
+			header:      "Authorization",
+			expected:    "Basic dGVzdDp0ZXN0",
+		},
+		{
+			name:        "Access token",
+			credentials: config.Credentials{AuthMethod: "accessToken", AccessToken: "token"},
+			header:      "Authorization",
+			expected:    "Bearer token",
+		},
+		{
+			name:        "API key",
+			credentials: config.Credentials{AuthMethod: "apiKey", APIKey: "key"},
+			header:      "X-JFrog-Art-Api",
+			expected:    "key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				headers = append(headers, r.Header.Clone())
+				w.Write([]byte("OK"))
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			conf.Credentials = &tt.credentials
+			client := NewClient(conf)
+
+			if _, err := client.FetchHTTP(pingEndpoint); err != nil {
+				t.Fatalf("FetchHTTP() error = %v", err)
+			}
+			if _, err := client.FetchHTTPWithContext(context.Background(), pingEndpoint); err != nil {
+				t.Fatalf("FetchHTTPWithContext() error = %v", err)
+			}
+			for i, header := range headers {
+				if got := header.Get(tt.header); got != tt.expected {
+					t.Errorf("Request %d: %s header = %q, want %q", i, tt.header, got, tt.expected)
+				}
+				if tt.header != "Authorization" && header.Get("Authorization") != "" {
+					t.Errorf("Request %d: unexpected Authorization header %q", i, header.Get("Authorization"))
+				}
+			}
+		})
+	}
+}
+
+func TestAnonymousAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("X-JFrog-Art-Api") != "" {
+			t.Errorf("Unexpected credentials sent to %s", r.URL.Path)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"status":401,"message":"Unauthorized"}]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/api/system/ping":
+			w.Write([]byte("OK"))
+		case "/api/system/version":
+			w.Write([]byte(`{"version":"7.77.3","revision":"77703900"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.Credentials = &config.Credentials{AuthMethod: "anonymous"}
+	client := NewClient(conf)
+
+	health, err := client.FetchHealth()
+	if err != nil {
+		t.Fatalf("FetchHealth() error = %v", err)
+	}
+	if !health.Healthy {
+		t.Error("FetchHealth() reported Artifactory as unhealthy")
+	}
+	buildInfo, err := client.FetchBuildInfo()
+	if err != nil {
+		t.Fatalf("FetchBuildInfo() error = %v", err)
+	}
+	if buildInfo.Version != "7.77.3" {
+		t.Errorf("Version = %q, want %q", buildInfo.Version, "7.77.3")
+	}
+	if _, err := client.FetchHTTPWithContext(context.Background(), pingEndpoint); err != nil {
+		t.Fatalf("FetchHTTPWithContext() error = %v", err)
+	}
+}
+
+func TestCustomAuthHeader(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        config.CustomAuthHeader
+		authorization string
+	}{
+		{
+			name:          "In addition to the auth method",
+			header:        config.CustomAuthHeader{Name: "X-Auth-Token", Value: "token"},
+			authorization: "Bearer token",
+		},
+		{
+			name:   "Instead of the auth method",
+			header: config.CustomAuthHeader{Name: "X-Auth-Token", Value: "token", Replace: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				headers = append(headers, r.Header.Clone())
+				w.Write([]byte("OK"))
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			conf.Credentials = &config.Credentials{AuthMethod: "accessToken", AccessToken: "token"}
+			conf.CustomAuthHeader = &tt.header
+			client := NewClient(conf)
+
+			if _, err := client.FetchHTTP(pingEndpoint); err != nil {
+				t.Fatalf("FetchHTTP() error = %v", err)
+			}
+			if _, err := client.FetchHTTPWithContext(context.Background(), pingEndpoint); err != nil {
+				t.Fatalf("FetchHTTPWithContext() error = %v", err)
+			}
+			for i, header := range headers {
+				if got := header.Get("X-Auth-Token"); got != "token" {
+					t.Errorf("Request %d: X-Auth-Token header = %q, want %q", i, got, "token")
+				}
+				if got := header.Get("Authorization"); got != tt.authorization {
+					t.Errorf("Request %d: Authorization header = %q, want %q", i, got, tt.authorization)
+				}
+			}
+		})
+	}
+}
+
 func TestFetchHTTPWithContextTimeout(t *testing.T) {
 	// Create a slow server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -137,6 +389,34 @@ func TestFetchHTTPWithContextTimeout(t *testing.T) {
 	}
 }
 
+func TestCancelRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		client.CancelRequests()
+	}()
+
+	start := time.Now()
+	_, err := client.FetchHTTP("system/ping")
+	if err == nil {
+		t.Error("Expected cancellation error, but got none")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Request was not cancelled promptly, took %v", elapsed)
+	}
+}
+
 func TestFetchBackgroundTasks(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -280,6 +560,102 @@ func TestBackgroundTaskStructure(t *testing.T) {
 	}
 }
 
+func TestBackgroundTaskRunningSeconds(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		task            BackgroundTask
+		expectPresent   bool
+		expectedSeconds float64
+	}{
+		{
+			name:            "Running task",
+			task:            BackgroundTask{State: "running", Started: "2024-05-01T11:30:00.000Z"},
+			expectPresent:   true,
+			expectedSeconds: 1800,
+		},
+		{
+			name:            "Start time ahead of the clock",
+			task:            BackgroundTask{State: "running", Started: "2024-05-01T12:00:05.000Z"},
+			expectPresent:   true,
+			expectedSeconds: 0,
+		},
+		{
+			name:          "Running task without start time",
+			task:          BackgroundTask{State: "running"},
+			expectPresent: false,
+		},
+		{
+			name:          "Scheduled task",
+			task:          BackgroundTask{State: "scheduled", Started: "2024-05-01T11:30:00.000Z"},
+			expectPresent: false,
+		},
+		{
+			name:          "Invalid start time",
+			task:          BackgroundTask{State: "running", Started: "an hour ago"},
+			expectPresent: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seconds, ok := tt.task.RunningSeconds(now)
+			if ok != tt.expectPresent {
+				t.Fatalf("RunningSeconds() present = %v, want %v", ok, tt.expectPresent)
+			}
+			if seconds != tt.expectedSeconds {
+				t.Errorf("RunningSeconds() = %v, want %v", seconds, tt.expectedSeconds)
+			}
+		})
+	}
+}
+
+func TestBackgroundTaskConversion(t *testing.T) {
+	tests := []struct {
+		name             string
+		task             BackgroundTask
+		expectConversion bool
+		expectPending    bool
+	}{
+		{
+			name:             "Running conversion",
+			task:             BackgroundTask{Type: "org.artifactory.storage.db.conversion.DbConversionJob", State: "running"},
+			expectConversion: true,
+			expectPending:    true,
+		},
+		{
+			name:             "Scheduled migration",
+			task:             BackgroundTask{Type: "org.artifactory.migration.Sha256MigrationJob", State: "scheduled"},
+			expectConversion: true,
+			expectPending:    true,
+		},
+		{
+			name:             "Stopped migration",
+			task:             BackgroundTask{Type: "org.artifactory.migration.Sha256MigrationJob", State: "stopped"},
+			expectConversion: true,
+			expectPending:    false,
+		},
+		{
+			name:             "Other task",
+			task:             BackgroundTask{Type: "org.artifactory.repo.cleanup.ArtifactCleanupJob", State: "running"},
+			expectConversion: false,
+			expectPending:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.IsConversion(); got != tt.expectConversion {
+				t.Errorf("IsConversion() = %v, want %v", got, tt.expectConversion)
+			}
+			if got := tt.task.IsPending(); got != tt.expectPending {
+				t.Errorf("IsPending() = %v, want %v", got, tt.expectPending)
+			}
+		})
+	}
+}
+
 func TestClientConfiguration(t *testing.T) {
 	t.Run("SSL verification enabled", func(t *testing.T) {
 		conf := createTestConfig()
@@ -320,6 +696,67 @@ func TestClientConfiguration(t *testing.T) {
 	})
 }
 
+func TestClientMinTLSVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	server.TLS = &tls.Config{
+		MinVersion: tls.VersionTLS11,
+		MaxVersion: tls.VersionTLS11,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		minTLSVersion uint16
+	}{
+		{
+			name:          "Default minimum version",
+			minTLSVersion: 0,
+		},
+		{
+			name:          "Minimum TLS 1.2",
+			minTLSVersion: tls.VersionTLS12,
+		},
+		{
+			name:          "Minimum TLS 1.3",
+			minTLSVersion: tls.VersionTLS13,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			conf.MinTLSVersion = tt.minTLSVersion
+			client := NewClient(conf)
+
+			if _, err := client.FetchHTTP("system/ping"); err == nil {
+				t.Error("Expected TLS handshake to fail against a TLS 1.1 server, but got none")
+			}
+		})
+	}
+}
+
+func TestClientMinTLSVersionCompatibleServer(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.MinTLSVersion = tls.VersionTLS13
+	client := NewClient(conf)
+
+	if _, err := client.FetchHTTP("system/ping"); err != nil {
+		t.Errorf("FetchHTTP() error = %v", err)
+	}
+}
+
 func TestOptionalMetricsConfiguration(t *testing.T) {
 	conf := createTestConfig()
 	conf.ExporterRuntimeConfig.OptionalMetrics = config.OptionalMetrics{
@@ -440,3 +877,268 @@ func TestClientHTTPHeaders(t *testing.T) {
 		t.Error("User-Agent header should not be empty")
 	}
 }
+
+func TestClientCACertPEM(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	tests := []struct {
+		name        string
+		caCertPEM   string
+		expectError bool
+	}{
+		{
+			name:      "Trusted via inline PEM",
+			caCertPEM: caPEM,
+		},
+		{
+			name:        "Untrusted without inline PEM",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			conf.ArtiSSLVerify = true
+			conf.CACertPEM = tt.caCertPEM
+			client := NewClient(conf)
+
+			_, err := client.FetchHTTP("system/ping")
+			if tt.expectError && err == nil {
+				t.Error("Expected certificate verification error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("FetchHTTP() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestClientUserAgent(t *testing.T) {
+	tests := []struct {
+		name              string
+		userAgent         string
+		expectedUserAgent string
+	}{
+		{
+			name:              "Default User-Agent",
+			expectedUserAgent: "artifactory_exporter/" + version.Version,
+		},
+		{
+			name:              "Custom User-Agent",
+			userAgent:         "my-exporter/1.0",
+			expectedUserAgent: "my-exporter/1.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userAgent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userAgent = r.UserAgent()
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			conf.UserAgent = tt.userAgent
+			client := NewClient(conf)
+
+			if _, err := client.FetchHTTP("system/version"); err != nil {
+				t.Fatalf("FetchHTTP() error = %v", err)
+			}
+			if userAgent != tt.expectedUserAgent {
+				t.Errorf("User-Agent = %q, want %q", userAgent, tt.expectedUserAgent)
+			}
+		})
+	}
+}
+
+func TestURLJoining(t *testing.T) {
+	tests := []struct {
+		name        string
+		scrapeURI   string
+		expectedAPI string
+		expectedGet string
+	}{
+		{
+			name:        "Root without trailing slash",
+			scrapeURI:   "https://host/artifactory",
+			expectedAPI: "https://host/artifactory/api/federation/status/mirrorsLag",
+			expectedGet: "https://host/access/api/v1/system/ping",
+		},
+		{
+			name:        "Root with trailing slash",
+			scrapeURI:   "https://host/artifactory/",
+			expectedAPI: "https://host/artifactory/api/federation/status/mirrorsLag",
+			expectedGet: "https://host/access/api/v1/system/ping",
+		},
+		{
+			name:        "Subpath without trailing slash",
+			scrapeURI:   "https://host/jfrog/artifactory",
+			expectedAPI: "https://host/jfrog/artifactory/api/federation/status/mirrorsLag",
+			expectedGet: "https://host/jfrog/access/api/v1/system/ping",
+		},
+		{
+			name:        "Subpath with trailing slash",
+			scrapeURI:   "https://host/jfrog/artifactory/",
+			expectedAPI: "https://host/jfrog/artifactory/api/federation/status/mirrorsLag",
+			expectedGet: "https://host/jfrog/access/api/v1/system/ping",
+		},
+		{
+			name:        "IPv6 literal",
+			scrapeURI:   "http://[::1]:8081/artifactory",
+			expectedAPI: "http://[::1]:8081/artifactory/api/federation/status/mirrorsLag",
+			expectedGet: "http://[::1]:8081/access/api/v1/system/ping",
+		},
+		{
+			name:        "IPv6 literal with subpath and trailing slash",
+			scrapeURI:   "http://[2001:db8::1]/jfrog/artifactory/",
+			expectedAPI: "http://[2001:db8::1]/jfrog/artifactory/api/federation/status/mirrorsLag",
+			expectedGet: "http://[2001:db8::1]/jfrog/access/api/v1/system/ping",
+		},
+		{
+			name:        "Subpath with multiple trailing slashes",
+			scrapeURI:   "https://host/jfrog/artifactory//",
+			expectedAPI: "https://host/jfrog/artifactory/api/federation/status/mirrorsLag",
+			expectedGet: "https://host/jfrog/access/api/v1/system/ping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = tt.scrapeURI
+			client := NewClient(conf)
+
+			if got := client.apiURL("federation/status/mirrorsLag"); got != tt.expectedAPI {
+				t.Errorf("apiURL() = %s, want %s", got, tt.expectedAPI)
+			}
+			if got := client.platformURL("access/api/v1/system/ping"); got != tt.expectedGet {
+				t.Errorf("platformURL() = %s, want %s", got, tt.expectedGet)
+			}
+		})
+	}
+}
+
+func TestFetchHTTPIPv6(t *testing.T) {
+	ln, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %v", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifactory/api/system/ping":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		case "/router/api/v1/topology/health":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"nodes":[{"id":"art1","state":"HEALTHY"}]}`))
+		default:
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	server.Listener.Close()
+	server.Listener = ln
+	server.Start()
+	defer server.Close()
+
+	if !strings.HasPrefix(server.URL, "http://[::1]:") {
+		t.Fatalf("Server URL = %s, want a bracketed IPv6 literal", server.URL)
+	}
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL + "/artifactory/"
+	client := NewClient(conf)
+
+	health, err := client.FetchHealth()
+	if err != nil {
+		t.Fatalf("FetchHealth() error = %v", err)
+	}
+	if !health.Healthy {
+		t.Error("FetchHealth() = unhealthy, want healthy")
+	}
+	haNodes, err := client.FetchHANodes()
+	if err != nil {
+		t.Fatalf("FetchHANodes() error = %v", err)
+	}
+	if len(haNodes.Nodes) != 1 {
+		t.Errorf("FetchHANodes() returned %d nodes, want 1", len(haNodes.Nodes))
+	}
+}
+
+func TestFetchHTTPSubpath(t *testing.T) {
+	for _, suffix := range []string{"", "/"} {
+		t.Run("suffix "+suffix, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/jfrog/artifactory/api/federation/status/mirrorsLag" {
+					t.Errorf("Unexpected request path %s", r.URL.Path)
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL + "/jfrog/artifactory" + suffix
+			client := NewClient(conf)
+
+			if _, err := client.FetchHTTP("federation/status/mirrorsLag"); err != nil {
+				t.Fatalf("FetchHTTP() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestClientCertExpiry(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}))
+	defer tlsServer.Close()
+	plainServer := createTestServer("OK", http.StatusOK)
+	defer plainServer.Close()
+
+	t.Run("HTTPS scrape URI", func(t *testing.T) {
+		conf := createTestConfig()
+		conf.ArtiScrapeURI = tlsServer.URL
+		client := NewClient(conf)
+
+		if _, ok := client.CertExpiry(); ok {
+			t.Error("CertExpiry() reported an expiry before any request")
+		}
+		if _, err := client.FetchHTTP("system/ping"); err != nil {
+			t.Fatalf("FetchHTTP() error = %v", err)
+		}
+		expiry, ok := client.CertExpiry()
+		if !ok {
+			t.Fatal("CertExpiry() reported no expiry after HTTPS request")
+		}
+		expected := tlsServer.Certificate().NotAfter
+		if expiry.Unix() != expected.Unix() {
+			t.Errorf("CertExpiry() = %v, want %v", expiry, expected)
+		}
+	})
+
+	t.Run("HTTP scrape URI", func(t *testing.T) {
+		conf := createTestConfig()
+		conf.ArtiScrapeURI = plainServer.URL
+		client := NewClient(conf)
+
+		if _, err := client.FetchHTTP("system/ping"); err != nil {
+			t.Fatalf("FetchHTTP() error = %v", err)
+		}
+		if _, ok := client.CertExpiry(); ok {
+			t.Error("CertExpiry() reported an expiry for HTTP scrape URI")
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package artifactory
+
+import (
+	"encoding/xml"
+)
+
+// ConfigDescriptor represents the general settings of the Artifactory
+// configuration descriptor.
+type ConfigDescriptor struct {
+	UrlBase    string `xml:"urlBase"`
+	ServerName string `xml:"serverName"`
+	NodeId     string `xml:"-"`
+}
+
+// FetchConfigDescriptor makes the API call to the system configuration
+// endpoint and returns the general settings of the configuration descriptor.
+// Reading it requires admin permissions, otherwise the returned error matches
+// ErrAdminRequired.
+func (c *Client) FetchConfigDescriptor() (ConfigDescriptor, error) {
+	var descriptor ConfigDescriptor
+	c.logger.Debug("Fetching configuration descriptor")
+	resp, err := c.FetchHTTP(systemConfigurationEndpoint)
+	if err != nil {
+		return descriptor, err
+	}
+	descriptor.NodeId = resp.NodeId
+
+	if err := xml.Unmarshal(resp.Body, &descriptor); err != nil {
+		c.logger.Error("There was an issue when trying to unmarshal configuration descriptor response")
+		return descriptor, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: systemConfigurationEndpoint,
+		}
+	}
+	return descriptor, nil
+}
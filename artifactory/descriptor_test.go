@@ -0,0 +1,118 @@
+package artifactory
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchConfigDescriptor(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		response    string
+		expected    ConfigDescriptor
+		expectError error
+	}{
+		{
+			name:   "Descriptor readable",
+			status: http.StatusOK,
+			response: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<config xmlns="http://artifactory.jfrog.org/xsd/3.1.17">
+    <serverName>artifactory-prod</serverName>
+    <urlBase>https://artifactory.example.com</urlBase>
+    <backups/>
+</config>`,
+			expected: ConfigDescriptor{UrlBase: "https://artifactory.example.com", ServerName: "artifactory-prod", NodeId: "test-node"},
+		},
+		{
+			name:     "Base URL not set",
+			status:   http.StatusOK,
+			response: `<config xmlns="http://artifactory.jfrog.org/xsd/3.1.17"><serverName>artifactory</serverName></config>`,
+			expected: ConfigDescriptor{ServerName: "artifactory", NodeId: "test-node"},
+		},
+		{
+			name:        "Admin permissions required",
+			status:      http.StatusForbidden,
+			response:    `{"errors":[{"status":403,"message":"Forbidden"}]}`,
+			expectError: ErrAdminRequired,
+		},
+		{
+			name:        "Unauthorized",
+			status:      http.StatusUnauthorized,
+			response:    `{"errors":[{"status":401,"message":"Unauthorized"}]}`,
+			expectError: ErrAdminRequired,
+		},
+		{
+			name:        "Server error",
+			status:      http.StatusInternalServerError,
+			response:    `{"errors":[{"status":500,"message":"Internal Server Error"}]}`,
+			expectError: &APIError{},
+		},
+		{
+			name:        "Invalid response",
+			status:      http.StatusOK,
+			response:    `{"errors": []}`,
+			expectError: &UnmarshalError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/system/configuration" {
+					t.Errorf("Unexpected request to %s", r.URL.Path)
+				}
+				w.Header().Set("X-Artifactory-Node-Id", "test-node")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			client := NewClient(conf)
+
+			descriptor, err := client.FetchConfigDescriptor()
+			if tt.expectError != nil {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if tt.expectError == ErrAdminRequired && !errors.Is(err, ErrAdminRequired) {
+					t.Errorf("FetchConfigDescriptor() error = %v, want %v", err, ErrAdminRequired)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchConfigDescriptor() error = %v", err)
+			}
+			if descriptor != tt.expected {
+				t.Errorf("FetchConfigDescriptor() = %+v, want %+v", descriptor, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFetchConfigDescriptorCircuitBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":[{"status":403,"message":"Forbidden"}]}`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.CircuitBreakerThreshold = 2
+	conf.CircuitBreakerCooldown = time.Minute
+	client := NewClient(conf)
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.FetchConfigDescriptor(); !errors.Is(err, ErrAdminRequired) {
+			t.Fatalf("FetchConfigDescriptor() error = %v, want %v", err, ErrAdminRequired)
+		}
+	}
+	if state := client.CircuitStates()[systemConfigurationEndpoint]; state != CircuitClosed {
+		t.Errorf("Circuit state after denied requests = %v, want %v", state, CircuitClosed)
+	}
+}
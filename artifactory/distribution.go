@@ -0,0 +1,47 @@
+package artifactory
+
+import "errors"
+
+const releaseBundlesEndpoint = "distribution/api/v1/release_bundle"
+
+// ReleaseBundleVersion represents a single version of a release bundle in the
+// response of the Distribution release bundles endpoint
+type ReleaseBundleVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	State   string `json:"state"`
+}
+
+// ReleaseBundles represents the versions of all release bundles. Available is
+// false if JFrog Distribution isn't installed.
+type ReleaseBundles struct {
+	Versions  []ReleaseBundleVersion
+	Available bool
+	NodeId    string
+}
+
+// FetchReleaseBundles makes the API call to the JFrog Distribution release
+// bundles endpoint and returns the versions of all release bundles.
+func (c *Client) FetchReleaseBundles() (ReleaseBundles, error) {
+	var releaseBundles ReleaseBundles
+	c.logger.Debug("Fetching release bundles")
+	resp, err := c.GetHTTP(releaseBundlesEndpoint)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.status == 404 {
+			c.logger.Debug("JFrog Distribution not found, no release bundles available")
+			return releaseBundles, nil
+		}
+		return releaseBundles, err
+	}
+	releaseBundles.NodeId = resp.NodeId
+	if err := c.unmarshalJSON(releaseBundlesEndpoint, resp.Body, &releaseBundles.Versions); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal release bundles respond")
+		return releaseBundles, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: releaseBundlesEndpoint,
+		}
+	}
+	releaseBundles.Available = true
+	return releaseBundles, nil
+}
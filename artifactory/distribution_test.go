@@ -0,0 +1,90 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchReleaseBundles(t *testing.T) {
+	tests := []struct {
+		name             string
+		responseBody     string
+		responseCode     int
+		expectError      bool
+		expectAvailable  bool
+		expectedVersions []ReleaseBundleVersion
+	}{
+		{
+			name: "Release bundles",
+			responseBody: `[
+				{"name": "webapp", "version": "1.0.0", "state": "SIGNED", "created": "2024-05-01T10:00:00.000Z"},
+				{"name": "webapp", "version": "1.1.0", "state": "OPEN", "created": "2024-06-01T10:00:00.000Z"},
+				{"name": "backend", "version": "2.3.0", "state": "SIGNED", "created": "2024-06-02T10:00:00.000Z"}
+			]`,
+			responseCode:    http.StatusOK,
+			expectAvailable: true,
+			expectedVersions: []ReleaseBundleVersion{
+				{Name: "webapp", Version: "1.0.0", State: "SIGNED"},
+				{Name: "webapp", Version: "1.1.0", State: "OPEN"},
+				{Name: "backend", Version: "2.3.0", State: "SIGNED"},
+			},
+		},
+		{
+			name:            "No release bundles",
+			responseBody:    `[]`,
+			responseCode:    http.StatusOK,
+			expectAvailable: true,
+		},
+		{
+			name:         "Distribution not installed",
+			responseBody: `{"errors":[{"status":404,"message":"Not Found"}]}`,
+			responseCode: http.StatusNotFound,
+		},
+		{
+			name:         "Server error",
+			responseBody: `{"errors":[{"status":500,"message":"Internal Server Error"}]}`,
+			responseCode: http.StatusInternalServerError,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/distribution/api/v1/release_bundle" {
+					t.Errorf("Expected request to /distribution/api/v1/release_bundle, got %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.responseCode)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL + "/artifactory"
+			client := NewClient(conf)
+
+			releaseBundles, err := client.FetchReleaseBundles()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchReleaseBundles() error = %v", err)
+			}
+			if releaseBundles.Available != tt.expectAvailable {
+				t.Errorf("Available = %v, want %v", releaseBundles.Available, tt.expectAvailable)
+			}
+			if len(releaseBundles.Versions) != len(tt.expectedVersions) {
+				t.Fatalf("FetchReleaseBundles() returned %d versions, want %d", len(releaseBundles.Versions), len(tt.expectedVersions))
+			}
+			for i, expected := range tt.expectedVersions {
+				if releaseBundles.Versions[i] != expected {
+					t.Errorf("Versions[%d] = %+v, want %+v", i, releaseBundles.Versions[i], expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package artifactory
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sync"
+)
+
+const (
+	dockerCatalogEndpoint = "docker/%s/v2/_catalog"
+	dockerTagsEndpoint    = "docker/%s/v2/%s/tags/list"
+	dockerPageSize        = 1000
+)
+
+// DockerRepoStats represents the number of images and tags in a Docker repository
+type DockerRepoStats struct {
+	RepoKey string
+	Images  int
+	Tags    int
+	NodeId  string
+}
+
+type dockerCatalog struct {
+	Repositories []string `json:"repositories"`
+}
+
+type dockerTags struct {
+	Tags []string `json:"tags"`
+}
+
+// fetchDockerList follows the n/last pagination of the Docker registry API
+// and returns the items of all pages.
+func (c *Client) fetchDockerList(endpoint string, items func(body []byte) ([]string, error)) ([]string, string, error) {
+	var all []string
+	var nodeId string
+	last := ""
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("%s?n=%d", endpoint, dockerPageSize)
+		if last != "" {
+			path += "&last=" + url.QueryEscape(last)
+		}
+		resp, err := c.FetchHTTP(path)
+		if err != nil {
+			return nil, nodeId, err
+		}
+		nodeId = resp.NodeId
+		pageItems, err := items(resp.Body)
+		if err != nil {
+			return nil, nodeId, &UnmarshalError{
+				message:  err.Error(),
+				endpoint: path,
+			}
+		}
+		all = append(all, pageItems...)
+		if len(pageItems) < dockerPageSize {
+			return all, nodeId, nil
+		}
+		if c.maxPages > 0 && page >= c.maxPages {
+			c.logger.Warn(
+				"Reached maximum number of pages, results may be incomplete",
+				"endpoint", endpoint,
+				"max_pages", c.maxPages,
+			)
+			return all, nodeId, nil
+		}
+		last = pageItems[len(pageItems)-1]
+	}
+}
+
+func (c *Client) fetchDockerImages(repoKey string) ([]string, string, error) {
+	endpoint := fmt.Sprintf(dockerCatalogEndpoint, url.PathEscape(repoKey))
+	return c.fetchDockerList(endpoint, func(body []byte) ([]string, error) {
+		var catalog dockerCatalog
+		err := c.unmarshalJSON(endpoint, body, &catalog)
+		return catalog.Repositories, err
+	})
+}
+
+func (c *Client) fetchDockerTags(repoKey string, image string) ([]string, error) {
+	endpoint := fmt.Sprintf(dockerTagsEndpoint, url.PathEscape(repoKey), image)
+	tags, _, err := c.fetchDockerList(endpoint, func(body []byte) ([]string, error) {
+		var tags dockerTags
+		err := c.unmarshalJSON(endpoint, body, &tags)
+		return tags.Tags, err
+	})
+	return tags, err
+}
+
+// FetchDockerRepoStats counts the images and tags of the configured Docker repositories
+// using the Docker registry API. The tags of up to the configured number of images are
+// fetched concurrently. Repositories which couldn't be fetched are left out of the
+// result and their errors are returned joined. If only is given, only the
+// configured repositories among them are fetched.
+func (c *Client) FetchDockerRepoStats(only ...string) ([]DockerRepoStats, error) {
+	var stats []DockerRepoStats
+	var errs []error
+	for _, repoKey := range c.dockerRepos {
+		if len(only) > 0 && !slices.Contains(only, repoKey) {
+			continue
+		}
+		c.logger.Debug(
+			"Fetching Docker repository stats",
+			"repo", repoKey,
+		)
+		repoStats, err := c.fetchDockerRepoStats(repoKey)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("docker repository %s: %w", repoKey, err))
+			continue
+		}
+		stats = append(stats, repoStats)
+	}
+	return stats, errors.Join(errs...)
+}
+
+func (c *Client) fetchDockerRepoStats(repoKey string) (DockerRepoStats, error) {
+	stats := DockerRepoStats{RepoKey: repoKey}
+	images, nodeId, err := c.fetchDockerImages(repoKey)
+	if err != nil {
+		return stats, err
+	}
+	stats.Images = len(images)
+	stats.NodeId = nodeId
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, c.dockerConcurrency)
+	for _, image := range images {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(image string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			tags, err := c.fetchDockerTags(repoKey, image)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			stats.Tags += len(tags)
+		}(image)
+	}
+	wg.Wait()
+	return stats, firstErr
+}
@@ -0,0 +1,99 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// paginate returns the page of items after last as the Docker registry API does.
+func paginate(items []string, r *http.Request) []string {
+	n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+	start := 0
+	if last := r.URL.Query().Get("last"); last != "" {
+		for i, item := range items {
+			if item == last {
+				start = i + 1
+			}
+		}
+	}
+	end := start + n
+	if n == 0 || end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+func TestFetchDockerRepoStats(t *testing.T) {
+	images := []string{"app/backend", "app/frontend", "nginx"}
+	manyTags := make([]string, 2*dockerPageSize+10)
+	for i := range manyTags {
+		manyTags[i] = fmt.Sprintf("1.0.%04d", i)
+	}
+	tags := map[string][]string{
+		"app/backend":  {"latest", "1.0", "1.1"},
+		"app/frontend": {"latest"},
+		"nginx":        manyTags,
+	}
+
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		path := strings.TrimPrefix(r.URL.Path, "/api/docker/")
+		switch {
+		case path == "docker-local/v2/_catalog":
+			json.NewEncoder(w).Encode(map[string][]string{"repositories": paginate(images, r)})
+		case strings.HasPrefix(path, "docker-local/v2/") && strings.HasSuffix(path, "/tags/list"):
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				max := maxInFlight.Load()
+				if current <= max || maxInFlight.CompareAndSwap(max, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			image := strings.TrimSuffix(strings.TrimPrefix(path, "docker-local/v2/"), "/tags/list")
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": image, "tags": paginate(tags[image], r)})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.DockerRepos = []string{"docker-local", "docker-missing"}
+	conf.DockerConcurrency = 2
+	client := NewClient(conf)
+
+	stats, err := client.FetchDockerRepoStats()
+	if err == nil {
+		t.Error("Expected error for missing repository but got none")
+	}
+	if len(stats) != 1 {
+		t.Fatalf("FetchDockerRepoStats() returned %d repositories, want 1", len(stats))
+	}
+	if stats[0].RepoKey != "docker-local" {
+		t.Errorf("RepoKey = %s, want docker-local", stats[0].RepoKey)
+	}
+	if stats[0].Images != 3 {
+		t.Errorf("Images = %d, want 3", stats[0].Images)
+	}
+	if expected := 4 + len(manyTags); stats[0].Tags != expected {
+		t.Errorf("Tags = %d, want %d", stats[0].Tags, expected)
+	}
+	if stats[0].NodeId != "test-node" {
+		t.Errorf("NodeId = %s, want test-node", stats[0].NodeId)
+	}
+	if max := maxInFlight.Load(); max > 2 {
+		t.Errorf("%d concurrent tags requests, want at most 2", max)
+	}
+}
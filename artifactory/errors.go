@@ -1,6 +1,25 @@
 package artifactory
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Reasons of failed requests to Artifactory, as returned by FailureReason.
+const (
+	ReasonAuth      = "auth"
+	ReasonNetwork   = "network"
+	ReasonTimeout   = "timeout"
+	ReasonHTTPError = "http_error"
+)
+
+// ErrAdminRequired matches the errors of requests denied with a 401 or 403
+// response, e.g. to endpoints requiring admin permissions the configured
+// credentials don't have. Use errors.Is to check for it.
+var ErrAdminRequired = errors.New("request requires admin permissions")
 
 // UnmarshalError is a custom Error type for unmarshal API respond body error
 type UnmarshalError struct {
@@ -34,3 +53,50 @@ func (e *APIError) apiEndpoint() string {
 func (e *APIError) apiStatus() int {
 	return e.status
 }
+
+// Is reports whether the request was denied, matching ErrAdminRequired.
+func (e *APIError) Is(target error) bool {
+	return target == ErrAdminRequired && (e.status == http.StatusUnauthorized || e.status == http.StatusForbidden)
+}
+
+// CircuitOpenError is a custom Error type for requests skipped by an open circuit breaker
+type CircuitOpenError struct {
+	endpoint string
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("Circuit open: skipping request (endpoint: %s)", e.endpoint)
+}
+
+func (e *CircuitOpenError) apiEndpoint() string {
+	return e.endpoint
+}
+
+// FailureReason classifies the error of a failed request to Artifactory as
+// an authentication failure, a timeout, a network failure or otherwise an
+// erroneous response. Requests skipped by an open circuit count as network
+// failures.
+func FailureReason(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.status == http.StatusUnauthorized || apiErr.status == http.StatusForbidden {
+			return ReasonAuth
+		}
+		return ReasonHTTPError
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ReasonTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ReasonTimeout
+		}
+		return ReasonNetwork
+	}
+	var circuitErr *CircuitOpenError
+	if errors.As(err, &circuitErr) {
+		return ReasonNetwork
+	}
+	return ReasonHTTPError
+}
@@ -1,7 +1,11 @@
 package artifactory
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"testing"
 )
 
@@ -223,3 +227,60 @@ func TestErrorInterfaceCompliance(t *testing.T) {
 		}
 	})
 }
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "Unauthorized",
+			err:      &APIError{message: "Bad credentials", status: 401},
+			expected: ReasonAuth,
+		},
+		{
+			name:     "Forbidden",
+			err:      fmt.Errorf("wrapped: %w", &APIError{message: "Forbidden", status: 403}),
+			expected: ReasonAuth,
+		},
+		{
+			name:     "Server error",
+			err:      &APIError{message: "Internal Server Error", status: 500},
+			expected: ReasonHTTPError,
+		},
+		{
+			name:     "Invalid response",
+			err:      &UnmarshalError{message: "invalid character"},
+			expected: ReasonHTTPError,
+		},
+		{
+			name:     "Deadline exceeded",
+			err:      fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+			expected: ReasonTimeout,
+		},
+		{
+			name:     "Client timeout",
+			err:      &url.Error{Op: "Get", URL: "http://localhost", Err: &net.DNSError{IsTimeout: true}},
+			expected: ReasonTimeout,
+		},
+		{
+			name:     "Connection refused",
+			err:      &url.Error{Op: "Get", URL: "http://localhost", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}},
+			expected: ReasonNetwork,
+		},
+		{
+			name:     "Circuit open",
+			err:      &CircuitOpenError{endpoint: "/api/system/ping"},
+			expected: ReasonNetwork,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reason := FailureReason(tt.err); reason != tt.expected {
+				t.Errorf("FailureReason() = %s, want %s", reason, tt.expected)
+			}
+		})
+	}
+}
@@ -1,12 +1,10 @@
 package artifactory
 
 import (
-	"context"
-	"encoding/json"
 	"errors"
+	"net/http"
 	"net/url"
 	"strings"
-	"time"
 )
 
 const federationMirrorsLagEndpoint = "federation/status/mirrorsLag"
@@ -17,13 +15,64 @@ func isRTFSEnabled(body []byte) bool {
 	return strings.Contains(string(body), "RTFS is enabled")
 }
 
-// IsFederationEnabled checks one of the federation endpoints to see if federation is enabled
-func (c *Client) IsFederationEnabled() bool {
-	_, err := c.FetchHTTP(federationUnavailableMirrorsEndpoint)
+// RTFSEnabled returns true if a federation endpoint reported RTFS to be enabled
+// since the last call to ResetRTFSEnabled. The flag is shared with node clients.
+func (c *Client) RTFSEnabled() bool {
+	return c.rtfsEnabled.Load()
+}
+
+// ResetRTFSEnabled clears the flag returned by RTFSEnabled, e.g. at the start of a scrape.
+func (c *Client) ResetRTFSEnabled() {
+	c.rtfsEnabled.Store(false)
+}
+
+// federationClient returns a client whose requests time out after the
+// federation timeout instead of the timeout of other requests. It shares
+// everything else with c.
+func (c *Client) federationClient() *Client {
+	if c.federationTimeout <= 0 || c.federationTimeout == c.client.Timeout {
+		return c
+	}
+	httpClient := *c.client
+	httpClient.Timeout = c.federationTimeout
+	federationClient := *c
+	federationClient.client = &httpClient
+	return &federationClient
+}
+
+// States of federation, as returned by FederationState.
+const (
+	FederationEnabled      = "enabled"
+	FederationRTFS         = "rtfs"
+	FederationUnauthorized = "unauthorized"
+	FederationUnavailable  = "unavailable"
+)
+
+// FederationState checks one of the federation endpoints and classifies the
+// result: federation is enabled, enabled with RTFS which doesn't report the
+// mirrors, not accessible with the configured credentials or unavailable,
+// e.g. on versions or licenses without federation. The error of the check is
+// returned for the latter two. Like the other federation requests, the check
+// times out after the federation timeout.
+func (c *Client) FederationState() (string, error) {
+	resp, err := c.federationClient().FetchHTTP(federationUnavailableMirrorsEndpoint)
 	if err != nil {
-		return false
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.status == http.StatusUnauthorized || apiErr.status == http.StatusForbidden) {
+			return FederationUnauthorized, err
+		}
+		return FederationUnavailable, err
+	}
+	if isRTFSEnabled(resp.Body) {
+		return FederationRTFS, nil
 	}
-	return true
+	return FederationEnabled, nil
+}
+
+// IsFederationEnabled checks one of the federation endpoints to see if federation is enabled
+func (c *Client) IsFederationEnabled() bool {
+	state, _ := c.FederationState()
+	return state == FederationEnabled || state == FederationRTFS
 }
 
 // MirrorLag represents single element of API respond from federation/status/mirrorsLag endpoint
@@ -59,7 +108,7 @@ func (c *Client) FetchMirrorLags() (MirrorLags, error) {
 	var mirrorLags MirrorLags
 	c.logger.Debug("Fetching mirror lags")
 
-	resp, err := c.FetchHTTP(federationMirrorsLagEndpoint)
+	resp, err := c.federationClient().FetchHTTP(federationMirrorsLagEndpoint)
 	if err != nil {
 		var apiErr *APIError
 		var urlErr *url.Error
@@ -77,11 +126,12 @@ func (c *Client) FetchMirrorLags() (MirrorLags, error) {
 	// Check if RTFS is enabled, which returns plain text instead of JSON
 	if isRTFSEnabled(resp.Body) {
 		c.logger.Debug("RTFS is enabled, mirror lags endpoint is not available")
+		c.rtfsEnabled.Store(true)
 		return mirrorLags, nil
 	}
 
 	var mirrorLagsData []MirrorLag
-	err = json.Unmarshal(resp.Body, &mirrorLagsData)
+	err = c.unmarshalJSON(federationMirrorsLagEndpoint, resp.Body, &mirrorLagsData)
 	if err != nil {
 		c.logger.Error("There was an issue when trying to unmarshal mirror lags response", "err", err)
 		return mirrorLags, err
@@ -96,10 +146,7 @@ func (c *Client) FetchUnavailableMirrors() (UnavailableMirrors, error) {
 	var unavailableMirrors UnavailableMirrors
 	c.logger.Debug("Fetching unavailable mirrors")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	resp, err := c.FetchHTTPWithContext(ctx, federationUnavailableMirrorsEndpoint)
+	resp, err := c.federationClient().FetchHTTPWithContext(c.ctx, federationUnavailableMirrorsEndpoint)
 	if err != nil {
 		var apiErr *APIError
 		var urlErr *url.Error
@@ -117,14 +164,22 @@ func (c *Client) FetchUnavailableMirrors() (UnavailableMirrors, error) {
 	// Check if RTFS is enabled, which returns plain text instead of JSON
 	if isRTFSEnabled(resp.Body) {
 		c.logger.Debug("RTFS is enabled, unavailable mirrors endpoint is not available")
+		c.rtfsEnabled.Store(true)
 		return unavailableMirrors, nil
 	}
 
-	err = json.Unmarshal(resp.Body, &unavailableMirrors)
+	err = c.unmarshalJSON(federationUnavailableMirrorsEndpoint, resp.Body, &unavailableMirrors)
 	if err != nil {
 		c.logger.Error("There was an issue when trying to unmarshal unavailable mirrors response", "err", err)
 		return unavailableMirrors, err
 	}
+	// Attribute every mirror to the node which answered, so mirrors lost by a
+	// single HA node can be told apart.
+	for i := range unavailableMirrors.UnavailableMirrors {
+		if unavailableMirrors.UnavailableMirrors[i].NodeId == "" {
+			unavailableMirrors.UnavailableMirrors[i].NodeId = resp.NodeId
+		}
+	}
 
 	return unavailableMirrors, nil
 }
@@ -12,16 +12,16 @@ import (
 
 func createFederationTestConfig() *config.Config {
 	return &config.Config{
-		ArtiScrapeURI:  "http://localhost:8081/artifactory",
-		ArtiSSLVerify:  false,
-		ArtiTimeout:    5 * time.Second,
-		UseCache:       false,
-		CacheTTL:       5 * time.Minute,
-		CacheTimeout:   30 * time.Second,
-		ListenAddress:  ":9531",
-		MetricsPath:    "/metrics",
-		Credentials:    &config.Credentials{AuthMethod: "userPass", Username: "user", Password: "pass"},
-		Logger:         l.New(l.Config{Format: "logfmt", Level: "debug"}),
+		ArtiScrapeURI: "http://localhost:8081/artifactory",
+		ArtiSSLVerify: false,
+		ArtiTimeout:   5 * time.Second,
+		UseCache:      false,
+		CacheTTL:      5 * time.Minute,
+		CacheTimeout:  30 * time.Second,
+		ListenAddress: ":9531",
+		MetricsPaths:  []string{"/metrics"},
+		Credentials:   &config.Credentials{AuthMethod: "userPass", Username: "user", Password: "pass"},
+		Logger:        l.New(l.Config{Format: "logfmt", Level: "debug"}),
 		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{
 			OptionalMetrics: config.OptionalMetrics{
 				FederationStatus: true,
@@ -251,4 +251,177 @@ func TestIsFederationEnabled(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestFederationState(t *testing.T) {
+	tests := []struct {
+		name         string
+		responseBody string
+		responseCode int
+		expected     string
+	}{
+		{
+			name:         "Enabled",
+			responseBody: `{"unavailableMirrors":[],"nodeId":"test-node"}`,
+			responseCode: http.StatusOK,
+			expected:     FederationEnabled,
+		},
+		{
+			name:         "RTFS",
+			responseBody: "RTFS is enabled therefore get unavailable mirrors is not allowed",
+			responseCode: http.StatusOK,
+			expected:     FederationRTFS,
+		},
+		{
+			name:         "Unauthorized",
+			responseBody: `{"errors":[{"status":403,"message":"Forbidden"}]}`,
+			responseCode: http.StatusForbidden,
+			expected:     FederationUnauthorized,
+		},
+		{
+			name:         "Unavailable",
+			responseBody: `{"errors":[{"status":404,"message":"Not Found"}]}`,
+			responseCode: http.StatusNotFound,
+			expected:     FederationUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createTestServer(tt.responseBody, tt.responseCode)
+			defer server.Close()
+
+			conf := createFederationTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			client := NewClient(conf)
+
+			state, err := client.FederationState()
+			if state != tt.expected {
+				t.Errorf("FederationState() = %s, want %s", state, tt.expected)
+			}
+			failed := tt.expected == FederationUnauthorized || tt.expected == FederationUnavailable
+			if failed != (err != nil) {
+				t.Errorf("FederationState() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRTFSEnabled(t *testing.T) {
+	server := createTestServer("RTFS is enabled therefore get unavailable mirrors is not allowed", 200)
+	defer server.Close()
+
+	conf := createFederationTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	if client.RTFSEnabled() {
+		t.Error("RTFSEnabled() = true before any federation endpoint was fetched")
+	}
+	if _, err := client.FetchUnavailableMirrors(); err != nil {
+		t.Fatalf("FetchUnavailableMirrors() error = %v", err)
+	}
+	if !client.RTFSEnabled() {
+		t.Error("RTFSEnabled() = false after unavailable mirrors endpoint reported RTFS")
+	}
+
+	client.ResetRTFSEnabled()
+	if client.RTFSEnabled() {
+		t.Error("RTFSEnabled() = true after reset")
+	}
+
+	nodeClient := client.NodeClient(server.URL)
+	if _, err := nodeClient.FetchMirrorLags(); err != nil {
+		t.Fatalf("FetchMirrorLags() error = %v", err)
+	}
+	if !client.RTFSEnabled() {
+		t.Error("RTFSEnabled() = false after node client's mirror lags endpoint reported RTFS")
+	}
+}
+
+func TestFetchUnavailableMirrorsNodeId(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "node-2")
+		w.Write([]byte(`{"unavailableMirrors":[{"status":"unavailable","localRepoKey":"local"},{"status":"unavailable","localRepoKey":"other","nodeId":"node-3"}]}`))
+	}))
+	defer server.Close()
+
+	conf := createFederationTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	result, err := client.FetchUnavailableMirrors()
+	if err != nil {
+		t.Fatalf("FetchUnavailableMirrors() error = %v", err)
+	}
+	if result.NodeId != "node-2" {
+		t.Errorf("NodeId = %s, want node-2", result.NodeId)
+	}
+	if len(result.UnavailableMirrors) != 2 {
+		t.Fatalf("Expected 2 mirrors but got %d", len(result.UnavailableMirrors))
+	}
+	if nodeId := result.UnavailableMirrors[0].NodeId; nodeId != "node-2" {
+		t.Errorf("Mirror without node ID got NodeId %s, want node-2", nodeId)
+	}
+	if nodeId := result.UnavailableMirrors[1].NodeId; nodeId != "node-3" {
+		t.Errorf("Mirror with node ID got NodeId %s, want node-3", nodeId)
+	}
+}
+
+func TestFederationTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		switch r.URL.Path {
+		case "/api/federation/status/mirrorsLag":
+			w.Write([]byte(`[]`))
+		default:
+			w.Write([]byte(`{"unavailableMirrors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name              string
+		artiTimeout       time.Duration
+		federationTimeout time.Duration
+		expectError       bool
+	}{
+		{
+			name:        "Artifactory timeout without federation timeout",
+			artiTimeout: 20 * time.Millisecond,
+			expectError: true,
+		},
+		{
+			name:              "Longer federation timeout",
+			artiTimeout:       20 * time.Millisecond,
+			federationTimeout: 2 * time.Second,
+		},
+		{
+			name:              "Shorter federation timeout",
+			artiTimeout:       2 * time.Second,
+			federationTimeout: 20 * time.Millisecond,
+			expectError:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := createFederationTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			conf.ArtiTimeout = tt.artiTimeout
+			conf.FederationTimeout = tt.federationTimeout
+			client := NewClient(conf)
+
+			_, lagsErr := client.FetchMirrorLags()
+			_, mirrorsErr := client.FetchUnavailableMirrors()
+			for name, err := range map[string]error{"FetchMirrorLags": lagsErr, "FetchUnavailableMirrors": mirrorsErr} {
+				if tt.expectError && err == nil {
+					t.Errorf("%s() expected error but got none", name)
+				}
+				if !tt.expectError && err != nil {
+					t.Errorf("%s() error = %v", name, err)
+				}
+			}
+		})
+	}
+}
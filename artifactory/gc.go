@@ -0,0 +1,91 @@
+package artifactory
+
+import (
+	"strconv"
+	"time"
+)
+
+// gcDurationFamily is the metric family of the garbage collection runs in the
+// open metrics. Every run is reported with its type, status, start and end.
+const gcDurationFamily = "jfrt_artifacts_gc_duration_seconds"
+
+// GarbageCollectionRun represents a single garbage collection run.
+type GarbageCollectionRun struct {
+	Type            string
+	Status          string
+	Start           time.Time
+	End             time.Time
+	DurationSeconds float64
+}
+
+// Succeeded returns true if the run completed successfully.
+func (r GarbageCollectionRun) Succeeded() bool {
+	return r.Status == "COMPLETED"
+}
+
+// GarbageCollectionRuns represents the garbage collection runs found in the
+// open metrics.
+type GarbageCollectionRuns struct {
+	Runs []GarbageCollectionRun
+	// Available is false if the open metrics don't include garbage
+	// collection runs, which depends on the version of Artifactory.
+	Available bool
+	NodeId    string
+}
+
+// FetchGarbageCollectionRuns makes the API call to open metrics endpoint and
+// returns the garbage collection runs found in it. Runs without valid start
+// and end time are skipped.
+func (c *Client) FetchGarbageCollectionRuns() (GarbageCollectionRuns, error) {
+	c.logger.Debug("Fetching garbage collection runs")
+	openMetrics, err := c.FetchOpenMetrics()
+	if err != nil {
+		return GarbageCollectionRuns{}, err
+	}
+	return c.ParseGarbageCollectionRuns(openMetrics)
+}
+
+// ParseGarbageCollectionRuns returns the garbage collection runs found in
+// already fetched open metrics.
+func (c *Client) ParseGarbageCollectionRuns(openMetrics OpenMetrics) (GarbageCollectionRuns, error) {
+	var gcRuns GarbageCollectionRuns
+	family, err := c.parseOpenMetricsFamily(openMetrics, gcDurationFamily)
+	if err != nil {
+		return gcRuns, err
+	}
+	gcRuns.NodeId = openMetrics.NodeId
+	if family == nil {
+		return gcRuns, nil
+	}
+	gcRuns.Available = true
+	for _, metric := range family.GetMetric() {
+		run := GarbageCollectionRun{
+			DurationSeconds: metric.GetGauge().GetValue(),
+		}
+		var start, end int64
+		var startErr, endErr error
+		for _, label := range metric.GetLabel() {
+			switch label.GetName() {
+			case "gc_type":
+				run.Type = label.GetValue()
+			case "status":
+				run.Status = label.GetValue()
+			case "start_time":
+				start, startErr = strconv.ParseInt(label.GetValue(), 10, 64)
+			case "end_time":
+				end, endErr = strconv.ParseInt(label.GetValue(), 10, 64)
+			}
+		}
+		if startErr != nil || endErr != nil || end == 0 {
+			c.logger.Debug(
+				"Skipping garbage collection run without valid start and end time",
+				"type", run.Type,
+			)
+			continue
+		}
+		run.Start = time.UnixMilli(start)
+		run.End = time.UnixMilli(end)
+		gcRuns.Runs = append(gcRuns.Runs, run)
+	}
+	return gcRuns, nil
+}
@@ -0,0 +1,97 @@
+package artifactory
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFetchGarbageCollectionRuns(t *testing.T) {
+	tests := []struct {
+		name          string
+		responseBody  string
+		responseCode  int
+		expectError   bool
+		expectedAvail bool
+		expected      []GarbageCollectionRun
+	}{
+		{
+			name: "Runs of multiple types",
+			responseBody: `# HELP jfrt_runtime_heap_freememory_bytes Free memory
+# TYPE jfrt_runtime_heap_freememory_bytes gauge
+jfrt_runtime_heap_freememory_bytes 1.2e+09 1700000000000
+# HELP jfrt_artifacts_gc_duration_seconds Time taken by a GC run
+# TYPE jfrt_artifacts_gc_duration_seconds gauge
+jfrt_artifacts_gc_duration_seconds{end_time="1700000012000",gc_type="FULL",start_time="1700000000000",status="COMPLETED"} 12 1700000060000
+jfrt_artifacts_gc_duration_seconds{end_time="1700003602500",gc_type="TRASH_AND_BINARIES",start_time="1700003600000",status="COMPLETED"} 2.5 1700003660000
+jfrt_artifacts_gc_duration_seconds{end_time="1700007201000",gc_type="TRASH_AND_BINARIES",start_time="1700007200000",status="FAILED"} 1 1700007260000
+jfrt_artifacts_gc_duration_seconds{gc_type="FULL",status="RUNNING"} 0 1700007260000
+# HELP jfrt_artifacts_gc_binaries_total Number of binaries removed by a GC run
+# TYPE jfrt_artifacts_gc_binaries_total counter
+jfrt_artifacts_gc_binaries_total{end_time="1700000012000",gc_type="FULL",start_time="1700000000000",status="COMPLETED"} 42 1700000060000
+# EOF`,
+			responseCode:  http.StatusOK,
+			expectedAvail: true,
+			expected: []GarbageCollectionRun{
+				{Type: "FULL", Status: "COMPLETED", Start: time.UnixMilli(1700000000000), End: time.UnixMilli(1700000012000), DurationSeconds: 12},
+				{Type: "TRASH_AND_BINARIES", Status: "COMPLETED", Start: time.UnixMilli(1700003600000), End: time.UnixMilli(1700003602500), DurationSeconds: 2.5},
+				{Type: "TRASH_AND_BINARIES", Status: "FAILED", Start: time.UnixMilli(1700007200000), End: time.UnixMilli(1700007201000), DurationSeconds: 1},
+			},
+		},
+		{
+			name: "Runs not available",
+			responseBody: `# HELP jfrt_runtime_heap_freememory_bytes Free memory
+# TYPE jfrt_runtime_heap_freememory_bytes gauge
+jfrt_runtime_heap_freememory_bytes 1.2e+09 1700000000000
+# EOF`,
+			responseCode:  http.StatusOK,
+			expectedAvail: false,
+		},
+		{
+			name:          "Endpoint not available",
+			responseBody:  `{"errors":[{"status":404,"message":"Not Found"}]}`,
+			responseCode:  http.StatusNotFound,
+			expectedAvail: false,
+		},
+		{
+			name:         "Server error",
+			responseBody: `{"errors":[{"status":500,"message":"Internal Server Error"}]}`,
+			responseCode: http.StatusInternalServerError,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createTestServer(tt.responseBody, tt.responseCode)
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			client := NewClient(conf)
+
+			gcRuns, err := client.FetchGarbageCollectionRuns()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchGarbageCollectionRuns() error = %v", err)
+			}
+			if gcRuns.Available != tt.expectedAvail {
+				t.Errorf("Available = %v, want %v", gcRuns.Available, tt.expectedAvail)
+			}
+			if len(gcRuns.Runs) != len(tt.expected) {
+				t.Fatalf("FetchGarbageCollectionRuns() returned %d runs, want %d", len(gcRuns.Runs), len(tt.expected))
+			}
+			for i, expected := range tt.expected {
+				run := gcRuns.Runs[i]
+				if run.Type != expected.Type || run.Status != expected.Status || !run.Start.Equal(expected.Start) || !run.End.Equal(expected.End) || run.DurationSeconds != expected.DurationSeconds {
+					t.Errorf("Runs[%d] = %+v, want %+v", i, run, expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package artifactory
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RequestInterceptor is called with every request to Artifactory right before
+// it's sent, after the auth headers were set. It may modify the request, e.g.
+// add headers or sign it for a service mesh. A returned error aborts the
+// request.
+type RequestInterceptor func(req *http.Request) error
+
+// SetRequestInterceptor sets the interceptor of the requests to Artifactory.
+// It has to be called before the client is used.
+func (c *Client) SetRequestInterceptor(interceptor RequestInterceptor) {
+	c.requestInterceptor = interceptor
+}
+
+// interceptRequest passes req to the request interceptor, if any.
+func (c *Client) interceptRequest(req *http.Request) error {
+	if c.requestInterceptor == nil {
+		return nil
+	}
+	if err := c.requestInterceptor(req); err != nil {
+		return fmt.Errorf("request interceptor: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package artifactory
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRequestInterceptor(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("X-Signature") != "signed:"+r.URL.Path {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":[{"status":403,"message":"Forbidden"}]}`))
+			return
+		}
+		w.Write([]byte(`OK`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	if _, err := client.FetchHTTP("system/ping"); err == nil {
+		t.Error("FetchHTTP() without interceptor error = nil, want unsigned request rejected")
+	}
+
+	client.SetRequestInterceptor(func(req *http.Request) error {
+		if req.Header.Get("Authorization") == "" {
+			t.Error("Interceptor called before the auth header was set")
+		}
+		req.Header.Set("X-Signature", "signed:"+req.URL.Path)
+		return nil
+	})
+	if _, err := client.FetchHTTP("system/ping"); err != nil {
+		t.Errorf("FetchHTTP() with interceptor error = %v", err)
+	}
+	if _, err := client.NodeClient(server.URL).FetchHTTP("system/ping"); err != nil {
+		t.Errorf("FetchHTTP() of node client error = %v", err)
+	}
+
+	signErr := errors.New("no signing key")
+	client.SetRequestInterceptor(func(req *http.Request) error {
+		return signErr
+	})
+	before := requests.Load()
+	if _, err := client.FetchHTTP("system/ping"); !errors.Is(err, signErr) {
+		t.Errorf("FetchHTTP() error = %v, want %v", err, signErr)
+	}
+	if requests.Load() != before {
+		t.Error("Request was sent although the interceptor failed")
+	}
+}
@@ -0,0 +1,61 @@
+package artifactory
+
+// gcCollectionFamily is the metric family of the JVM garbage collection
+// stats in the open metrics, as exposed by the Prometheus Java client.
+const gcCollectionFamily = "jvm_gc_collection_seconds"
+
+// GCCollector represents the garbage collection stats of a single JVM
+// garbage collector.
+type GCCollector struct {
+	Name    string
+	Seconds float64
+	Count   uint64
+}
+
+// JVMMetrics represents the JVM stats found in the open metrics
+type JVMMetrics struct {
+	GCCollectors []GCCollector
+	// Available is false if the open metrics don't include JVM stats,
+	// which depends on the edition and version of Artifactory.
+	Available bool
+	NodeId    string
+}
+
+// FetchJVMMetrics makes the API call to open metrics endpoint and returns the
+// JVM garbage collection stats found in it.
+func (c *Client) FetchJVMMetrics() (JVMMetrics, error) {
+	c.logger.Debug("Fetching JVM metrics")
+	openMetrics, err := c.FetchOpenMetrics()
+	if err != nil {
+		return JVMMetrics{}, err
+	}
+	return c.ParseJVMMetrics(openMetrics)
+}
+
+// ParseJVMMetrics returns the JVM garbage collection stats found in already
+// fetched open metrics.
+func (c *Client) ParseJVMMetrics(openMetrics OpenMetrics) (JVMMetrics, error) {
+	var jvmMetrics JVMMetrics
+	family, err := c.parseOpenMetricsFamily(openMetrics, gcCollectionFamily)
+	if err != nil {
+		return jvmMetrics, err
+	}
+	jvmMetrics.NodeId = openMetrics.NodeId
+	if family == nil {
+		return jvmMetrics, nil
+	}
+	jvmMetrics.Available = true
+	for _, metric := range family.GetMetric() {
+		collector := GCCollector{
+			Seconds: metric.GetSummary().GetSampleSum(),
+			Count:   metric.GetSummary().GetSampleCount(),
+		}
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "gc" {
+				collector.Name = label.GetValue()
+			}
+		}
+		jvmMetrics.GCCollectors = append(jvmMetrics.GCCollectors, collector)
+	}
+	return jvmMetrics, nil
+}
@@ -0,0 +1,91 @@
+package artifactory
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFetchJVMMetrics(t *testing.T) {
+	tests := []struct {
+		name          string
+		responseBody  string
+		responseCode  int
+		expectError   bool
+		expectedAvail bool
+		expected      []GCCollector
+	}{
+		{
+			name: "GC metrics available",
+			responseBody: `# HELP jfrt_runtime_heap_freememory_bytes Free memory
+# TYPE jfrt_runtime_heap_freememory_bytes gauge
+jfrt_runtime_heap_freememory_bytes 1.2e+09 1700000000000
+# HELP jvm_gc_collection_seconds Time spent in a given JVM garbage collector in seconds.
+# TYPE jvm_gc_collection_seconds summary
+jvm_gc_collection_seconds_count{gc="G1 Young Generation",} 12.0
+jvm_gc_collection_seconds_sum{gc="G1 Young Generation",} 0.345
+jvm_gc_collection_seconds_count{gc="G1 Old Generation",} 1.0
+jvm_gc_collection_seconds_sum{gc="G1 Old Generation",} 1.5
+# EOF`,
+			responseCode:  http.StatusOK,
+			expectedAvail: true,
+			expected: []GCCollector{
+				{Name: "G1 Young Generation", Seconds: 0.345, Count: 12},
+				{Name: "G1 Old Generation", Seconds: 1.5, Count: 1},
+			},
+		},
+		{
+			name: "GC metrics not available",
+			responseBody: `# HELP jfrt_runtime_heap_freememory_bytes Free memory
+# TYPE jfrt_runtime_heap_freememory_bytes gauge
+jfrt_runtime_heap_freememory_bytes 1.2e+09 1700000000000
+# EOF`,
+			responseCode:  http.StatusOK,
+			expectedAvail: false,
+		},
+		{
+			name:          "Endpoint not available",
+			responseBody:  `{"errors":[{"status":404,"message":"Not Found"}]}`,
+			responseCode:  http.StatusNotFound,
+			expectedAvail: false,
+		},
+		{
+			name:         "Server error",
+			responseBody: `{"errors":[{"status":500,"message":"Internal Server Error"}]}`,
+			responseCode: http.StatusInternalServerError,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createTestServer(tt.responseBody, tt.responseCode)
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			client := NewClient(conf)
+
+			jvmMetrics, err := client.FetchJVMMetrics()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchJVMMetrics() error = %v", err)
+			}
+			if jvmMetrics.Available != tt.expectedAvail {
+				t.Errorf("Available = %v, want %v", jvmMetrics.Available, tt.expectedAvail)
+			}
+			if len(jvmMetrics.GCCollectors) != len(tt.expected) {
+				t.Fatalf("FetchJVMMetrics() returned %d collectors, want %d", len(jvmMetrics.GCCollectors), len(tt.expected))
+			}
+			for i, expected := range tt.expected {
+				if jvmMetrics.GCCollectors[i] != expected {
+					t.Errorf("GCCollectors[%d] = %+v, want %+v", i, jvmMetrics.GCCollectors[i], expected)
+				}
+			}
+		})
+	}
+}
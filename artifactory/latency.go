@@ -0,0 +1,97 @@
+package artifactory
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestHook is called with the normalized endpoint and the duration of every
+// request sent to the Artifactory API, including reading the response body.
+// Responses served from the response cache aren't requests and aren't reported.
+type RequestHook func(endpoint string, duration time.Duration)
+
+// endpointPatterns replace repository keys and other names in endpoint paths
+// with placeholders. The first matching pattern applies.
+var endpointPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`^docker/[^/]+/v2/_catalog$`), "docker/{repo}/v2/_catalog"},
+	{regexp.MustCompile(`^docker/[^/]+/v2/.+/tags/list$`), "docker/{repo}/v2/{image}/tags/list"},
+	{regexp.MustCompile(`^pypi/[^/]+/simple$`), "pypi/{repo}/simple"},
+	{regexp.MustCompile(`^repositories/.+$`), "repositories/{repo}"},
+	{regexp.MustCompile(`^replication/.+$`), "replication/{repo}"},
+	{regexp.MustCompile(`^storage/quota$`), "storage/quota"},
+	{regexp.MustCompile(`^storage/.+$`), "storage/{path}"},
+	{regexp.MustCompile(`^(v2/)?security/permissions/.+$`), "${1}security/permissions/{name}"},
+}
+
+// SetRequestHook enables timing of the requests to Artifactory. It has to be
+// called before the client is used.
+func (c *Client) SetRequestHook(hook RequestHook) {
+	c.requestHook = hook
+}
+
+// send sends req and reports its duration to the request hook, if any, once
+// the response body is read or closed. Only requests to the Artifactory API
+// are reported.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.client.Do(c.traceRequest(req))
+	if c.requestHook == nil {
+		return resp, err
+	}
+	endpoint, ok := strings.CutPrefix(req.URL.String(), c.apiURL(""))
+	if !ok {
+		return resp, err
+	}
+	observe := func() {
+		c.requestHook(normalizeEndpoint(endpoint), time.Since(start))
+	}
+	if err != nil {
+		observe()
+		return resp, err
+	}
+	resp.Body = &observingBody{ReadCloser: resp.Body, observe: observe}
+	return resp, nil
+}
+
+// observingBody calls observe once the body is read until EOF or closed,
+// whichever happens first.
+type observingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	observe func()
+}
+
+func (b *observingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.once.Do(b.observe)
+	}
+	return n, err
+}
+
+func (b *observingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.observe)
+	return err
+}
+
+// normalizeEndpoint returns the endpoint path without query parameters and
+// with repository keys and other names replaced by placeholders, bounding the
+// number of distinct endpoints.
+func normalizeEndpoint(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	path = strings.Trim(path, "/")
+	for _, p := range endpointPatterns {
+		if p.pattern.MatchString(path) {
+			return p.pattern.ReplaceAllString(path, p.replacement)
+		}
+	}
+	return path
+}
@@ -0,0 +1,131 @@
+package artifactory
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNormalizeEndpoint(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"system/ping", "system/ping"},
+		{"repositories", "repositories"},
+		{"repositories?type=remote", "repositories"},
+		{"repositories/libs-release", "repositories/{repo}"},
+		{"replication/libs-release", "replication/{repo}"},
+		{"storage/quota", "storage/quota"},
+		{"storage/libs-release/org/example?list&deep=1", "storage/{path}"},
+		{"security/permissions", "security/permissions"},
+		{"security/permissions/readers", "security/permissions/{name}"},
+		{"v2/security/permissions/readers", "v2/security/permissions/{name}"},
+		{"docker/docker-local/v2/_catalog?n=100", "docker/{repo}/v2/_catalog"},
+		{"docker/docker-local/v2/team/app/tags/list?n=100&last=1.0", "docker/{repo}/v2/{image}/tags/list"},
+		{"pypi/pypi-local/simple/", "pypi/{repo}/simple"},
+		{"federation/status/mirrorsLag", "federation/status/mirrorsLag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := normalizeEndpoint(tt.path); got != tt.expected {
+				t.Errorf("normalizeEndpoint(%q) = %q, want %q", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRequestHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	var mutex sync.Mutex
+	var endpoints []string
+	client.SetRequestHook(func(endpoint string, duration time.Duration) {
+		if duration < 0 {
+			t.Errorf("Request to %s took %v, want a positive duration", endpoint, duration)
+		}
+		mutex.Lock()
+		endpoints = append(endpoints, endpoint)
+		mutex.Unlock()
+	})
+
+	if _, err := client.FetchHTTP("repositories/libs-release"); err != nil {
+		t.Fatalf("FetchHTTP() error = %v", err)
+	}
+	if _, err := client.FetchHTTPWithContext(context.Background(), federationUnavailableMirrorsEndpoint); err != nil {
+		t.Fatalf("FetchHTTPWithContext() error = %v", err)
+	}
+	stream, err := client.FetchHTTPStream(storageInfoEndpoint)
+	if err != nil {
+		t.Fatalf("FetchHTTPStream() error = %v", err)
+	}
+	io.ReadAll(stream.Body)
+	stream.Body.Close()
+	if _, err := client.QueryAQL([]byte(`items.find()`)); err != nil {
+		t.Fatalf("QueryAQL() error = %v", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	expected := []string{"repositories/{repo}", federationUnavailableMirrorsEndpoint, storageInfoEndpoint, aqlEndpoint}
+	if len(endpoints) != len(expected) {
+		t.Fatalf("Observed requests to %v, want %v", endpoints, expected)
+	}
+	for i := range expected {
+		if endpoints[i] != expected[i] {
+			t.Errorf("Request %d: endpoint = %q, want %q", i, endpoints[i], expected[i])
+		}
+	}
+}
+
+func TestRequestHookCachedResponse(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.UseCache = true
+	conf.CacheTimeout = 20 * time.Millisecond
+	client := NewClient(conf)
+
+	durations := make(chan time.Duration, 2)
+	client.SetRequestHook(func(endpoint string, duration time.Duration) {
+		durations <- duration
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.FetchHTTP("system/ping"); err != nil {
+			t.Fatalf("FetchHTTP() error = %v", err)
+		}
+		if i == 0 {
+			<-durations
+		}
+	}
+	select {
+	case duration := <-durations:
+		if duration < 200*time.Millisecond {
+			t.Errorf("Request served from the cache reported %v, want the duration of the request to Artifactory", duration)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Request to Artifactory wasn't reported")
+	}
+}
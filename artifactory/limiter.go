@@ -0,0 +1,114 @@
+package artifactory
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// requestLimiter caps the number of requests in flight to Artifactory. It is
+// shared by a client and its node clients. A nil limiter doesn't limit.
+type requestLimiter struct {
+	slots  chan struct{}
+	queued atomic.Int64
+}
+
+// newRequestLimiter returns a limiter allowing max requests in flight, or nil
+// if max isn't positive.
+func newRequestLimiter(max int) *requestLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &requestLimiter{slots: make(chan struct{}, max)}
+}
+
+// acquire waits for a free slot until ctx is done. Waiting requests are
+// counted as queued.
+func (l *requestLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	l.queued.Add(1)
+	defer l.queued.Add(-1)
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire.
+func (l *requestLimiter) release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
+// Queued returns the number of requests waiting for a free slot.
+func (l *requestLimiter) Queued() int {
+	if l == nil {
+		return 0
+	}
+	return int(l.queued.Load())
+}
+
+// releasingBody frees the slot of a request once its response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	limiter *requestLimiter
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.limiter.release)
+	return err
+}
+
+// doRequest sends req once a slot of the limiter is free. Requests wait at most
+// until the deadline of their context or, without one, the client timeout. The
+// slot is freed when the response body is closed. The request interceptor is
+// called right before the request is sent.
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	if c.limiter == nil {
+		if err := c.interceptRequest(req); err != nil {
+			return nil, err
+		}
+		return c.send(req)
+	}
+	ctx := req.Context()
+	if _, ok := ctx.Deadline(); !ok && c.client.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.client.Timeout)
+		defer cancel()
+	}
+	if err := c.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.interceptRequest(req); err != nil {
+		c.limiter.release()
+		return nil, err
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		c.limiter.release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, limiter: c.limiter}
+	return resp, nil
+}
+
+// RequestsQueued returns the number of requests waiting for a free slot of
+// the concurrent request limit.
+func (c *Client) RequestsQueued() int {
+	return c.limiter.Queued()
+}
@@ -0,0 +1,89 @@
+package artifactory
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	const maxConcurrent = 3
+	const requests = 20
+	var inFlight, maxInFlight atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`OK`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.MaxConcurrentRequests = maxConcurrent
+	client := NewClient(conf)
+
+	var wg sync.WaitGroup
+	var queued atomic.Int64
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.FetchHTTP(fmt.Sprintf("storage/repo-%d", i)); err != nil {
+				t.Errorf("FetchHTTP() error = %v", err)
+			}
+			if n := int64(client.RequestsQueued()); n > queued.Load() {
+				queued.Store(n)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if max := maxInFlight.Load(); max > maxConcurrent {
+		t.Errorf("Requests in flight = %d, want at most %d", max, maxConcurrent)
+	}
+	if queued.Load() == 0 {
+		t.Error("RequestsQueued() = 0 while requests were waiting, want > 0")
+	}
+	if n := client.RequestsQueued(); n != 0 {
+		t.Errorf("RequestsQueued() = %d after all requests finished, want 0", n)
+	}
+}
+
+func TestMaxConcurrentRequestsTimeout(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+		w.Write([]byte(`OK`))
+	}))
+	defer server.Close()
+	defer close(block)
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.ArtiTimeout = 200 * time.Millisecond
+	conf.MaxConcurrentRequests = 1
+	client := NewClient(conf)
+
+	go client.FetchHTTP("storage/blocking")
+	for client.limiter.Queued() == 0 && len(client.limiter.slots) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	start := time.Now()
+	if _, err := client.FetchHTTP("storage/queued"); err == nil {
+		t.Error("FetchHTTP() error = nil while the only slot was taken, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Queued request waited %s, want about the client timeout", elapsed)
+	}
+}
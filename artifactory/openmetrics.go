@@ -1,5 +1,14 @@
 package artifactory
 
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
 const openMetricsEndpoint = "v1/metrics"
 
 type OpenMetrics struct {
@@ -13,7 +22,8 @@ func (c *Client) FetchOpenMetrics() (OpenMetrics, error) {
 	c.logger.Debug("Fetching openMetrics")
 	resp, err := c.FetchHTTP(openMetricsEndpoint)
 	if err != nil {
-		if err.(*APIError).status == 404 {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.status == 404 {
 			return openMetrics, nil
 		}
 		return openMetrics, err
@@ -29,3 +39,80 @@ func (c *Client) FetchOpenMetrics() (OpenMetrics, error) {
 
 	return openMetrics, nil
 }
+
+// NativeMetrics represents metric families selected from the open metrics.
+type NativeMetrics struct {
+	Families []*dto.MetricFamily
+	NodeId   string
+}
+
+// FetchNativeMetrics makes the API call to open metrics endpoint and returns
+// the metric families with the given names, sorted by name. Families the open
+// metrics don't include are left out.
+func (c *Client) FetchNativeMetrics(names []string) (NativeMetrics, error) {
+	c.logger.Debug("Fetching native metrics")
+	openMetrics, err := c.FetchOpenMetrics()
+	if err != nil {
+		return NativeMetrics{}, err
+	}
+	return c.ParseNativeMetrics(openMetrics, names)
+}
+
+// ParseNativeMetrics returns the metric families with the given names of
+// already fetched open metrics, sorted by name.
+func (c *Client) ParseNativeMetrics(openMetrics OpenMetrics, names []string) (NativeMetrics, error) {
+	var nativeMetrics NativeMetrics
+	families, err := c.parseOpenMetricsFamilies(openMetrics, names)
+	if err != nil {
+		return nativeMetrics, err
+	}
+	nativeMetrics.NodeId = openMetrics.NodeId
+	for _, family := range families {
+		nativeMetrics.Families = append(nativeMetrics.Families, family)
+	}
+	slices.SortFunc(nativeMetrics.Families, func(a, b *dto.MetricFamily) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return nativeMetrics, nil
+}
+
+// parseOpenMetricsFamily returns the metric family with the given name of
+// the open metrics. The family is nil if the open metrics don't include it.
+func (c *Client) parseOpenMetricsFamily(openMetrics OpenMetrics, name string) (*dto.MetricFamily, error) {
+	families, err := c.parseOpenMetricsFamilies(openMetrics, []string{name})
+	return families[name], err
+}
+
+// parseOpenMetricsFamilies returns the metric families with the given names
+// of the open metrics. Only the lines of the families are parsed, so metrics
+// of other families the parser doesn't support can't break it.
+func (c *Client) parseOpenMetricsFamilies(openMetrics OpenMetrics, names []string) (map[string]*dto.MetricFamily, error) {
+	var lines []string
+	for _, line := range strings.Split(openMetrics.PromMetrics, "\n") {
+		metric := strings.TrimPrefix(strings.TrimPrefix(line, "# HELP "), "# TYPE ")
+		if slices.ContainsFunc(names, func(name string) bool { return strings.HasPrefix(metric, name) }) {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(strings.NewReader(strings.Join(lines, "\n") + "\n"))
+	if err != nil {
+		c.logger.Error(
+			"There was an issue when trying to parse open metrics",
+			"families", names,
+		)
+		return nil, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: openMetricsEndpoint,
+		}
+	}
+	for name := range families {
+		if !slices.Contains(names, name) {
+			delete(families, name)
+		}
+	}
+	return families, nil
+}
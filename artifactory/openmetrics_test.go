@@ -0,0 +1,55 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const nativeMetricsFixture = `# HELP jfrt_runtime_heap_freememory_bytes Free memory
+# TYPE jfrt_runtime_heap_freememory_bytes gauge
+jfrt_runtime_heap_freememory_bytes 1.2e+09 1700000000000
+# HELP jfrt_runtime_heap_freememory_bytes_max Max free memory
+# TYPE jfrt_runtime_heap_freememory_bytes_max gauge
+jfrt_runtime_heap_freememory_bytes_max 2e+09 1700000000000
+# HELP jfrt_http_connections_available_total Available connections
+# TYPE jfrt_http_connections_available_total counter
+jfrt_http_connections_available_total{pool="default"} 20 1700000000000
+# TYPE jfrt_unsupported info
+jfrt_unsupported_info{version="7"} 1
+# EOF`
+
+func TestFetchNativeMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/metrics" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		w.Write([]byte(nativeMetricsFixture))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	nativeMetrics, err := client.FetchNativeMetrics([]string{"jfrt_runtime_heap_freememory_bytes", "jfrt_http_connections_available_total", "jfrt_missing"})
+	if err != nil {
+		t.Fatalf("FetchNativeMetrics() error = %v", err)
+	}
+	if nativeMetrics.NodeId != "test-node" {
+		t.Errorf("NodeId = %s, want test-node", nativeMetrics.NodeId)
+	}
+	expected := []string{"jfrt_http_connections_available_total", "jfrt_runtime_heap_freememory_bytes"}
+	if len(nativeMetrics.Families) != len(expected) {
+		t.Fatalf("FetchNativeMetrics() returned %d families, want %d", len(nativeMetrics.Families), len(expected))
+	}
+	for i, name := range expected {
+		if got := nativeMetrics.Families[i].GetName(); got != name {
+			t.Errorf("Family %d = %s, want %s", i, got, name)
+		}
+	}
+	if value := nativeMetrics.Families[1].GetMetric()[0].GetGauge().GetValue(); value != 1.2e+09 {
+		t.Errorf("jfrt_runtime_heap_freememory_bytes = %v, want 1.2e+09", value)
+	}
+}
@@ -0,0 +1,37 @@
+package artifactory
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+const pypiSimpleEndpoint = "pypi/%s/simple/"
+
+// reSimpleIndexLink matches the links of a PEP 503 simple repository index,
+// one per project.
+var reSimpleIndexLink = regexp.MustCompile(`(?i)<a\s`)
+
+// PackageCount represents the number of packages in a repository
+type PackageCount struct {
+	RepoKey  string
+	Packages int
+	NodeId   string
+}
+
+// FetchPyPIProjects counts the projects of a PyPI repository listed by its
+// simple index.
+func (c *Client) FetchPyPIProjects(repoKey string) (PackageCount, error) {
+	count := PackageCount{RepoKey: repoKey}
+	c.logger.Debug(
+		"Fetching PyPI simple index",
+		"repo", repoKey,
+	)
+	resp, err := c.FetchHTTP(fmt.Sprintf(pypiSimpleEndpoint, url.PathEscape(repoKey)))
+	if err != nil {
+		return count, err
+	}
+	count.NodeId = resp.NodeId
+	count.Packages = len(reSimpleIndexLink.FindAllIndex(resp.Body, -1))
+	return count, nil
+}
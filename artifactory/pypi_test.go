@@ -0,0 +1,45 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchPyPIProjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/pypi/pypi-local/simple/" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+			return
+		}
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		w.Write([]byte(`<!DOCTYPE html>
+<html>
+<head><title>Simple Index</title></head>
+<body>
+<a href="requests/">requests</a><br/>
+<a href="flask/">flask</a><br/>
+<A href="Django/">Django</A><br/>
+</body>
+</html>`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	count, err := client.FetchPyPIProjects("pypi-local")
+	if err != nil {
+		t.Fatalf("FetchPyPIProjects() error = %v", err)
+	}
+	expected := PackageCount{RepoKey: "pypi-local", Packages: 3, NodeId: "test-node"}
+	if count != expected {
+		t.Errorf("FetchPyPIProjects() = %+v, want %+v", count, expected)
+	}
+
+	if _, err := client.FetchPyPIProjects("missing"); err == nil {
+		t.Error("Expected error for missing repository but got none")
+	}
+}
@@ -1,13 +1,18 @@
 package artifactory
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 )
 
 const replicationEndpoint = "replications"
 const replicationStatusEndpoint = "replication"
+const storageItemEndpoint = "storage"
 
 // Replication represents single element of API respond from replication endpoint
 type Replication struct {
@@ -23,6 +28,8 @@ type Replication struct {
 	CheckBinaryExistenceInFilestore bool   `json:"checkBinaryExistenceInFilestore"`
 	SyncStatistics                  bool   `json:"syncStatistics"`
 	Status                          string `json:"status"`
+	LastCompleted                   string `json:"-"`
+	LastModified                    string `json:"-"`
 }
 
 type Replications struct {
@@ -31,7 +38,84 @@ type Replications struct {
 }
 
 type ReplicationStatus struct {
-	Status string `json:"status"`
+	Status        string `json:"status"`
+	LastCompleted string `json:"lastCompleted"`
+}
+
+// ItemLastModified represents API respond from storage endpoint with the lastModified parameter
+type ItemLastModified struct {
+	URI          string `json:"uri"`
+	LastModified string `json:"lastModified"`
+}
+
+// artiTimeLayouts are the timestamp formats returned by the Artifactory API.
+var artiTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-0700",
+}
+
+func parseArtiTime(s string) (time.Time, error) {
+	var err error
+	for _, layout := range artiTimeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// LagSeconds returns how far the last successful replication is behind the
+// last modification of the source repository. Replications which are up to
+// date have no lag. The second return value is false when the replication
+// status does not provide enough information to calculate the lag.
+func (r Replication) LagSeconds() (float64, bool) {
+	if r.LastCompleted == "" || r.LastModified == "" {
+		return 0, false
+	}
+	lastCompleted, err := parseArtiTime(r.LastCompleted)
+	if err != nil {
+		return 0, false
+	}
+	lastModified, err := parseArtiTime(r.LastModified)
+	if err != nil {
+		return 0, false
+	}
+	if !lastModified.After(lastCompleted) {
+		return 0, true
+	}
+	return lastModified.Sub(lastCompleted).Seconds(), true
+}
+
+// replicationFailedStatuses are the replication statuses reported when the last run failed.
+var replicationFailedStatuses = []string{"error", "failure"}
+
+// LastRunFailed returns true if the replication status reports the last run
+// as failed. The API doesn't report error counts or times, only the status of
+// the last run. The second return value is false if the status wasn't fetched.
+func (r Replication) LastRunFailed() (bool, bool) {
+	if r.Status == "" {
+		return false, false
+	}
+	return slices.Contains(replicationFailedStatuses, strings.ToLower(r.Status)), true
+}
+
+// FetchLastModified makes the API call to storage endpoint and returns the last modification time of the repository
+func (c *Client) FetchLastModified(repoKey string) (ItemLastModified, error) {
+	var lastModified ItemLastModified
+	endpoint := fmt.Sprintf("%s/%s?lastModified", storageItemEndpoint, repoKey)
+	resp, err := c.FetchHTTP(endpoint)
+	if err != nil {
+		return lastModified, err
+	}
+	if err := c.unmarshalJSON(endpoint, resp.Body, &lastModified); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal last modified respond")
+		return lastModified, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+	return lastModified, nil
 }
 
 // FetchReplications makes the API call to replication endpoint and returns []Replication
@@ -48,7 +132,7 @@ func (c *Client) FetchReplications() (Replications, error) {
 	}
 	replications.NodeId = resp.NodeId
 
-	if err := json.Unmarshal(resp.Body, &replications.Replications); err != nil {
+	if replications.Replications, err = c.unmarshalReplications(resp.Body); err != nil {
 		c.logger.Error("There was an issue when try to unmarshal replication respond")
 		return replications, &UnmarshalError{
 			message:  err.Error(),
@@ -65,7 +149,7 @@ func (c *Client) FetchReplications() (Replications, error) {
 				if err != nil {
 					return replications, err
 				}
-				if err := json.Unmarshal(statusResp.Body, &status); err != nil {
+				if err := c.unmarshalJSON(fmt.Sprintf("%s/%s", replicationStatusEndpoint, replication.RepoKey), statusResp.Body, &status); err != nil {
 					c.logger.Error("There was an issue when try to unmarshal replication status respond")
 					return replications, &UnmarshalError{
 						message:  err.Error(),
@@ -73,9 +157,52 @@ func (c *Client) FetchReplications() (Replications, error) {
 					}
 				}
 				replications.Replications[i].Status = status.Status
+				replications.Replications[i].LastCompleted = status.LastCompleted
+				if status.LastCompleted == "" {
+					continue
+				}
+				lastModified, err := c.FetchLastModified(replication.RepoKey)
+				if err != nil {
+					// Empty repositories have no last modification, so the lag can't be calculated.
+					c.logger.Debug(
+						"Couldn't fetch last modification of replicated repository",
+						"repo", replication.RepoKey,
+						"err", err.Error(),
+					)
+					continue
+				}
+				replications.Replications[i].LastModified = lastModified.LastModified
 			}
 		}
 	}
 
 	return replications, nil
 }
+
+// unmarshalReplications parses the response of the replications endpoint.
+// Repositories replicating to multiple targets (multi-push) may be listed as
+// an array of replications instead of a single one, which is flattened.
+func (c *Client) unmarshalReplications(body []byte) ([]Replication, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		c.observeUnmarshalError(replicationEndpoint)
+		return nil, err
+	}
+	replications := make([]Replication, 0, len(items))
+	for _, item := range items {
+		if bytes.HasPrefix(bytes.TrimSpace(item), []byte("[")) {
+			var multiPush []Replication
+			if err := c.unmarshalJSON(replicationEndpoint, item, &multiPush); err != nil {
+				return nil, err
+			}
+			replications = append(replications, multiPush...)
+			continue
+		}
+		var replication Replication
+		if err := c.unmarshalJSON(replicationEndpoint, item, &replication); err != nil {
+			return nil, err
+		}
+		replications = append(replications, replication)
+	}
+	return replications, nil
+}
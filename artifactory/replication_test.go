@@ -0,0 +1,162 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchReplicationsLag(t *testing.T) {
+	responses := map[string]string{
+		"/api/replications":                 `[{"replicationType":"PUSH","enabled":true,"repoKey":"up-to-date","url":"http://remote/up-to-date"},{"replicationType":"PUSH","enabled":true,"repoKey":"lagging","url":"http://remote/lagging"},{"replicationType":"PUSH","enabled":true,"repoKey":"never-replicated","url":"http://remote/never-replicated"},{"replicationType":"PUSH","enabled":true,"repoKey":"empty","url":"http://remote/empty"}]`,
+		"/api/replication/up-to-date":       `{"status":"ok","lastCompleted":"2024-03-01T12:00:00.000Z"}`,
+		"/api/replication/lagging":          `{"status":"ok","lastCompleted":"2024-03-01T12:00:00.000+0000"}`,
+		"/api/replication/never-replicated": `{"status":"never_run"}`,
+		"/api/replication/empty":            `{"status":"ok","lastCompleted":"2024-03-01T12:00:00.000Z"}`,
+		"/api/storage/up-to-date":           `{"uri":"http://localhost/api/storage/up-to-date","lastModified":"2024-03-01T11:30:00.000Z"}`,
+		"/api/storage/lagging":              `{"uri":"http://localhost/api/storage/lagging","lastModified":"2024-03-01T12:05:30.000+0000"}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.ExporterRuntimeConfig.OptionalMetrics.ReplicationStatus = true
+	client := NewClient(conf)
+
+	replications, err := client.FetchReplications()
+	if err != nil {
+		t.Fatalf("FetchReplications() error = %v", err)
+	}
+	if len(replications.Replications) != 4 {
+		t.Fatalf("FetchReplications() returned %d replications, want 4", len(replications.Replications))
+	}
+
+	tests := []struct {
+		repoKey     string
+		expectedLag float64
+		expectLag   bool
+	}{
+		{repoKey: "up-to-date", expectedLag: 0, expectLag: true},
+		{repoKey: "lagging", expectedLag: 330, expectLag: true},
+		{repoKey: "never-replicated", expectLag: false},
+		{repoKey: "empty", expectLag: false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.repoKey, func(t *testing.T) {
+			replication := replications.Replications[i]
+			if replication.RepoKey != tt.repoKey {
+				t.Fatalf("Replication.RepoKey = %s, want %s", replication.RepoKey, tt.repoKey)
+			}
+			lag, ok := replication.LagSeconds()
+			if ok != tt.expectLag {
+				t.Fatalf("LagSeconds() ok = %v, want %v", ok, tt.expectLag)
+			}
+			if lag != tt.expectedLag {
+				t.Errorf("LagSeconds() = %v, want %v", lag, tt.expectedLag)
+			}
+		})
+	}
+}
+
+func TestFetchReplicationsLastRunFailed(t *testing.T) {
+	responses := map[string]string{
+		"/api/replications":        `[{"replicationType":"PUSH","enabled":true,"repoKey":"healthy","url":"http://remote/healthy"},{"replicationType":"PUSH","enabled":true,"repoKey":"failing","url":"http://remote/failing"},{"replicationType":"PULL","enabled":false,"repoKey":"disabled","url":"http://remote/disabled"}]`,
+		"/api/replication/healthy": `{"status":"ok","lastCompleted":"2024-03-01T12:00:00.000Z","targets":[{"url":"http://remote/healthy","repoKey":"healthy","status":"ok","lastCompleted":"2024-03-01T12:00:00.000Z"}]}`,
+		"/api/replication/failing": `{"status":"error","lastCompleted":"2024-02-28T08:15:00.000Z","targets":[{"url":"http://remote/failing","repoKey":"failing","status":"error","lastCompleted":"2024-02-28T08:15:00.000Z"}]}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.ExporterRuntimeConfig.OptionalMetrics.ReplicationStatus = true
+	client := NewClient(conf)
+
+	replications, err := client.FetchReplications()
+	if err != nil {
+		t.Fatalf("FetchReplications() error = %v", err)
+	}
+	if len(replications.Replications) != 3 {
+		t.Fatalf("FetchReplications() returned %d replications, want 3", len(replications.Replications))
+	}
+
+	tests := []struct {
+		repoKey      string
+		expectFailed bool
+		expectKnown  bool
+	}{
+		{repoKey: "healthy", expectFailed: false, expectKnown: true},
+		{repoKey: "failing", expectFailed: true, expectKnown: true},
+		{repoKey: "disabled", expectKnown: false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.repoKey, func(t *testing.T) {
+			replication := replications.Replications[i]
+			if replication.RepoKey != tt.repoKey {
+				t.Fatalf("Replication.RepoKey = %s, want %s", replication.RepoKey, tt.repoKey)
+			}
+			failed, ok := replication.LastRunFailed()
+			if ok != tt.expectKnown {
+				t.Fatalf("LastRunFailed() ok = %v, want %v", ok, tt.expectKnown)
+			}
+			if failed != tt.expectFailed {
+				t.Errorf("LastRunFailed() = %v, want %v", failed, tt.expectFailed)
+			}
+		})
+	}
+}
+
+func TestFetchReplicationsMultiPush(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			[
+				{"replicationType":"PUSH","enabled":true,"repoKey":"libs-release","url":"http://target-1/libs-release"},
+				{"replicationType":"PUSH","enabled":true,"repoKey":"libs-release","url":"http://target-2/libs-release"},
+				{"replicationType":"PUSH","enabled":false,"repoKey":"libs-release","url":"http://target-3/libs-release"}
+			],
+			{"replicationType":"PULL","enabled":true,"repoKey":"npm-remote","url":"https://registry.npmjs.org"}
+		]`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	replications, err := client.FetchReplications()
+	if err != nil {
+		t.Fatalf("FetchReplications() error = %v", err)
+	}
+	expected := []string{"http://target-1/libs-release", "http://target-2/libs-release", "http://target-3/libs-release", "https://registry.npmjs.org"}
+	if len(replications.Replications) != len(expected) {
+		t.Fatalf("FetchReplications() returned %d replications, want %d", len(replications.Replications), len(expected))
+	}
+	for i, url := range expected {
+		if replications.Replications[i].URL != url {
+			t.Errorf("Replication %d has URL %s, want %s", i, replications.Replications[i].URL, url)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package artifactory
+
+import (
+	"fmt"
+	"net/url"
+)
+
+const (
+	repositoriesEndpoint          = "repositories"
+	remoteRepositoriesEndpoint    = "repositories?type=remote"
+	virtualRepositoriesEndpoint   = "repositories?type=virtual"
+	federatedRepositoriesEndpoint = "repositories?type=federated"
+)
+
+// Repository represents single element of API respond from repositories endpoint
+type Repository struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	PackageType string `json:"packageType"`
+	URL         string `json:"url"`
+}
+
+// Repositories represents API respond from repositories endpoint
+type Repositories struct {
+	Repositories []Repository
+	NodeId       string
+}
+
+// FetchRepositories makes the API call to repositories endpoint and returns
+// all repositories visible to the user.
+func (c *Client) FetchRepositories() (Repositories, error) {
+	c.logger.Debug("Fetching repositories")
+	return c.fetchRepositories(repositoriesEndpoint)
+}
+
+// FetchFederatedRepositories makes the API call to repositories endpoint for
+// the federated repositories.
+func (c *Client) FetchFederatedRepositories() (Repositories, error) {
+	c.logger.Debug("Fetching federated repositories")
+	return c.fetchRepositories(federatedRepositoriesEndpoint)
+}
+
+func (c *Client) fetchRepositories(endpoint string) (Repositories, error) {
+	var repositories Repositories
+	resp, err := c.FetchHTTP(endpoint)
+	if err != nil {
+		return repositories, err
+	}
+	repositories.NodeId = resp.NodeId
+
+	if err := c.unmarshalJSON(endpoint, resp.Body, &repositories.Repositories); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal repositories respond")
+		return repositories, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+	return repositories, nil
+}
+
+// RemoteRepository represents API respond from the repository configuration
+// endpoint of a remote repository
+type RemoteRepository struct {
+	Key         string `json:"key"`
+	PackageType string `json:"packageType"`
+	URL         string `json:"url"`
+	Offline     bool   `json:"offline"`
+}
+
+// RemoteRepositories represents the configuration of all remote repositories
+type RemoteRepositories struct {
+	Repositories []RemoteRepository
+	NodeId       string
+}
+
+// FetchRemoteRepositories makes the API call to repositories endpoint for the
+// remote repositories and then fetches the configuration of each of them.
+func (c *Client) FetchRemoteRepositories() (RemoteRepositories, error) {
+	var remoteRepositories RemoteRepositories
+	c.logger.Debug("Fetching remote repositories")
+	resp, err := c.FetchHTTP(remoteRepositoriesEndpoint)
+	if err != nil {
+		return remoteRepositories, err
+	}
+	remoteRepositories.NodeId = resp.NodeId
+
+	var repositories []Repository
+	if err := c.unmarshalJSON(remoteRepositoriesEndpoint, resp.Body, &repositories); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal remote repositories respond")
+		return remoteRepositories, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: remoteRepositoriesEndpoint,
+		}
+	}
+
+	remoteRepositories.Repositories = make([]RemoteRepository, len(repositories))
+	for i, repository := range repositories {
+		if err := c.fetchRepositoryConfig(repository.Key, &remoteRepositories.Repositories[i]); err != nil {
+			return remoteRepositories, err
+		}
+	}
+	return remoteRepositories, nil
+}
+
+// VirtualRepository represents API respond from the repository configuration
+// endpoint of a virtual repository
+type VirtualRepository struct {
+	Key          string   `json:"key"`
+	PackageType  string   `json:"packageType"`
+	Repositories []string `json:"repositories"`
+}
+
+// VirtualRepositories represents the configuration of all virtual repositories
+type VirtualRepositories struct {
+	Repositories []VirtualRepository
+	NodeId       string
+}
+
+// FetchVirtualRepositories makes the API call to repositories endpoint for the
+// virtual repositories and then fetches the configuration of each of them.
+func (c *Client) FetchVirtualRepositories() (VirtualRepositories, error) {
+	var virtualRepositories VirtualRepositories
+	c.logger.Debug("Fetching virtual repositories")
+	resp, err := c.FetchHTTP(virtualRepositoriesEndpoint)
+	if err != nil {
+		return virtualRepositories, err
+	}
+	virtualRepositories.NodeId = resp.NodeId
+
+	var repositories []Repository
+	if err := c.unmarshalJSON(virtualRepositoriesEndpoint, resp.Body, &repositories); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal virtual repositories respond")
+		return virtualRepositories, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: virtualRepositoriesEndpoint,
+		}
+	}
+
+	virtualRepositories.Repositories = make([]VirtualRepository, len(repositories))
+	for i, repository := range repositories {
+		if err := c.fetchRepositoryConfig(repository.Key, &virtualRepositories.Repositories[i]); err != nil {
+			return virtualRepositories, err
+		}
+	}
+	return virtualRepositories, nil
+}
+
+// RepositoryConfig represents API respond from the repository configuration
+// endpoint. Only the fields common to all repository types are included.
+type RepositoryConfig struct {
+	Key           string `json:"key"`
+	Rclass        string `json:"rclass"`
+	PackageType   string `json:"packageType"`
+	RepoLayoutRef string `json:"repoLayoutRef"`
+}
+
+// RepositoryConfigs represents the configuration of all repositories
+type RepositoryConfigs struct {
+	Repositories []RepositoryConfig
+	NodeId       string
+}
+
+// FetchRepositoryConfigs makes the API call to repositories endpoint and then
+// fetches the configuration of each repository.
+func (c *Client) FetchRepositoryConfigs() (RepositoryConfigs, error) {
+	var repositoryConfigs RepositoryConfigs
+	repositories, err := c.FetchRepositories()
+	if err != nil {
+		return repositoryConfigs, err
+	}
+	repositoryConfigs.NodeId = repositories.NodeId
+
+	c.logger.Debug("Fetching repository configurations")
+	repositoryConfigs.Repositories = make([]RepositoryConfig, len(repositories.Repositories))
+	for i, repository := range repositories.Repositories {
+		if err := c.fetchRepositoryConfig(repository.Key, &repositoryConfigs.Repositories[i]); err != nil {
+			return repositoryConfigs, err
+		}
+	}
+	return repositoryConfigs, nil
+}
+
+// fetchRepositoryConfig makes the API call to the configuration endpoint of
+// the repository with the given key and unmarshals the response into config.
+func (c *Client) fetchRepositoryConfig(key string, config any) error {
+	configEndpoint := fmt.Sprintf("%s/%s", repositoriesEndpoint, url.PathEscape(key))
+	resp, err := c.FetchHTTP(configEndpoint)
+	if err != nil {
+		return err
+	}
+	if err := c.unmarshalJSON(configEndpoint, resp.Body, config); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal repository configuration respond")
+		return &UnmarshalError{
+			message:  err.Error(),
+			endpoint: configEndpoint,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestFetchRepositories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/repositories" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		w.Write([]byte(`[
+			{"key": "libs-release", "type": "LOCAL", "url": "http://localhost:8081/artifactory/libs-release", "packageType": "Maven"},
+			{"key": "npm-remote", "type": "REMOTE", "url": "http://localhost:8081/artifactory/npm-remote", "packageType": "Npm"}
+		]`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	repositories, err := client.FetchRepositories()
+	if err != nil {
+		t.Fatalf("FetchRepositories() error = %v", err)
+	}
+	if repositories.NodeId != "test-node" {
+		t.Errorf("NodeId = %s, want test-node", repositories.NodeId)
+	}
+	expected := []Repository{
+		{Key: "libs-release", Type: "LOCAL", PackageType: "Maven", URL: "http://localhost:8081/artifactory/libs-release"},
+		{Key: "npm-remote", Type: "REMOTE", PackageType: "Npm", URL: "http://localhost:8081/artifactory/npm-remote"},
+	}
+	if len(repositories.Repositories) != len(expected) {
+		t.Fatalf("FetchRepositories() returned %d repositories, want %d", len(repositories.Repositories), len(expected))
+	}
+	for i, repo := range expected {
+		if repositories.Repositories[i] != repo {
+			t.Errorf("Repository %d = %+v, want %+v", i, repositories.Repositories[i], repo)
+		}
+	}
+}
+
+func TestFetchRemoteRepositories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		switch r.URL.Path {
+		case "/api/repositories":
+			if r.URL.Query().Get("type") != "remote" {
+				t.Errorf("Repositories requested with type %q, want remote", r.URL.Query().Get("type"))
+			}
+			w.Write([]byte(`[
+				{"key": "npm-remote", "type": "REMOTE", "url": "https://registry.npmjs.org", "packageType": "Npm"},
+				{"key": "maven-remote", "type": "REMOTE", "url": "https://repo1.maven.org/maven2", "packageType": "Maven"}
+			]`))
+		case "/api/repositories/npm-remote":
+			w.Write([]byte(`{"key": "npm-remote", "rclass": "remote", "packageType": "npm", "url": "https://registry.npmjs.org", "offline": true}`))
+		case "/api/repositories/maven-remote":
+			w.Write([]byte(`{"key": "maven-remote", "rclass": "remote", "packageType": "maven", "url": "https://repo1.maven.org/maven2", "offline": false}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	remoteRepositories, err := client.FetchRemoteRepositories()
+	if err != nil {
+		t.Fatalf("FetchRemoteRepositories() error = %v", err)
+	}
+	expected := []RemoteRepository{
+		{Key: "npm-remote", PackageType: "npm", URL: "https://registry.npmjs.org", Offline: true},
+		{Key: "maven-remote", PackageType: "maven", URL: "https://repo1.maven.org/maven2", Offline: false},
+	}
+	if len(remoteRepositories.Repositories) != len(expected) {
+		t.Fatalf("FetchRemoteRepositories() returned %d repositories, want %d", len(remoteRepositories.Repositories), len(expected))
+	}
+	for i, repo := range expected {
+		if remoteRepositories.Repositories[i] != repo {
+			t.Errorf("Repository %d = %+v, want %+v", i, remoteRepositories.Repositories[i], repo)
+		}
+	}
+}
+
+func TestFetchVirtualRepositories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		switch r.URL.Path {
+		case "/api/repositories":
+			if r.URL.Query().Get("type") != "virtual" {
+				t.Errorf("Repositories requested with type %q, want virtual", r.URL.Query().Get("type"))
+			}
+			w.Write([]byte(`[
+				{"key": "maven-virtual", "type": "VIRTUAL", "packageType": "Maven"},
+				{"key": "npm-virtual", "type": "VIRTUAL", "packageType": "Npm"}
+			]`))
+		case "/api/repositories/maven-virtual":
+			w.Write([]byte(`{"key": "maven-virtual", "rclass": "virtual", "packageType": "maven", "repositories": ["libs-release", "libs-snapshot", "maven-remote"]}`))
+		case "/api/repositories/npm-virtual":
+			w.Write([]byte(`{"key": "npm-virtual", "rclass": "virtual", "packageType": "npm", "repositories": []}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	virtualRepositories, err := client.FetchVirtualRepositories()
+	if err != nil {
+		t.Fatalf("FetchVirtualRepositories() error = %v", err)
+	}
+	expected := []VirtualRepository{
+		{Key: "maven-virtual", PackageType: "maven", Repositories: []string{"libs-release", "libs-snapshot", "maven-remote"}},
+		{Key: "npm-virtual", PackageType: "npm", Repositories: []string{}},
+	}
+	if len(virtualRepositories.Repositories) != len(expected) {
+		t.Fatalf("FetchVirtualRepositories() returned %d repositories, want %d", len(virtualRepositories.Repositories), len(expected))
+	}
+	for i, repo := range expected {
+		actual := virtualRepositories.Repositories[i]
+		if actual.Key != repo.Key || actual.PackageType != repo.PackageType || !slices.Equal(actual.Repositories, repo.Repositories) {
+			t.Errorf("Repository %d = %+v, want %+v", i, actual, repo)
+		}
+	}
+	if virtualRepositories.NodeId != "test-node" {
+		t.Errorf("NodeId = %q, want test-node", virtualRepositories.NodeId)
+	}
+}
+
+func TestFetchRepositoryConfigs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		switch r.URL.Path {
+		case "/api/repositories":
+			w.Write([]byte(`[
+				{"key": "libs-release", "type": "LOCAL", "packageType": "Maven"},
+				{"key": "generic-local", "type": "LOCAL", "packageType": "Generic"},
+				{"key": "npm-remote", "type": "REMOTE", "url": "https://registry.npmjs.org", "packageType": "Npm"}
+			]`))
+		case "/api/repositories/libs-release":
+			w.Write([]byte(`{"key": "libs-release", "rclass": "local", "packageType": "maven", "repoLayoutRef": "maven-2-default"}`))
+		case "/api/repositories/generic-local":
+			w.Write([]byte(`{"key": "generic-local", "rclass": "local", "packageType": "generic", "repoLayoutRef": "simple-default"}`))
+		case "/api/repositories/npm-remote":
+			w.Write([]byte(`{"key": "npm-remote", "rclass": "remote", "packageType": "npm", "repoLayoutRef": "npm-default", "url": "https://registry.npmjs.org"}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	repositoryConfigs, err := client.FetchRepositoryConfigs()
+	if err != nil {
+		t.Fatalf("FetchRepositoryConfigs() error = %v", err)
+	}
+	if repositoryConfigs.NodeId != "test-node" {
+		t.Errorf("NodeId = %s, want test-node", repositoryConfigs.NodeId)
+	}
+	expected := []RepositoryConfig{
+		{Key: "libs-release", Rclass: "local", PackageType: "maven", RepoLayoutRef: "maven-2-default"},
+		{Key: "generic-local", Rclass: "local", PackageType: "generic", RepoLayoutRef: "simple-default"},
+		{Key: "npm-remote", Rclass: "remote", PackageType: "npm", RepoLayoutRef: "npm-default"},
+	}
+	if len(repositoryConfigs.Repositories) != len(expected) {
+		t.Fatalf("FetchRepositoryConfigs() returned %d repositories, want %d", len(repositoryConfigs.Repositories), len(expected))
+	}
+	for i, repo := range expected {
+		if repositoryConfigs.Repositories[i] != repo {
+			t.Errorf("Repository %d = %+v, want %+v", i, repositoryConfigs.Repositories[i], repo)
+		}
+	}
+}
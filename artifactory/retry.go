@@ -0,0 +1,100 @@
+package artifactory
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultRetryWait is the time waited before the first retry of a request.
+// Every further retry waits one more multiple of it.
+const defaultRetryWait = time.Second
+
+// retrySubsystems map endpoint path prefixes to the subsystem whose retry
+// setting applies to their requests. The first matching prefix applies.
+var retrySubsystems = []struct {
+	prefix    string
+	subsystem string
+}{
+	{"system/ping", "ping"},
+	{"search/aql", "aql"},
+	{"access/api/v1/system/federation", "federation"},
+	{"federation/", "federation"},
+	{"access/", "access"},
+	{"system/", "system"},
+	{"router/", "system"},
+	{"storage", "storage"},
+	{"replication", "replication"},
+	{"repositories", "repositories"},
+	{"security/", "security"},
+	{"v2/security/", "security"},
+	{"docker/", "docker"},
+	{"pypi/", "pypi"},
+	{"tasks", "tasks"},
+	{"v1/metrics", "open_metrics"},
+}
+
+// requestSubsystem returns the subsystem of the request to fullPath, or an
+// empty string if it doesn't belong to any.
+func (c *Client) requestSubsystem(fullPath string) string {
+	path, ok := strings.CutPrefix(fullPath, c.apiURL(""))
+	if !ok {
+		path = strings.TrimPrefix(fullPath, c.platformURL(""))
+	}
+	for _, s := range retrySubsystems {
+		if strings.HasPrefix(path, s.prefix) {
+			return s.subsystem
+		}
+	}
+	return ""
+}
+
+// maxRetries returns the number of retries of the requests of subsystem. The
+// setting of the subsystem takes precedence over the global one.
+func (c *Client) maxRetries(subsystem string) int {
+	if retries, ok := c.subsystemRetries[subsystem]; ok {
+		return retries
+	}
+	return c.globalRetries
+}
+
+// retryable reports whether a request which failed with resp or err is worth
+// retrying. Network errors, rate limiting and server errors are retried.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// makeRequestWithRetries is like makeRequest, but retries failed requests up
+// to the number of retries of their subsystem. The response of the last
+// attempt is returned. Requests aborted with CancelRequests aren't retried.
+func (c *Client) makeRequestWithRetries(method string, path string, body []byte, headers **map[string]string) (*http.Response, error) {
+	subsystem := c.requestSubsystem(path)
+	maxRetries := c.maxRetries(subsystem)
+	for attempt := 1; ; attempt++ {
+		resp, err := c.makeRequest(method, path, body, headers)
+		if attempt > maxRetries || !retryable(resp, err) {
+			return resp, err
+		}
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+			resp.Body.Close()
+		}
+		c.logger.Debug(
+			"Retrying failed request",
+			"endpoint", path,
+			"subsystem", subsystem,
+			"attempt", attempt,
+			"max_retries", maxRetries,
+			"status", status,
+		)
+		select {
+		case <-c.ctx.Done():
+			return nil, c.ctx.Err()
+		case <-time.After(time.Duration(attempt) * c.retryWait):
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestRequestSubsystem(t *testing.T) {
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = "http://localhost:8081/artifactory"
+	client := NewClient(conf)
+
+	tests := []struct {
+		fullPath string
+		expected string
+	}{
+		{"http://localhost:8081/artifactory/api/system/ping", "ping"},
+		{"http://localhost:8081/artifactory/api/system/license", "system"},
+		{"http://localhost:8081/artifactory/api/search/aql", "aql"},
+		{"http://localhost:8081/artifactory/api/storageinfo", "storage"},
+		{"http://localhost:8081/artifactory/api/v2/security/permissions", "security"},
+		{"http://localhost:8081/access/api/v1/system/federation", "federation"},
+		{"http://localhost:8081/access/api/v1/tokens/me", "access"},
+		{"http://localhost:8081/router/api/v1/topology/health", "system"},
+		{"http://localhost:8081/artifactory/api/unknown", ""},
+	}
+	for _, tt := range tests {
+		if actual := client.requestSubsystem(tt.fullPath); actual != tt.expected {
+			t.Errorf("requestSubsystem(%s) = %q, want %q", tt.fullPath, actual, tt.expected)
+		}
+	}
+}
+
+func TestFetchHTTPRetries(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		n := requests[r.URL.Path]
+		mu.Unlock()
+		switch {
+		case r.URL.Path == "/api/system/license":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		case n <= 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"errors":[{"status":503,"message":"Service Unavailable"}]}`))
+		default:
+			w.Write([]byte(`OK`))
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.MaxRetries = 1
+	conf.SubsystemRetries = map[string]int{"ping": 3, "aql": 0}
+	client := NewClient(conf)
+	client.retryWait = 0
+
+	if _, err := client.FetchHTTP("system/ping"); err != nil {
+		t.Errorf("FetchHTTP(system/ping) error = %v", err)
+	}
+	if _, err := client.FetchHTTP("storageinfo"); err == nil {
+		t.Error("FetchHTTP(storageinfo): expected error but got none")
+	}
+	if _, err := client.QueryAQL([]byte(`items.find()`)); err == nil {
+		t.Error("QueryAQL(): expected error but got none")
+	}
+	if _, err := client.FetchHTTP("system/license"); err == nil {
+		t.Error("FetchHTTP(system/license): expected error but got none")
+	}
+
+	expected := map[string]int{
+		// Per-subsystem retries take precedence over the global setting.
+		"/api/system/ping": 3,
+		"/api/storageinfo": 2,
+		"/api/search/aql":  1,
+		// Client errors aren't retried.
+		"/api/system/license": 1,
+	}
+	for path, n := range expected {
+		if requests[path] != n {
+			t.Errorf("Server received %d requests to %s, want %d", requests[path], path, n)
+		}
+	}
+}
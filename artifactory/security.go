@@ -1,13 +1,17 @@
 package artifactory
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
 )
 
 const (
-	usersEndpoint        = "security/users"
-	groupsEndpoint       = "security/groups"
-	certificatesEndpoint = "system/security/certificates"
+	usersEndpoint         = "security/users"
+	groupsEndpoint        = "security/groups"
+	certificatesEndpoint  = "system/security/certificates"
+	permissionsV1Endpoint = "security/permissions"
+	permissionsV2Endpoint = "v2/security/permissions"
 )
 
 // User represents single element of API respond from users endpoint
@@ -20,20 +24,24 @@ type Users struct {
 	NodeId string
 }
 
-// FetchUsers makes the API call to users endpoint and returns []User
+// FetchUsers makes the API call to users endpoint and returns []User.
+// Paginated responses are followed until all pages have been fetched.
 func (c *Client) FetchUsers() (Users, error) {
 	var users Users
 	c.logger.Debug("Fetching users stats")
-	resp, err := c.FetchHTTP(usersEndpoint)
+	items, nodeId, err := c.fetchPages(usersEndpoint, "users")
 	if err != nil {
 		return users, err
 	}
-	users.NodeId = resp.NodeId
-	if err := json.Unmarshal(resp.Body, &users.Users); err != nil {
-		c.logger.Error("There was an issue when try to unmarshal users respond")
-		return users, &UnmarshalError{
-			message:  err.Error(),
-			endpoint: usersEndpoint,
+	users.NodeId = nodeId
+	users.Users = make([]User, len(items))
+	for i, item := range items {
+		if err := c.unmarshalJSON(usersEndpoint, item, &users.Users[i]); err != nil {
+			c.logger.Error("There was an issue when try to unmarshal users respond")
+			return users, &UnmarshalError{
+				message:  err.Error(),
+				endpoint: usersEndpoint,
+			}
 		}
 	}
 	return users, nil
@@ -49,20 +57,24 @@ type Groups struct {
 	NodeId string
 }
 
-// FetchGroups makes the API call to groups endpoint and returns []Group
+// FetchGroups makes the API call to groups endpoint and returns []Group.
+// Paginated responses are followed until all pages have been fetched.
 func (c *Client) FetchGroups() (Groups, error) {
 	var groups Groups
 	c.logger.Debug("Fetching groups stats")
-	resp, err := c.FetchHTTP(groupsEndpoint)
+	items, nodeId, err := c.fetchPages(groupsEndpoint, "groups")
 	if err != nil {
 		return groups, err
 	}
-	groups.NodeId = resp.NodeId
-	if err := json.Unmarshal(resp.Body, &groups.Groups); err != nil {
-		c.logger.Error("There was an issue when try to unmarshal groups respond")
-		return groups, &UnmarshalError{
-			message:  err.Error(),
-			endpoint: groupsEndpoint,
+	groups.NodeId = nodeId
+	groups.Groups = make([]Group, len(items))
+	for i, item := range items {
+		if err := c.unmarshalJSON(groupsEndpoint, item, &groups.Groups[i]); err != nil {
+			c.logger.Error("There was an issue when try to unmarshal groups respond")
+			return groups, &UnmarshalError{
+				message:  err.Error(),
+				endpoint: groupsEndpoint,
+			}
 		}
 	}
 
@@ -93,7 +105,7 @@ func (c *Client) FetchCertificates() (Certificates, error) {
 		return certs, err
 	}
 	certs.NodeId = resp.NodeId
-	if err := json.Unmarshal(resp.Body, &certs.Certificates); err != nil {
+	if err := c.unmarshalJSON(certificatesEndpoint, resp.Body, &certs.Certificates); err != nil {
 		c.logger.Error("There was an issue when try to unmarshal certificates response")
 		return certs, &UnmarshalError{
 			message:  err.Error(),
@@ -103,3 +115,87 @@ func (c *Client) FetchCertificates() (Certificates, error) {
 
 	return certs, nil
 }
+
+// PermissionTarget represents single element of API respond from permissions endpoint
+type PermissionTarget struct {
+	Name         string   `json:"name"`
+	URI          string   `json:"uri"`
+	Repositories []string `json:"-"`
+}
+
+type PermissionTargets struct {
+	PermissionTargets []PermissionTarget
+	NodeId            string
+}
+
+// permissionTargetV1 represents API respond from the v1 permission target details endpoint
+type permissionTargetV1 struct {
+	Repositories []string `json:"repositories"`
+}
+
+// permissionTargetV2 represents API respond from the v2 permission target details endpoint
+type permissionTargetV2 struct {
+	Repo struct {
+		Repositories []string `json:"repositories"`
+	} `json:"repo"`
+}
+
+// FetchPermissionTargets makes the API call to permissions endpoint and returns []PermissionTarget.
+// The v2 permissions API is preferred, falling back to v1 on instances where it doesn't exist.
+func (c *Client) FetchPermissionTargets() (PermissionTargets, error) {
+	var permissionTargets PermissionTargets
+	c.logger.Debug("Fetching permission targets stats")
+	endpoint := permissionsV2Endpoint
+	resp, err := c.FetchHTTP(endpoint)
+	if err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.status != 404 {
+			return permissionTargets, err
+		}
+		c.logger.Debug("Permissions v2 API is not available, falling back to v1")
+		endpoint = permissionsV1Endpoint
+		resp, err = c.FetchHTTP(endpoint)
+		if err != nil {
+			return permissionTargets, err
+		}
+	}
+	permissionTargets.NodeId = resp.NodeId
+	if err := c.unmarshalJSON(endpoint, resp.Body, &permissionTargets.PermissionTargets); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal permission targets respond")
+		return permissionTargets, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+
+	if c.OptionalMetrics.PermissionTargetRepos {
+		c.logger.Debug("Fetching permission targets repositories")
+		for i, permissionTarget := range permissionTargets.PermissionTargets {
+			detailsEndpoint := fmt.Sprintf("%s/%s", endpoint, url.PathEscape(permissionTarget.Name))
+			detailsResp, err := c.FetchHTTP(detailsEndpoint)
+			if err != nil {
+				return permissionTargets, err
+			}
+			var repositories []string
+			if endpoint == permissionsV2Endpoint {
+				var details permissionTargetV2
+				err = c.unmarshalJSON(detailsEndpoint, detailsResp.Body, &details)
+				repositories = details.Repo.Repositories
+			} else {
+				var details permissionTargetV1
+				err = c.unmarshalJSON(detailsEndpoint, detailsResp.Body, &details)
+				repositories = details.Repositories
+			}
+			if err != nil {
+				c.logger.Error("There was an issue when try to unmarshal permission target respond")
+				return permissionTargets, &UnmarshalError{
+					message:  err.Error(),
+					endpoint: detailsEndpoint,
+				}
+			}
+			permissionTargets.PermissionTargets[i].Repositories = repositories
+		}
+	}
+
+	return permissionTargets, nil
+}
@@ -0,0 +1,189 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchUsersPagination(t *testing.T) {
+	pages := map[string]string{
+		"":      `{"users":[{"name":"admin","realm":"internal"},{"name":"alice","realm":"ldap"}],"cursor":"page2"}`,
+		"page2": `{"users":[{"name":"bob","realm":"ldap"},{"name":"carol","realm":"saml"}],"cursor":"page3"}`,
+		"page3": `{"users":[{"name":"dave","realm":"internal"}]}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/security/users" {
+			t.Errorf("Expected request to /api/security/users, got %s", r.URL.Path)
+		}
+		body, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+			return
+		}
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		maxPages      int
+		expectedUsers int
+	}{
+		{
+			name:          "All pages aggregated",
+			maxPages:      0,
+			expectedUsers: 5,
+		},
+		{
+			name:          "Limit higher than number of pages",
+			maxPages:      10,
+			expectedUsers: 5,
+		},
+		{
+			name:          "Limit stops pagination",
+			maxPages:      2,
+			expectedUsers: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			conf.ArtiMaxPages = tt.maxPages
+			client := NewClient(conf)
+
+			users, err := client.FetchUsers()
+			if err != nil {
+				t.Fatalf("FetchUsers() error = %v", err)
+			}
+			if len(users.Users) != tt.expectedUsers {
+				t.Errorf("FetchUsers() returned %d users, want %d", len(users.Users), tt.expectedUsers)
+			}
+			if users.NodeId != "test-node" {
+				t.Errorf("Users.NodeId = %s, want test-node", users.NodeId)
+			}
+		})
+	}
+}
+
+func TestFetchUsersUnpaginated(t *testing.T) {
+	server := createTestServer(`[{"name":"admin","realm":"internal"},{"name":"alice","realm":"ldap"}]`, http.StatusOK)
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	users, err := client.FetchUsers()
+	if err != nil {
+		t.Fatalf("FetchUsers() error = %v", err)
+	}
+	if len(users.Users) != 2 {
+		t.Errorf("FetchUsers() returned %d users, want 2", len(users.Users))
+	}
+	if users.Users[1].Realm != "ldap" {
+		t.Errorf("Users[1].Realm = %s, want ldap", users.Users[1].Realm)
+	}
+}
+
+func TestFetchGroupsPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{"groups":[{"name":"readers","uri":"http://localhost/api/security/groups/readers"}],"cursor":"next"}`))
+		case "next":
+			w.Write([]byte(`{"groups":[{"name":"writers","uri":"http://localhost/api/security/groups/writers"}],"cursor":""}`))
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	groups, err := client.FetchGroups()
+	if err != nil {
+		t.Fatalf("FetchGroups() error = %v", err)
+	}
+	if len(groups.Groups) != 2 {
+		t.Errorf("FetchGroups() returned %d groups, want 2", len(groups.Groups))
+	}
+}
+
+func TestFetchPermissionTargets(t *testing.T) {
+	v2Responses := map[string]string{
+		"/api/v2/security/permissions":           `[{"name":"readers","uri":"http://localhost/api/v2/security/permissions/readers"},{"name":"deployers","uri":"http://localhost/api/v2/security/permissions/deployers"}]`,
+		"/api/v2/security/permissions/readers":   `{"name":"readers","repo":{"repositories":["libs-release","libs-snapshot","docker-local"],"actions":{"users":{"alice":["read"]}}}}`,
+		"/api/v2/security/permissions/deployers": `{"name":"deployers","repo":{"repositories":["libs-release"]}}`,
+	}
+	v1Responses := map[string]string{
+		"/api/security/permissions":         `[{"name":"readers","uri":"http://localhost/api/security/permissions/readers"}]`,
+		"/api/security/permissions/readers": `{"name":"readers","repositories":["libs-release","libs-snapshot"]}`,
+	}
+
+	tests := []struct {
+		name          string
+		responses     map[string]string
+		withRepos     bool
+		expectedRepos []int
+	}{
+		{
+			name:          "v2 API",
+			responses:     v2Responses,
+			expectedRepos: []int{0, 0},
+		},
+		{
+			name:          "v2 API with repositories",
+			responses:     v2Responses,
+			withRepos:     true,
+			expectedRepos: []int{3, 1},
+		},
+		{
+			name:          "v1 API fallback with repositories",
+			responses:     v1Responses,
+			withRepos:     true,
+			expectedRepos: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, ok := tt.responses[r.URL.Path]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			conf.ExporterRuntimeConfig.OptionalMetrics.PermissionTargetRepos = tt.withRepos
+			client := NewClient(conf)
+
+			permissionTargets, err := client.FetchPermissionTargets()
+			if err != nil {
+				t.Fatalf("FetchPermissionTargets() error = %v", err)
+			}
+			if len(permissionTargets.PermissionTargets) != len(tt.expectedRepos) {
+				t.Fatalf("FetchPermissionTargets() returned %d targets, want %d", len(permissionTargets.PermissionTargets), len(tt.expectedRepos))
+			}
+			for i, expected := range tt.expectedRepos {
+				if repos := len(permissionTargets.PermissionTargets[i].Repositories); repos != expected {
+					t.Errorf("PermissionTargets[%d] has %d repositories, want %d", i, repos, expected)
+				}
+			}
+		})
+	}
+}
@@ -1,13 +1,21 @@
 package artifactory
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 )
 
 const (
-	storageInfoEndpoint = "storageinfo"
+	storageInfoEndpoint          = "storageinfo"
+	storageInfoCalculateEndpoint = "storageinfo/calculate"
+	storageQuotaEndpoint         = "storage/quota"
 )
 
+// storageCalculatePollInterval is the interval in which the background tasks
+// are checked for a running storage summary calculation.
+var storageCalculatePollInterval = time.Second
+
 // StorageInfo represents API respond from license storageinfo
 type StorageInfo struct {
 	BinariesSummary struct {
@@ -35,19 +43,94 @@ type StorageInfo struct {
 		PackageType  string `json:"packageType"`
 		Percentage   string `json:"percentage"`
 	} `json:"repositoriesSummaryList"`
-	NodeId string
+	// LastUpdate is the time the storage summary was calculated as Unix
+	// timestamp in milliseconds. It is 0 if Artifactory doesn't report it.
+	LastUpdate int64 `json:"lastUpdate"`
+	NodeId     string
+}
+
+// CalculateStorageInfo triggers a recalculation of the storage summary and
+// waits until no storage summary calculation is running anymore, or the
+// storage calculation timeout expires. As the calculation runs asynchronously,
+// the background tasks are polled for its completion.
+func (c *Client) CalculateStorageInfo() error {
+	c.logger.Debug("Triggering storage info calculation")
+	fullPath := c.apiURL(storageInfoCalculateEndpoint)
+	resp, err := c.makeRequest("POST", fullPath, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if _, err := c.handleResponse(resp, fullPath); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(c.storageCalcTimeout)
+	for {
+		tasks, err := c.FetchBackgroundTasks()
+		if err != nil {
+			return err
+		}
+		running := false
+		for _, task := range tasks {
+			if task.IsStorageCalculation() && task.IsPending() {
+				running = true
+				break
+			}
+		}
+		if !running {
+			c.logger.Debug("Storage info calculation finished")
+			return nil
+		}
+		if time.Now().Add(storageCalculatePollInterval).After(deadline) {
+			return fmt.Errorf("storage info calculation did not finish within %s", c.storageCalcTimeout)
+		}
+		select {
+		case <-c.ctx.Done():
+			return c.ctx.Err()
+		case <-time.After(storageCalculatePollInterval):
+		}
+	}
 }
 
-// FetchStorageInfo makes the API call to storageinfo endpoint and returns StorageInfo
+// FetchStorageInfo makes the API call to storageinfo endpoint and returns StorageInfo.
+// If enabled, the storage summary is recalculated first.
 func (c *Client) FetchStorageInfo() (StorageInfo, error) {
 	var storageInfo StorageInfo
+	if c.storageCalculate {
+		if err := c.CalculateStorageInfo(); err != nil {
+			c.logger.Warn(
+				"Couldn't recalculate storage info, it may be stale",
+				"err", err.Error(),
+			)
+		}
+	}
 	c.logger.Debug("Fetching storage info stats")
+	// The storage info of instances with many repositories is large, so it's
+	// decoded while it's received. Streamed responses can't be cached, so
+	// it's read at once if the response cache is enabled.
+	if c.responseCache == nil {
+		stream, err := c.FetchHTTPStream(storageInfoEndpoint)
+		if err != nil {
+			return storageInfo, err
+		}
+		defer stream.Body.Close()
+		storageInfo.NodeId = stream.NodeId
+		if err := c.decodeJSON(storageInfoEndpoint, stream.Body, &storageInfo); err != nil {
+			c.logger.Error("There was an issue when try to decode storageInfo respond")
+			return storageInfo, &UnmarshalError{
+				message:  err.Error(),
+				endpoint: storageInfoEndpoint,
+			}
+		}
+		return storageInfo, nil
+	}
 	resp, err := c.FetchHTTP(storageInfoEndpoint)
 	if err != nil {
 		return storageInfo, err
 	}
 	storageInfo.NodeId = resp.NodeId
-	if err := json.Unmarshal(resp.Body, &storageInfo); err != nil {
+	if err := c.unmarshalJSON(storageInfoEndpoint, resp.Body, &storageInfo); err != nil {
 		c.logger.Error("There was an issue when try to unmarshal storageInfo respond")
 		return storageInfo, &UnmarshalError{
 			message:  err.Error(),
@@ -56,3 +139,45 @@ func (c *Client) FetchStorageInfo() (StorageInfo, error) {
 	}
 	return storageInfo, nil
 }
+
+// StorageQuota represents API respond from storage quota endpoint
+type StorageQuota struct {
+	Enabled                    bool `json:"enabled"`
+	DiskSpaceLimitPercentage   int  `json:"diskSpaceLimitPercentage"`
+	DiskSpaceWarningPercentage int  `json:"diskSpaceWarningPercentage"`
+	NodeId                     string
+}
+
+// LimitBytes returns the storage quota in bytes for a file store of the given
+// total size. The second return value is false when no quota is configured,
+// either because the quota is disabled or because it is unlimited.
+func (q StorageQuota) LimitBytes(totalSpace float64) (float64, bool) {
+	if !q.Enabled || q.DiskSpaceLimitPercentage <= 0 || q.DiskSpaceLimitPercentage >= 100 {
+		return 0, false
+	}
+	return totalSpace * float64(q.DiskSpaceLimitPercentage) / 100, true
+}
+
+// FetchStorageQuota makes the API call to storage quota endpoint and returns StorageQuota.
+// Instances which don't support storage quotas are reported as having the quota disabled.
+func (c *Client) FetchStorageQuota() (StorageQuota, error) {
+	var storageQuota StorageQuota
+	c.logger.Debug("Fetching storage quota")
+	resp, err := c.FetchHTTP(storageQuotaEndpoint)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.status == 404 {
+			return storageQuota, nil
+		}
+		return storageQuota, err
+	}
+	storageQuota.NodeId = resp.NodeId
+	if err := c.unmarshalJSON(storageQuotaEndpoint, resp.Body, &storageQuota); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal storage quota respond")
+		return storageQuota, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: storageQuotaEndpoint,
+		}
+	}
+	return storageQuota, nil
+}
@@ -0,0 +1,286 @@
+package artifactory
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchStorageQuota(t *testing.T) {
+	tests := []struct {
+		name          string
+		responseBody  string
+		responseCode  int
+		expectError   bool
+		expectQuota   bool
+		expectedLimit float64
+	}{
+		{
+			name:          "Quota configured",
+			responseBody:  `{"enabled":true,"diskSpaceLimitPercentage":80,"diskSpaceWarningPercentage":70}`,
+			responseCode:  http.StatusOK,
+			expectQuota:   true,
+			expectedLimit: 800,
+		},
+		{
+			name:         "Quota disabled",
+			responseBody: `{"enabled":false,"diskSpaceLimitPercentage":80,"diskSpaceWarningPercentage":70}`,
+			responseCode: http.StatusOK,
+			expectQuota:  false,
+		},
+		{
+			name:         "Unlimited quota",
+			responseBody: `{"enabled":true,"diskSpaceLimitPercentage":100}`,
+			responseCode: http.StatusOK,
+			expectQuota:  false,
+		},
+		{
+			name:         "Endpoint not available",
+			responseBody: `{"errors":[{"status":404,"message":"Not Found"}]}`,
+			responseCode: http.StatusNotFound,
+			expectQuota:  false,
+		},
+		{
+			name:         "Invalid JSON response",
+			responseBody: `{"enabled": yes}`,
+			responseCode: http.StatusOK,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createTestServer(tt.responseBody, tt.responseCode)
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			client := NewClient(conf)
+
+			quota, err := client.FetchStorageQuota()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchStorageQuota() error = %v", err)
+			}
+			limit, ok := quota.LimitBytes(1000)
+			if ok != tt.expectQuota {
+				t.Fatalf("LimitBytes() ok = %v, want %v", ok, tt.expectQuota)
+			}
+			if limit != tt.expectedLimit {
+				t.Errorf("LimitBytes() = %v, want %v", limit, tt.expectedLimit)
+			}
+		})
+	}
+}
+
+func TestCalculateStorageInfo(t *testing.T) {
+	storageCalculatePollInterval = 10 * time.Millisecond
+	defer func() { storageCalculatePollInterval = time.Second }()
+
+	runningTask := `{"tasks":[{"id":"1","type":"org.artifactory.storage.StorageSummaryJob","state":"running"}]}`
+	tests := []struct {
+		name          string
+		runningPolls  int32
+		timeout       time.Duration
+		expectError   bool
+		expectedPolls int32
+	}{
+		{
+			name:          "Calculation finishes",
+			runningPolls:  2,
+			timeout:       time.Second,
+			expectedPolls: 3,
+		},
+		{
+			name:         "Calculation times out",
+			runningPolls: 1000,
+			timeout:      50 * time.Millisecond,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var triggered atomic.Bool
+			var polls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/storageinfo/calculate":
+					if r.Method != http.MethodPost {
+						t.Errorf("Expected POST to calculate storage info, got %s", r.Method)
+					}
+					triggered.Store(true)
+					w.WriteHeader(http.StatusAccepted)
+				case "/api/tasks":
+					if polls.Add(1) <= tt.runningPolls {
+						w.Write([]byte(runningTask))
+						return
+					}
+					w.Write([]byte(`{"tasks":[]}`))
+				case "/api/storageinfo":
+					w.Write([]byte(`{"binariesSummary":{"binariesCount":"1"}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+				}
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL
+			conf.StorageCalculate = true
+			conf.StorageCalculateTimeout = tt.timeout
+			client := NewClient(conf)
+
+			err := client.CalculateStorageInfo()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else if err != nil {
+				t.Fatalf("CalculateStorageInfo() error = %v", err)
+			}
+			if !triggered.Load() {
+				t.Error("Storage info calculation was not triggered")
+			}
+			if tt.expectedPolls != 0 && polls.Load() != tt.expectedPolls {
+				t.Errorf("Background tasks were polled %d times, want %d", polls.Load(), tt.expectedPolls)
+			}
+
+			// A failed calculation doesn't prevent reading the storage info.
+			storageInfo, err := client.FetchStorageInfo()
+			if err != nil {
+				t.Fatalf("FetchStorageInfo() error = %v", err)
+			}
+			if storageInfo.BinariesSummary.BinariesCount != "1" {
+				t.Errorf("BinariesCount = %s, want 1", storageInfo.BinariesSummary.BinariesCount)
+			}
+		})
+	}
+}
+
+func TestFetchStorageInfoGzip(t *testing.T) {
+	body := `{
+		"binariesSummary": {"binariesCount": "125,726", "binariesSize": "3.48 GB", "artifactsSize": "59.77 GB", "optimization": "5.82%", "itemsCount": "2,176,580", "artifactsCount": "1,934,593"},
+		"repositoriesSummaryList": [
+			{"repoKey": "libs-release", "repoType": "LOCAL", "foldersCount": 10, "filesCount": 100, "usedSpace": "1 GB", "itemsCount": 110, "packageType": "Maven", "percentage": "100%"}
+		]
+	}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+			w.Write([]byte(body))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		gz.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	storageInfo, err := client.FetchStorageInfo()
+	if err != nil {
+		t.Fatalf("FetchStorageInfo() error = %v", err)
+	}
+	if storageInfo.BinariesSummary.ArtifactsCount != "1,934,593" {
+		t.Errorf("ArtifactsCount = %s, want 1,934,593", storageInfo.BinariesSummary.ArtifactsCount)
+	}
+	if len(storageInfo.RepositoriesSummaryList) != 1 || storageInfo.RepositoriesSummaryList[0].RepoKey != "libs-release" {
+		t.Errorf("RepositoriesSummaryList = %+v, want libs-release only", storageInfo.RepositoriesSummaryList)
+	}
+}
+
+func TestFetchStorageInfoStream(t *testing.T) {
+	const repoCount = 50000
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		w.Write([]byte(`{"binariesSummary": {"artifactsCount": "1,934,593"}, "repositoriesSummaryList": [`))
+		for i := range repoCount {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"repoKey": "repo-%d", "repoType": "LOCAL", "foldersCount": %d, "filesCount": %d, "usedSpace": "1 GB", "itemsCount": %d, "packageType": "Maven", "percentage": "0%%"}`, i, i, i, 2*i)
+		}
+		w.Write([]byte(`], "lastUpdate": 1700000000000}`))
+	}))
+	defer server.Close()
+
+	for _, useCache := range []bool{false, true} {
+		conf := createTestConfig()
+		conf.ArtiScrapeURI = server.URL
+		conf.UseCache = useCache
+		client := NewClient(conf)
+
+		storageInfo, err := client.FetchStorageInfo()
+		if err != nil {
+			t.Fatalf("FetchStorageInfo() with cache %v error = %v", useCache, err)
+		}
+		if storageInfo.NodeId != "test-node" {
+			t.Errorf("NodeId = %s, want test-node", storageInfo.NodeId)
+		}
+		if len(storageInfo.RepositoriesSummaryList) != repoCount {
+			t.Fatalf("RepositoriesSummaryList has %d repositories, want %d", len(storageInfo.RepositoriesSummaryList), repoCount)
+		}
+		last := storageInfo.RepositoriesSummaryList[repoCount-1]
+		if last.RepoKey != fmt.Sprintf("repo-%d", repoCount-1) || last.ItemsCount != 2*(repoCount-1) {
+			t.Errorf("Last repository = %+v", last)
+		}
+		if storageInfo.LastUpdate != 1700000000000 {
+			t.Errorf("LastUpdate = %d, want 1700000000000", storageInfo.LastUpdate)
+		}
+	}
+}
+
+func TestFetchHTTPStreamErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		case "/api/storageinfo":
+			// Stall after the first part of the body.
+			w.Write([]byte(`{"repositoriesSummaryList": [`))
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.ArtiTimeout = 200 * time.Millisecond
+	client := NewClient(conf)
+
+	_, err := client.FetchHTTPStream("missing")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.status != http.StatusNotFound {
+		t.Errorf("FetchHTTPStream() error = %v, want 404 API error", err)
+	}
+
+	start := time.Now()
+	if _, err := client.FetchStorageInfo(); err == nil {
+		t.Error("FetchStorageInfo() expected error for a stalled response but got none")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("FetchStorageInfo() returned after %s, want the timeout to apply while reading the body", elapsed)
+	}
+}
@@ -0,0 +1,157 @@
+package artifactory
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+var jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+// UnmarshalErrorHook is called with the normalized endpoint of every JSON
+// response which couldn't be parsed.
+type UnmarshalErrorHook func(endpoint string)
+
+// SetUnmarshalErrorHook enables counting of the JSON responses which couldn't
+// be parsed. It has to be called before the client is used.
+func (c *Client) SetUnmarshalErrorHook(hook UnmarshalErrorHook) {
+	c.unmarshalErrorHook = hook
+}
+
+// observeUnmarshalError reports a JSON response of endpoint which couldn't be
+// parsed to the unmarshal error hook, if any.
+func (c *Client) observeUnmarshalError(endpoint string) {
+	if c.unmarshalErrorHook != nil {
+		c.unmarshalErrorHook(normalizeEndpoint(endpoint))
+	}
+}
+
+// unmarshalJSON parses the JSON response of endpoint into v. In strict mode,
+// fields of the response v has no counterpart for are logged as a warning,
+// once per endpoint and set of fields, as they hint at a changed schema.
+func (c *Client) unmarshalJSON(endpoint string, data []byte, v any) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		c.observeUnmarshalError(endpoint)
+		return err
+	}
+	if c.strictJSON {
+		c.checkUnknownFields(endpoint, data, v)
+	}
+	return nil
+}
+
+// decodeJSON is like unmarshalJSON, but decodes the JSON response of endpoint
+// while it's read from r. In strict mode the response is read at once, as the
+// unknown fields are searched in the whole response.
+func (c *Client) decodeJSON(endpoint string, r io.Reader, v any) error {
+	if c.strictJSON {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return c.unmarshalJSON(endpoint, data, v)
+	}
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		c.observeUnmarshalError(endpoint)
+		return err
+	}
+	return nil
+}
+
+// checkUnknownFields logs the fields of data unknown to the type of v.
+func (c *Client) checkUnknownFields(endpoint string, data []byte, v any) {
+	t := reflect.TypeOf(v).Elem()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(reflect.New(t).Interface()); err == nil {
+		return
+	}
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return
+	}
+	fields := unknownJSONFields(raw, t, "")
+	if len(fields) == 0 {
+		return
+	}
+	slices.Sort(fields)
+	fields = slices.Compact(fields)
+	if _, reported := c.schemaDrift.LoadOrStore(endpoint+"\xff"+strings.Join(fields, ","), struct{}{}); reported {
+		return
+	}
+	c.logger.Warn(
+		"Unexpected fields in Artifactory response, its schema may have changed",
+		"endpoint", endpoint,
+		"fields", fields,
+	)
+}
+
+// unknownJSONFields returns the paths of the object keys of value, a decoded
+// JSON value, which have no matching field in t. Elements of arrays are
+// denoted by [], e.g. servers[].id. Types which unmarshal themselves aren't
+// inspected.
+func unknownJSONFields(value any, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	var unknown []string
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			switch t.Kind() {
+			case reflect.Struct:
+				field, ok := jsonField(t, key)
+				if !ok {
+					unknown = append(unknown, keyPath)
+					continue
+				}
+				unknown = append(unknown, unknownJSONFields(item, field.Type, keyPath)...)
+			case reflect.Map:
+				unknown = append(unknown, unknownJSONFields(item, t.Elem(), keyPath)...)
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, item := range v {
+				unknown = append(unknown, unknownJSONFields(item, t.Elem(), path+"[]")...)
+			}
+		}
+	}
+	return unknown
+}
+
+// jsonField returns the field of the struct type t the object key is
+// unmarshalled into, preferring an exact match of the name like encoding/json.
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var fold reflect.StructField
+	var folded bool
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if name == key {
+			return field, true
+		}
+		if !folded && strings.EqualFold(name, key) {
+			fold, folded = field, true
+		}
+	}
+	return fold, folded
+}
@@ -0,0 +1,102 @@
+package artifactory
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUnknownJSONFields(t *testing.T) {
+	type node struct {
+		Id    string `json:"id"`
+		State string `json:"state,omitempty"`
+	}
+	type response struct {
+		Nodes   []node            `json:"nodes"`
+		Labels  map[string]node   `json:"labels"`
+		Name    string            // matched by field name
+		Raw     json.RawMessage   `json:"raw"`
+		Ignored string            `json:"-"`
+		Extra   map[string]string `json:"extra"`
+	}
+	tests := []struct {
+		name     string
+		data     string
+		expected []string
+	}{
+		{
+			name: "Known fields",
+			data: `{"nodes": [{"id": "a", "state": "HEALTHY"}], "name": "x", "raw": {"anything": 1}, "extra": {"k": "v"}}`,
+		},
+		{
+			name:     "Top level field",
+			data:     `{"nodes": [], "version": "7.90"}`,
+			expected: []string{"version"},
+		},
+		{
+			name:     "Nested fields",
+			data:     `{"nodes": [{"id": "a", "role": "primary"}, {"id": "b", "role": "member", "zone": "eu"}], "labels": {"l": {"id": "c", "color": "red"}}}`,
+			expected: []string{"labels.l.color", "nodes[].role", "nodes[].zone"},
+		},
+		{
+			name:     "Ignored field",
+			data:     `{"Ignored": "x"}`,
+			expected: []string{"Ignored"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw any
+			if err := json.Unmarshal([]byte(tt.data), &raw); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			fields := unknownJSONFields(raw, reflect.TypeFor[response](), "")
+			slices.Sort(fields)
+			fields = slices.Compact(fields)
+			if !slices.Equal(fields, tt.expected) {
+				t.Errorf("unknownJSONFields() = %v, want %v", fields, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStrictJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": "7.90.0", "revision": "79000900", "edition": "pro"}`))
+	}))
+	defer server.Close()
+
+	for _, strict := range []bool{false, true} {
+		var buf bytes.Buffer
+		conf := createTestConfig()
+		conf.ArtiScrapeURI = server.URL
+		conf.StrictJSON = strict
+		conf.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+		client := NewClient(conf)
+
+		for range 2 {
+			buildInfo, err := client.FetchBuildInfo()
+			if err != nil {
+				t.Fatalf("FetchBuildInfo() error = %v", err)
+			}
+			if buildInfo.Version != "7.90.0" {
+				t.Errorf("Version = %s, want 7.90.0", buildInfo.Version)
+			}
+		}
+
+		warnings := strings.Count(buf.String(), "Unexpected fields in Artifactory response")
+		if strict && (warnings != 1 || !strings.Contains(buf.String(), "fields=[edition]")) {
+			t.Errorf("Expected a single warning about field edition, got log %q", buf.String())
+		}
+		if !strict && warnings != 0 {
+			t.Errorf("Expected no warning without strict mode, got log %q", buf.String())
+		}
+	}
+}
@@ -2,7 +2,9 @@ package artifactory
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"slices"
 	"strconv"
 	"strings"
@@ -14,6 +16,9 @@ const (
 	versionEndpoint  = "system/version"
 	licenseEndpoint  = "system/license"
 	licensesEndpoint = "system/licenses"
+	haNodesEndpoint  = "router/api/v1/topology/health"
+	routerEndpoint   = "router/api/v1/system/health"
+	haNodeHealthy    = "HEALTHY"
 )
 
 type HealthStatus struct {
@@ -41,11 +46,35 @@ func (c *Client) FetchHealth() (HealthStatus, error) {
 
 // BuildInfo represents API respond from version endpoint
 type BuildInfo struct {
-	Version  string   `json:"version"`
-	Revision string   `json:"revision"`
-	Addons   []string `json:"addons"`
-	License  string   `json:"license"`
-	NodeId   string
+	Version   string      `json:"version"`
+	Revision  string      `json:"revision"`
+	Addons    []string    `json:"addons"`
+	License   string      `json:"license"`
+	Uptime    json.Number `json:"uptime"`
+	StartTime string      `json:"startTime"`
+	NodeId    string
+}
+
+// UptimeSeconds returns the uptime of Artifactory at now. It's taken from
+// the uptime in seconds if present, or calculated from the start time
+// otherwise. The second return value is false if neither is reported,
+// which is the case on some editions.
+func (b BuildInfo) UptimeSeconds(now time.Time) (float64, bool) {
+	if b.Uptime != "" {
+		uptime, err := b.Uptime.Float64()
+		if err != nil {
+			return 0, false
+		}
+		return uptime, true
+	}
+	if b.StartTime != "" {
+		startTime, err := parseArtiTime(b.StartTime)
+		if err != nil {
+			return 0, false
+		}
+		return now.Sub(startTime).Seconds(), true
+	}
+	return 0, false
 }
 
 // FetchBuildInfo makes the API call to version endpoint and returns BuildInfo
@@ -57,7 +86,7 @@ func (c *Client) FetchBuildInfo() (BuildInfo, error) {
 		return buildInfo, err
 	}
 	buildInfo.NodeId = resp.NodeId
-	if err := json.Unmarshal(resp.Body, &buildInfo); err != nil {
+	if err := c.unmarshalJSON(versionEndpoint, resp.Body, &buildInfo); err != nil {
 		c.logger.Error("There was an issue when try to unmarshal buildInfo respond")
 		return buildInfo, &UnmarshalError{
 			message:  err.Error(),
@@ -128,7 +157,7 @@ func (c *Client) FetchLicense() (LicenseInfo, error) {
 		return licenseInfo, err
 	}
 	licenseInfo.NodeId = resp.NodeId
-	if err := json.Unmarshal(resp.Body, &licenseInfo); err != nil {
+	if err := c.unmarshalJSON(licenseEndpoint, resp.Body, &licenseInfo); err != nil {
 		c.logger.Error("There was an issue when trying to unmarshal licenseInfo response")
 		return licenseInfo, &UnmarshalError{
 			message:  err.Error(),
@@ -157,7 +186,7 @@ func (c *Client) FetchLicenses() (LicensesInfo, error) {
 	if err != nil {
 		return licensesInfo, err
 	}
-	if err := json.Unmarshal(resp.Body, &licensesInfo); err != nil {
+	if err := c.unmarshalJSON(licensesEndpoint, resp.Body, &licensesInfo); err != nil {
 		c.logger.Error("There was an issue when trying to unmarshal licensesInfo response")
 		return licensesInfo, &UnmarshalError{
 			message:  err.Error(),
@@ -166,3 +195,155 @@ func (c *Client) FetchLicenses() (LicensesInfo, error) {
 	}
 	return licensesInfo, nil
 }
+
+// HANode represents single element of API response from topology health endpoint
+type HANode struct {
+	Id      string `json:"id"`
+	State   string `json:"state"`
+	Message string `json:"message"`
+}
+
+// IsHealthy returns true if the node reports a healthy state.
+func (n HANode) IsHealthy() bool {
+	return strings.ToUpper(n.State) == haNodeHealthy
+}
+
+type HANodes struct {
+	Nodes  []HANode `json:"nodes"`
+	NodeId string
+}
+
+// IsHA returns true if the topology consists of more than one node.
+func (h HANodes) IsHA() bool {
+	return len(h.Nodes) > 1
+}
+
+// FetchHANodes makes the API call to topology health endpoint and returns HANodes.
+// Editions without the endpoint are reported as having no nodes.
+func (c *Client) FetchHANodes() (HANodes, error) {
+	var haNodes HANodes
+	c.logger.Debug("Fetching HA nodes stats")
+	resp, err := c.GetHTTP(haNodesEndpoint)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.status == 404 {
+			return haNodes, nil
+		}
+		return haNodes, err
+	}
+	haNodes.NodeId = resp.NodeId
+	if err := c.unmarshalJSON(haNodesEndpoint, resp.Body, &haNodes); err != nil {
+		c.logger.Error("There was an issue when trying to unmarshal HA nodes response")
+		return haNodes, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: haNodesEndpoint,
+		}
+	}
+	return haNodes, nil
+}
+
+// FetchHANodeURLs returns the base URI of every known HA node, keyed by node ID.
+// Node IDs are taken from the topology health endpoint and their URLs from the
+// licenses endpoint. If the topology is unavailable, all licensed nodes are returned.
+func (c *Client) FetchHANodeURLs() (map[string]string, error) {
+	c.logger.Debug("Fetching HA node URLs")
+	haNodes, err := c.FetchHANodes()
+	if err != nil {
+		return nil, err
+	}
+	licensesInfo, err := c.FetchLicenses()
+	if err != nil {
+		return nil, err
+	}
+
+	licensedURLs := make(map[string]string)
+	for _, license := range licensesInfo.Licenses {
+		if license.NodeId != "" && license.NodeUrl != "" {
+			licensedURLs[license.NodeId] = license.NodeUrl
+		}
+	}
+	if len(haNodes.Nodes) == 0 {
+		return licensedURLs, nil
+	}
+
+	nodeURLs := make(map[string]string)
+	for _, node := range haNodes.Nodes {
+		nodeURL, ok := licensedURLs[node.Id]
+		if !ok {
+			c.logger.Warn(
+				"Could not find URL of HA node",
+				"node_id", node.Id,
+			)
+			continue
+		}
+		nodeURLs[node.Id] = nodeURL
+	}
+	return nodeURLs, nil
+}
+
+// RouterService represents single element of API response from router health endpoint
+type RouterService struct {
+	ServiceId string `json:"service_id"`
+	NodeId    string `json:"node_id"`
+	State     string `json:"state"`
+	Message   string `json:"message"`
+}
+
+// IsHealthy returns true if the service reports a healthy state.
+func (s RouterService) IsHealthy() bool {
+	return strings.ToUpper(s.State) == haNodeHealthy
+}
+
+// RouterHealth represents API response from router health endpoint
+type RouterHealth struct {
+	Services []RouterService `json:"services"`
+	NodeId   string
+}
+
+// FetchRouterHealth makes the API call to router health endpoint and returns
+// the state of each JFrog Platform service. Deployments without the router,
+// or where it can't be reached, are reported as having no services.
+func (c *Client) FetchRouterHealth() (RouterHealth, error) {
+	var routerHealth RouterHealth
+	c.logger.Debug("Fetching router health stats")
+	resp, err := c.GetHTTP(routerEndpoint)
+	if err != nil {
+		if routerUnavailable(err) {
+			c.logger.Debug(
+				"The router health endpoint is unavailable",
+				"err", err.Error(),
+			)
+			return routerHealth, nil
+		}
+		return routerHealth, err
+	}
+	routerHealth.NodeId = resp.NodeId
+	if err := c.unmarshalJSON(routerEndpoint, resp.Body, &routerHealth); err != nil {
+		c.logger.Error("There was an issue when trying to unmarshal router health response")
+		return routerHealth, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: routerEndpoint,
+		}
+	}
+	return routerHealth, nil
+}
+
+// routerUnavailable reports whether err means the router health endpoint
+// isn't served, as on instances predating the router. Besides a 404, this is
+// a denied request or the error page of a proxy in front of the instance,
+// which either has no route to the router or responds with a non-JSON body.
+func routerUnavailable(err error) bool {
+	var unmarshalErr *UnmarshalError
+	if errors.Is(err, ErrAdminRequired) || errors.As(err, &unmarshalErr) {
+		return true
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.status {
+	case http.StatusNotFound, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchHANodes(t *testing.T) {
+	tests := []struct {
+		name            string
+		responseBody    string
+		responseCode    int
+		expectError     bool
+		expectHA        bool
+		expectedHealthy []bool
+	}{
+		{
+			name:            "Two nodes with one degraded",
+			responseBody:    `{"nodes":[{"id":"art1","state":"HEALTHY","message":"OK"},{"id":"art2","state":"DEGRADED","message":"Service jfac is unhealthy"}]}`,
+			responseCode:    http.StatusOK,
+			expectHA:        true,
+			expectedHealthy: []bool{true, false},
+		},
+		{
+			name:            "Single node",
+			responseBody:    `{"nodes":[{"id":"art1","state":"HEALTHY"}]}`,
+			responseCode:    http.StatusOK,
+			expectHA:        false,
+			expectedHealthy: []bool{true},
+		},
+		{
+			name:         "Endpoint not available",
+			responseBody: `{"errors":[{"status":404,"message":"Not Found"}]}`,
+			responseCode: http.StatusNotFound,
+			expectHA:     false,
+		},
+		{
+			name:         "Invalid JSON response",
+			responseBody: `{"nodes": [}`,
+			responseCode: http.StatusOK,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createTestServer(tt.responseBody, tt.responseCode)
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL + "/artifactory"
+			client := NewClient(conf)
+
+			haNodes, err := client.FetchHANodes()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchHANodes() error = %v", err)
+			}
+			if haNodes.IsHA() != tt.expectHA {
+				t.Errorf("IsHA() = %v, want %v", haNodes.IsHA(), tt.expectHA)
+			}
+			if len(haNodes.Nodes) != len(tt.expectedHealthy) {
+				t.Fatalf("FetchHANodes() returned %d nodes, want %d", len(haNodes.Nodes), len(tt.expectedHealthy))
+			}
+			for i, healthy := range tt.expectedHealthy {
+				if haNodes.Nodes[i].IsHealthy() != healthy {
+					t.Errorf("Nodes[%d].IsHealthy() = %v, want %v", i, haNodes.Nodes[i].IsHealthy(), healthy)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchRouterHealth(t *testing.T) {
+	tests := []struct {
+		name            string
+		responseBody    string
+		responseCode    int
+		expectError     bool
+		expectedHealthy []bool
+	}{
+		{
+			name:            "Services with one unhealthy",
+			responseBody:    `{"router":{"node_id":"art1","state":"HEALTHY","message":"OK"},"services":[{"service_id":"jfrt@01abc","node_id":"art1","state":"HEALTHY","message":"OK"},{"service_id":"jfac@01abc","node_id":"art1","state":"UNHEALTHY","message":"Service is not responding"}]}`,
+			responseCode:    http.StatusOK,
+			expectedHealthy: []bool{true, false},
+		},
+		{
+			name:         "Router not available",
+			responseBody: `{"errors":[{"status":404,"message":"Not Found"}]}`,
+			responseCode: http.StatusNotFound,
+		},
+		{
+			name:         "Router access denied",
+			responseBody: `{"errors":[{"status":403,"message":"Forbidden"}]}`,
+			responseCode: http.StatusForbidden,
+		},
+		{
+			name:         "Proxy error page",
+			responseBody: `<html><body>502 Bad Gateway</body></html>`,
+			responseCode: http.StatusBadGateway,
+		},
+		{
+			name:         "Server error",
+			responseBody: `{"errors":[{"status":500,"message":"Internal Server Error"}]}`,
+			responseCode: http.StatusInternalServerError,
+			expectError:  true,
+		},
+		{
+			name:         "Invalid JSON response",
+			responseBody: `{"services": [}`,
+			responseCode: http.StatusOK,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createTestServer(tt.responseBody, tt.responseCode)
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL + "/artifactory"
+			client := NewClient(conf)
+
+			routerHealth, err := client.FetchRouterHealth()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchRouterHealth() error = %v", err)
+			}
+			if len(routerHealth.Services) != len(tt.expectedHealthy) {
+				t.Fatalf("FetchRouterHealth() returned %d services, want %d", len(routerHealth.Services), len(tt.expectedHealthy))
+			}
+			for i, healthy := range tt.expectedHealthy {
+				if routerHealth.Services[i].IsHealthy() != healthy {
+					t.Errorf("Services[%d].IsHealthy() = %v, want %v", i, routerHealth.Services[i].IsHealthy(), healthy)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchHANodeURLs(t *testing.T) {
+	licenses := `{"licenses":[
+		{"type":"Enterprise","nodeId":"art1","nodeUrl":"http://10.0.0.1:8081/artifactory"},
+		{"type":"Enterprise","nodeId":"art2","nodeUrl":"http://10.0.0.2:8081/artifactory"},
+		{"type":"Enterprise","nodeId":"art3","nodeUrl":"http://10.0.0.3:8081/artifactory"}
+	]}`
+
+	tests := []struct {
+		name         string
+		topology     string
+		expectedURLs map[string]string
+	}{
+		{
+			name:     "Nodes from topology",
+			topology: `{"nodes":[{"id":"art1","state":"HEALTHY"},{"id":"art2","state":"HEALTHY"},{"id":"art4","state":"HEALTHY"}]}`,
+			expectedURLs: map[string]string{
+				"art1": "http://10.0.0.1:8081/artifactory",
+				"art2": "http://10.0.0.2:8081/artifactory",
+			},
+		},
+		{
+			name: "Topology not available",
+			expectedURLs: map[string]string{
+				"art1": "http://10.0.0.1:8081/artifactory",
+				"art2": "http://10.0.0.2:8081/artifactory",
+				"art3": "http://10.0.0.3:8081/artifactory",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/artifactory/api/system/licenses":
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(licenses))
+				case r.URL.Path == "/router/api/v1/topology/health" && tt.topology != "":
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(tt.topology))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+				}
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.ArtiScrapeURI = server.URL + "/artifactory"
+			client := NewClient(conf)
+
+			nodeURLs, err := client.FetchHANodeURLs()
+			if err != nil {
+				t.Fatalf("FetchHANodeURLs() error = %v", err)
+			}
+			if len(nodeURLs) != len(tt.expectedURLs) {
+				t.Fatalf("FetchHANodeURLs() returned %d nodes, want %d", len(nodeURLs), len(tt.expectedURLs))
+			}
+			for nodeId, expected := range tt.expectedURLs {
+				if nodeURLs[nodeId] != expected {
+					t.Errorf("URL of node %s = %s, want %s", nodeId, nodeURLs[nodeId], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/node2/artifactory/api/federation/status/mirrorsLag" {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		w.Header().Set("X-Artifactory-Node-Id", "art2")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"localRepoKey":"fed-local","remoteUrl":"http://remote/artifactory/api/federation/fed-remote","remoteRepoKey":"fed-remote","lagInMS":250}]`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL + "/artifactory"
+	client := NewClient(conf)
+	nodeClient := client.NodeClient(server.URL + "/node2/artifactory")
+
+	mirrorLags, err := nodeClient.FetchMirrorLags()
+	if err != nil {
+		t.Fatalf("FetchMirrorLags() error = %v", err)
+	}
+	if mirrorLags.NodeId != "art2" {
+		t.Errorf("MirrorLags.NodeId = %s, want art2", mirrorLags.NodeId)
+	}
+	if len(mirrorLags.MirrorLags) != 1 {
+		t.Errorf("FetchMirrorLags() returned %d lags, want 1", len(mirrorLags.MirrorLags))
+	}
+	if client.URI != conf.ArtiScrapeURI {
+		t.Errorf("NodeClient() modified the URI of the parent client to %s", client.URI)
+	}
+}
+
+func TestBuildInfoUptimeSeconds(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		responseBody   string
+		expectPresent  bool
+		expectedUptime float64
+	}{
+		{
+			name:           "Uptime in seconds",
+			responseBody:   `{"version":"7.77.5","revision":"77705900","uptime":86400}`,
+			expectPresent:  true,
+			expectedUptime: 86400,
+		},
+		{
+			name:           "Start timestamp",
+			responseBody:   `{"version":"7.77.5","revision":"77705900","startTime":"2024-05-01T11:00:00.000Z"}`,
+			expectPresent:  true,
+			expectedUptime: 3600,
+		},
+		{
+			name:          "Not reported",
+			responseBody:  `{"version":"7.77.5","revision":"77705900"}`,
+			expectPresent: false,
+		},
+		{
+			name:          "Invalid start timestamp",
+			responseBody:  `{"version":"7.77.5","revision":"77705900","startTime":"yesterday"}`,
+			expectPresent: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buildInfo BuildInfo
+			if err := json.Unmarshal([]byte(tt.responseBody), &buildInfo); err != nil {
+				t.Fatalf("Unmarshal error = %v", err)
+			}
+			uptime, ok := buildInfo.UptimeSeconds(now)
+			if ok != tt.expectPresent {
+				t.Fatalf("UptimeSeconds() present = %v, want %v", ok, tt.expectPresent)
+			}
+			if uptime != tt.expectedUptime {
+				t.Errorf("UptimeSeconds() = %v, want %v", uptime, tt.expectedUptime)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package artifactory
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// TracePhase is a phase of a request to Artifactory timed by the trace hook.
+type TracePhase string
+
+const (
+	TraceDNS     TracePhase = "dns"
+	TraceConnect TracePhase = "connect"
+	TraceTLS     TracePhase = "tls"
+)
+
+// TraceHook is called with the duration of every phase of a request. Phases
+// which don't happen, e.g. on reused connections, aren't reported.
+type TraceHook func(phase TracePhase, duration time.Duration)
+
+// SetTraceHook enables tracing of the requests to Artifactory. It has to be
+// called before the client is used.
+func (c *Client) SetTraceHook(hook TraceHook) {
+	c.traceHook = hook
+}
+
+// traceRequest returns req with a client trace reporting to the trace hook
+// attached, or req itself if tracing is disabled.
+func (c *Client) traceRequest(req *http.Request) *http.Request {
+	if c.traceHook == nil {
+		return req
+	}
+	var mutex sync.Mutex
+	var dnsStart, tlsStart time.Time
+	connectStarts := make(map[string]time.Time)
+	since := func(start *time.Time) time.Duration {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return time.Since(*start)
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			mutex.Lock()
+			dnsStart = time.Now()
+			mutex.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			c.traceHook(TraceDNS, since(&dnsStart))
+		},
+		ConnectStart: func(network, addr string) {
+			mutex.Lock()
+			connectStarts[network+addr] = time.Now()
+			mutex.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			mutex.Lock()
+			start, ok := connectStarts[network+addr]
+			mutex.Unlock()
+			if ok && err == nil {
+				c.traceHook(TraceConnect, time.Since(start))
+			}
+		},
+		TLSHandshakeStart: func() {
+			mutex.Lock()
+			tlsStart = time.Now()
+			mutex.Unlock()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				c.traceHook(TraceTLS, since(&tlsStart))
+			}
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
@@ -0,0 +1,47 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTraceHook(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	client := NewClient(conf)
+
+	var mutex sync.Mutex
+	phases := make(map[TracePhase]int)
+	client.SetTraceHook(func(phase TracePhase, duration time.Duration) {
+		if duration < 0 {
+			t.Errorf("Phase %s took %v, want a positive duration", phase, duration)
+		}
+		mutex.Lock()
+		phases[phase]++
+		mutex.Unlock()
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.FetchHTTP(pingEndpoint); err != nil {
+			t.Fatalf("FetchHTTP() error = %v", err)
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, phase := range []TracePhase{TraceDNS, TraceConnect, TraceTLS} {
+		// The connection of the first request is reused by the second one.
+		if phases[phase] != 1 {
+			t.Errorf("Phase %s was traced %d times, want 1", phase, phases[phase])
+		}
+	}
+}
@@ -3,9 +3,12 @@ package artifactory
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"slices"
 	"strings"
 )
@@ -26,6 +29,13 @@ type ApiResponse struct {
 	NodeId string
 }
 
+// ApiStream represents a successful API response whose body hasn't been read
+// yet. The body must be closed by the caller.
+type ApiStream struct {
+	Body   io.ReadCloser
+	NodeId string
+}
+
 var (
 	httpSuccCodes = []int{ // https://go.dev/src/net/http/status.go
 		http.StatusOK,                   // 200
@@ -41,8 +51,31 @@ var (
 	}
 )
 
+// setAuthHeader sets the header authenticating req with the configured auth
+// method. The custom auth header is sent in addition or instead, if set.
+func (c *Client) setAuthHeader(req *http.Request) error {
+	if c.customAuthHeader != nil {
+		req.Header.Set(c.customAuthHeader.Name, c.customAuthHeader.Value)
+		if c.customAuthHeader.Replace {
+			return nil
+		}
+	}
+	switch c.authMethod {
+	case "userPass":
+		req.SetBasicAuth(c.cred.Username, c.cred.Password)
+	case "accessToken":
+		req.Header.Add("Authorization", "Bearer "+c.cred.AccessToken)
+	case "apiKey":
+		req.Header.Set("X-JFrog-Art-Api", c.cred.APIKey)
+	case "anonymous":
+	default:
+		return fmt.Errorf("Artifactory Auth (%s) method is not supported", c.authMethod)
+	}
+	return nil
+}
+
 func (c *Client) makeRequest(method string, path string, body []byte, headers **map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest(method, path, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(c.ctx, method, path, bytes.NewBuffer(body))
 	if err != nil {
 		c.logger.Error(
 			"There was an error creating request",
@@ -50,24 +83,23 @@ func (c *Client) makeRequest(method string, path string, body []byte, headers **
 		)
 		return nil, err
 	}
-	switch c.authMethod {
-	case "userPass":
-		req.SetBasicAuth(c.cred.Username, c.cred.Password)
-	case "accessToken":
-		req.Header.Add("Authorization", "Bearer "+c.cred.AccessToken)
-	default:
-		return nil, fmt.Errorf("Artifactory Auth (%s) method is not supported", c.authMethod)
+	// Accept-Encoding is left to the transport, which then requests gzip
+	// compressed responses and decompresses them transparently.
+	req.Header.Set("User-Agent", c.userAgent)
+	if err := c.setAuthHeader(req); err != nil {
+		return nil, err
 	}
 	if headers != nil {
 		for key, value := range **headers {
 			req.Header.Set(key, value)
 		}
 	}
-	return c.client.Do(req)
+	return c.doRequest(req)
 }
 
 func (c *Client) handleResponse(resp *http.Response, fullPath string) (*ApiResponse, error) {
 	var apiErrors APIErrors
+	c.recordCertExpiry(resp)
 	bodyBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		c.logger.Error(
@@ -76,6 +108,20 @@ func (c *Client) handleResponse(resp *http.Response, fullPath string) (*ApiRespo
 		)
 		return nil, err
 	}
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		location := resp.Header.Get("Location")
+		c.logger.Error(
+			"Artifactory redirected the request, which isn't followed",
+			"endpoint", fullPath,
+			"location", location,
+			"status", resp.StatusCode,
+		)
+		return nil, &APIError{
+			message:  fmt.Sprintf("redirected to %s", location),
+			endpoint: fullPath,
+			status:   resp.StatusCode,
+		}
+	}
 	if !slices.Contains(httpSuccCodes, resp.StatusCode) {
 		if err := json.Unmarshal(bodyBytes, &apiErrors); err != nil {
 			c.logger.Error(
@@ -87,6 +133,21 @@ func (c *Client) handleResponse(resp *http.Response, fullPath string) (*ApiRespo
 				endpoint: fullPath,
 			}
 		}
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			// Callers decide whether the endpoint is required, so denied
+			// requests of optional metrics don't log errors every scrape.
+			c.logger.Debug(
+				"Access to the endpoint was denied",
+				"endpoint", fullPath,
+				"err", fmt.Sprintf("%v", apiErrors.Errors),
+				"status", resp.StatusCode,
+			)
+			return nil, &APIError{
+				message:  fmt.Sprintf("%v", apiErrors.Errors),
+				endpoint: fullPath,
+				status:   resp.StatusCode,
+			}
+		}
 		if resp.StatusCode == http.StatusNotFound {
 			c.logger.Warn(
 				"The endpoint does not exist",
@@ -109,7 +170,7 @@ func (c *Client) handleResponse(resp *http.Response, fullPath string) (*ApiRespo
 		return nil, &APIError{
 			message:  fmt.Sprintf("%v", apiErrors.Errors),
 			endpoint: fullPath,
-			// status:   resp.StatusCode, // Maybe it would be worth returning it too? As with http.StatusNotFound.
+			status:   resp.StatusCode,
 		}
 	}
 
@@ -126,7 +187,7 @@ func (c *Client) makeCachedRequest(method string, path string, body []byte, head
 
 	go func() {
 		defer cached.AbortTimeout()
-		resp, err := c.makeRequest(method, path, body, headers)
+		resp, err := c.makeRequestWithRetries(method, path, body, headers)
 		if err != nil {
 			c.logger.Error(
 				logMsgErrAPICall,
@@ -148,7 +209,11 @@ func (c *Client) makeCachedRequest(method string, path string, body []byte, head
 
 	select {
 	case err := <-cached.errors:
-		c.logger.Warn(
+		log := c.logger.Warn
+		if errors.Is(err, ErrAdminRequired) {
+			log = c.logger.Debug
+		}
+		log(
 			"Error while making request, fallback to cache",
 			"method", method,
 			"path", path,
@@ -165,19 +230,96 @@ func (c *Client) makeCachedRequest(method string, path string, body []byte, head
 	}
 }
 
+// joinURL joins a base URI with path elements using exactly one slash between
+// each of them, so base URIs with a subpath and/or a trailing slash work alike.
+// A trailing slash of the last element is kept, as some endpoints require it.
+func joinURL(base string, elems ...string) string {
+	joined := strings.TrimRight(base, "/")
+	for _, elem := range elems {
+		joined += "/" + strings.Trim(elem, "/")
+	}
+	if len(elems) > 0 && strings.HasSuffix(elems[len(elems)-1], "/") {
+		joined += "/"
+	}
+	return joined
+}
+
+// apiURL returns the full URL of an Artifactory API endpoint.
+func (c *Client) apiURL(path string) string {
+	return joinURL(c.URI, "api", path)
+}
+
+// platformURL returns the full URL of an endpoint outside of the Artifactory
+// API, relative to the JFrog Platform URI (the scrape URI without "/artifactory").
+func (c *Client) platformURL(path string) string {
+	platformURI := strings.TrimSuffix(strings.TrimRight(c.URI, "/"), "/artifactory")
+	return joinURL(platformURI, path)
+}
+
 // FetchHTTP is a wrapper function for making all Get API calls
 func (c *Client) FetchHTTP(path string) (*ApiResponse, error) {
-	fullPath := fmt.Sprintf("%s/api/%s", c.URI, path)
+	fullPath := c.apiURL(path)
+	if !c.circuitBreaker.Allow(path) {
+		c.logger.Debug(
+			"Circuit is open, skipping request",
+			"path", fullPath,
+		)
+		return nil, &CircuitOpenError{endpoint: fullPath}
+	}
 	c.logger.Debug(
 		"Fetching http",
 		"path", fullPath,
 	)
-	return c.makeCachedRequest("GET", fullPath, nil, nil)
+	resp, err := c.makeCachedRequest("GET", fullPath, nil, nil)
+	c.circuitBreaker.Report(path, err)
+	return resp, err
+}
+
+// FetchHTTPStream is like FetchHTTP, but returns the body of a successful
+// response unread, so large responses can be decoded while they're received
+// instead of being held in memory. Streamed responses aren't cached. The
+// timeout of the client keeps applying while the body is read. The caller
+// must close the body.
+func (c *Client) FetchHTTPStream(path string) (*ApiStream, error) {
+	fullPath := c.apiURL(path)
+	if !c.circuitBreaker.Allow(path) {
+		c.logger.Debug(
+			"Circuit is open, skipping request",
+			"path", fullPath,
+		)
+		return nil, &CircuitOpenError{endpoint: fullPath}
+	}
+	c.logger.Debug(
+		"Fetching http stream",
+		"path", fullPath,
+	)
+	resp, err := c.makeRequestWithRetries("GET", fullPath, nil, nil)
+	if err != nil {
+		c.logger.Error(
+			logMsgErrAPICall,
+			"endpoint", fullPath,
+			"err", err.Error(),
+		)
+		c.circuitBreaker.Report(path, err)
+		return nil, err
+	}
+	if !slices.Contains(httpSuccCodes, resp.StatusCode) {
+		defer resp.Body.Close()
+		_, err := c.handleResponse(resp, fullPath)
+		c.circuitBreaker.Report(path, err)
+		return nil, err
+	}
+	c.recordCertExpiry(resp)
+	c.circuitBreaker.Report(path, nil)
+	return &ApiStream{
+		Body:   resp.Body,
+		NodeId: resp.Header.Get("X-Artifactory-Node-Id"),
+	}, nil
 }
 
 // QueryAQL is a wrapper function for making an query to AQL endpoint
 func (c *Client) QueryAQL(query []byte) (*ApiResponse, error) {
-	fullPath := fmt.Sprintf("%s/api/search/aql", c.URI)
+	fullPath := c.apiURL(aqlEndpoint)
 	c.logger.Debug(
 		"Running AQL query",
 		"path", fullPath,
@@ -185,14 +327,98 @@ func (c *Client) QueryAQL(query []byte) (*ApiResponse, error) {
 	return c.makeCachedRequest("POST", fullPath, query, nil)
 }
 
+// GetHTTP is a wrapper function for making Get API calls outside of the Artifactory API
+// Note: the API endpoint (e.g. "/artifactory" or "/access") needs to be part of path
+func (c *Client) GetHTTP(path string) (*ApiResponse, error) {
+	fullPath := c.platformURL(path)
+	c.logger.Debug(
+		"Fetching http",
+		"path", fullPath,
+	)
+	return c.makeCachedRequest("GET", fullPath, nil, nil)
+}
+
 // PostHTTP is a wrapper function for making all Post API calls
 // Note: the API endpoint (e.g. "/artifactory" or "/access") needs to be part of path
 func (c *Client) PostHTTP(path string, body []byte, headers *map[string]string) (*ApiResponse, error) {
-	artifactoryURI := strings.TrimSuffix(c.URI, "/artifactory")
-	fullPath := fmt.Sprintf("%s/%s", artifactoryURI, path)
+	fullPath := c.platformURL(path)
 	c.logger.Debug(
 		"Posting http",
 		"path", fullPath,
 	)
 	return c.makeCachedRequest("POST", fullPath, body, &headers)
 }
+
+// pageEnvelope represents a single page of a paginated API response. The items
+// are stored under an endpoint specific key, so they are kept raw until the
+// key is known.
+type pageEnvelope map[string]json.RawMessage
+
+// fetchPages follows the continuation cursor of a paginated endpoint until it
+// is exhausted or the configured page limit is reached. Endpoints which respond
+// with a bare JSON array are not paginated and are returned as a single page.
+func (c *Client) fetchPages(endpoint string, itemsKey string) ([]json.RawMessage, string, error) {
+	return c.fetchPagesWith(c.FetchHTTP, endpoint, itemsKey)
+}
+
+// fetchPagesWith is like fetchPages, but fetches every page with fetch. It's
+// used for paginated endpoints outside of the Artifactory API.
+func (c *Client) fetchPagesWith(fetch func(path string) (*ApiResponse, error), endpoint string, itemsKey string) ([]json.RawMessage, string, error) {
+	var items []json.RawMessage
+	var nodeId string
+	path := endpoint
+	for page := 1; ; page++ {
+		resp, err := fetch(path)
+		if err != nil {
+			return nil, nodeId, err
+		}
+		nodeId = resp.NodeId
+
+		var pageItems []json.RawMessage
+		if err := json.Unmarshal(resp.Body, &pageItems); err == nil {
+			return append(items, pageItems...), nodeId, nil
+		}
+
+		var envelope pageEnvelope
+		if err := json.Unmarshal(resp.Body, &envelope); err != nil {
+			c.observeUnmarshalError(endpoint)
+			return nil, nodeId, &UnmarshalError{
+				message:  err.Error(),
+				endpoint: path,
+			}
+		}
+		if raw, ok := envelope[itemsKey]; ok {
+			if err := json.Unmarshal(raw, &pageItems); err != nil {
+				c.observeUnmarshalError(endpoint)
+				return nil, nodeId, &UnmarshalError{
+					message:  err.Error(),
+					endpoint: path,
+				}
+			}
+			items = append(items, pageItems...)
+		}
+
+		var cursor string
+		if raw, ok := envelope["cursor"]; ok {
+			if err := json.Unmarshal(raw, &cursor); err != nil {
+				c.observeUnmarshalError(endpoint)
+				return nil, nodeId, &UnmarshalError{
+					message:  err.Error(),
+					endpoint: path,
+				}
+			}
+		}
+		if cursor == "" {
+			return items, nodeId, nil
+		}
+		if c.maxPages > 0 && page >= c.maxPages {
+			c.logger.Warn(
+				"Reached maximum number of pages, results may be incomplete",
+				"endpoint", endpoint,
+				"max_pages", c.maxPages,
+			)
+			return items, nodeId, nil
+		}
+		path = fmt.Sprintf("%s?cursor=%s", endpoint, url.QueryEscape(cursor))
+	}
+}
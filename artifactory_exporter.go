@@ -1,15 +1,15 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/version"
 
-	"github.com/peimanja/artifactory_exporter/collector"
 	"github.com/peimanja/artifactory_exporter/config"
 	"github.com/peimanja/artifactory_exporter/logger"
 )
@@ -24,7 +24,18 @@ func main() {
 		os.Exit(1)
 	}
 
-	exporter, err := collector.NewExporter(conf)
+	if conf.Validate {
+		if err := validate(conf, os.Stdout); err != nil {
+			conf.Logger.Error(
+				"Configuration validation failed",
+				"err", err.Error(),
+			)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	exporter, err := newReloadableExporter(conf)
 	if err != nil {
 		conf.Logger.Error(
 			"Error creating an exporter",
@@ -32,8 +43,25 @@ func main() {
 		)
 		os.Exit(1)
 	}
-	collector.InitMetrics(exporter)
-	prometheus.MustRegister(exporter)
+	defaultHandler, gatherer := metricsHandler(conf.DisableDefaultMetrics, exporter)
+	handler := repoQueryHandler(defaultHandler, conf.InstanceLabel, exporter.ForRepo)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	if conf.Oneshot {
+		if err := pushMetrics(ctx, conf.PushGateway, conf.PushJob, conf.PushGrouping, gatherer); err != nil {
+			conf.Logger.Error(
+				"Error pushing metrics to the Pushgateway",
+				"err", err.Error(),
+			)
+			os.Exit(1)
+		}
+		conf.Logger.Info(
+			"Pushed metrics to the Pushgateway",
+			"url", conf.PushGateway,
+			"job", conf.PushJob,
+		)
+		os.Exit(0)
+	}
 	conf.Logger.Info(
 		"Starting artifactory_exporter",
 		"version", version.Info(),
@@ -46,36 +74,82 @@ func main() {
 		"Listening on address",
 		"address", conf.ListenAddress,
 	)
-	http.HandleFunc(conf.MetricsPath, func(w http.ResponseWriter, r *http.Request) {
+	handleMetricsPaths(http.DefaultServeMux, conf.MetricsPaths, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		conf.Logger.Debug(
 			"Prometheus scrape",
+			"path", r.URL.Path,
 			"remote", r.RemoteAddr,
 			"user_agent", r.UserAgent(),
+			"repo", r.URL.Query().Get("repo"),
 		)
-		promhttp.Handler().ServeHTTP(w, r)
-	})
+		handler.ServeHTTP(w, r)
+	}))
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte(`<html>
              <head><title>JFrog Artifactory Exporter</title></head>
              <body>
              <h1>JFrog Exporter</h1>
-             <p><a href='` + conf.MetricsPath + `'>Metrics</a></p>
+             <p><a href='` + conf.MetricsPaths[0] + `'>Metrics</a></p>
              </body>
              </html>`))
 	})
-	http.HandleFunc("/-/healthy", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, "OK")
-	})
-	http.HandleFunc("/-/ready", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, "OK")
-	})
-	if err := http.ListenAndServe(conf.ListenAddress, nil); err != nil {
+	probeMux := http.DefaultServeMux
+	if conf.HealthListenAddress != "" {
+		probeMux = http.NewServeMux()
+	}
+	handleProbes(probeMux)
+	if conf.LogLevelEndpoint {
+		http.HandleFunc("/-/loglevel", logLevelHandler(conf.LogLevel, conf.Logger))
+	}
+	if conf.FederationDebugEndpoint {
+		http.HandleFunc("/debug/federation", federationDebugHandler(exporter.Exporter, conf.Logger))
+	}
+	ln, err := net.Listen("tcp", conf.ListenAddress)
+	if err != nil {
 		conf.Logger.Error(
 			"Error starting HTTP server",
 			"err", err.Error(),
 		)
 		os.Exit(1)
 	}
+	listeners := []listener{{srv: &http.Server{Addr: conf.ListenAddress}, ln: ln}}
+	if conf.HealthListenAddress != "" {
+		healthLn, err := net.Listen("tcp", conf.HealthListenAddress)
+		if err != nil {
+			conf.Logger.Error(
+				"Error starting health probe HTTP server",
+				"err", err.Error(),
+			)
+			os.Exit(1)
+		}
+		conf.Logger.Info(
+			"Listening for health probes on address",
+			"address", conf.HealthListenAddress,
+		)
+		listeners = append(listeners, listener{srv: &http.Server{Addr: conf.HealthListenAddress, Handler: probeMux}, ln: healthLn})
+	}
+	if conf.GraphiteAddress != "" {
+		bridge, err := newGraphiteBridge(conf.GraphiteAddress, conf.GraphitePrefix, conf.GraphiteInterval, gatherer, conf.Logger)
+		if err != nil {
+			conf.Logger.Error(
+				"Error creating the Graphite bridge",
+				"err", err.Error(),
+			)
+			os.Exit(1)
+		}
+		conf.Logger.Info(
+			"Pushing metrics to Graphite",
+			"address", conf.GraphiteAddress,
+			"interval", conf.GraphiteInterval,
+		)
+		go bridge.Run(ctx)
+	}
+	go reloadOnSignal(ctx, conf, exporter)
+	if err := runServers(ctx, listeners, conf.ShutdownTimeout, exporter.CancelRequests, conf.Logger); err != nil {
+		conf.Logger.Error(
+			"Error running HTTP server",
+			"err", err.Error(),
+		)
+		os.Exit(1)
+	}
 }
@@ -1,7 +1,11 @@
 package collector
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
 )
 
 func (e *Exporter) exportAccessFederationValidate(ch chan<- prometheus.Metric) error {
@@ -22,6 +26,111 @@ func (e *Exporter) exportAccessFederationValidate(ch chan<- prometheus.Metric) e
 		"metric", "accessFederationValid",
 		"value", value,
 	)
-	ch <- prometheus.MustNewConstMetric(accessMetrics["accessFederationValid"], prometheus.GaugeValue, value, accessFederationValid.NodeId)
+	ch <- prometheus.MustNewConstMetric(e.metrics.access["accessFederationValid"], prometheus.GaugeValue, value, accessFederationValid.NodeId)
 	return nil
 }
+
+// exportAccessFederation exports the number of servers in the JFrog Access
+// Federation (Circle of Trust) and whether trust towards each of them can be
+// validated. Nothing is exported if Access Federation isn't configured.
+func (e *Exporter) exportAccessFederation(ch chan<- prometheus.Metric) bool {
+	accessFederation, err := e.client.FetchAccessFederation()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching JFrog Access Federation servers",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	if !accessFederation.Configured {
+		e.logger.Debug("JFrog Access Federation is not configured")
+		return true
+	}
+
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "servers",
+		"value", len(accessFederation.Servers),
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.accessFed["servers"], prometheus.GaugeValue, float64(len(accessFederation.Servers)), accessFederation.NodeId)
+	for _, server := range accessFederation.Servers {
+		reachable := true
+		if err := e.client.ValidateAccessFederationServer(server.URL); err != nil {
+			e.logger.Warn(
+				"JFrog Access Federation server could not be validated",
+				"server_id", server.Id,
+				"url", server.URL,
+				"err", err.Error(),
+			)
+			reachable = false
+		}
+		value := convArtiToPromBool(reachable)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "serverReachable",
+			"server_id", server.Id,
+			"value", value,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.accessFed["serverReachable"], prometheus.GaugeValue, value, server.Id, server.URL, accessFederation.NodeId)
+	}
+	return true
+}
+
+// activeTokens returns the tokens which haven't expired at now. Tokens without
+// expiry never expire.
+func activeTokens(tokens []artifactory.TokenInfo, now time.Time) []artifactory.TokenInfo {
+	active := make([]artifactory.TokenInfo, 0, len(tokens))
+	for _, token := range tokens {
+		if token.Expiry == 0 || token.Expiry > now.Unix() {
+			active = append(active, token)
+		}
+	}
+	return active
+}
+
+// countTokensExpiringWithin returns the number of active tokens expiring
+// within the time window from now. Tokens without expiry aren't counted.
+func countTokensExpiringWithin(tokens []artifactory.TokenInfo, now time.Time, within time.Duration) int {
+	count := 0
+	for _, token := range activeTokens(tokens, now) {
+		if token.Expiry != 0 && token.Expiry <= now.Add(within).Unix() {
+			count++
+		}
+	}
+	return count
+}
+
+// exportAccessTokens exports the number of active access tokens and the number
+// of them expiring within each configured time window.
+func (e *Exporter) exportAccessTokens(ch chan<- prometheus.Metric) bool {
+	tokens, err := e.client.FetchActiveTokens()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching access tokens",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+
+	now := time.Now()
+	active := activeTokens(tokens.Tokens, now)
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "total",
+		"value", len(active),
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.token["total"], prometheus.GaugeValue, float64(len(active)), tokens.NodeId)
+	for _, window := range e.exporterRuntimeConfig.TokensExpiringWindows {
+		expiring := countTokensExpiringWithin(active, now, window.Interval)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "expiring",
+			"within", window.ShortPeriod,
+			"value", expiring,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.token["expiring"], prometheus.GaugeValue, float64(expiring), window.ShortPeriod, tokens.NodeId)
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestCountTokensExpiringWithin(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tokens := []artifactory.TokenInfo{
+		{TokenId: "expired", Expiry: now.Add(-time.Hour).Unix()},
+		{TokenId: "no-expiry", Expiry: 0},
+		{TokenId: "in-1h", Expiry: now.Add(time.Hour).Unix()},
+		{TokenId: "in-3d", Expiry: now.Add(72 * time.Hour).Unix()},
+		{TokenId: "in-30d", Expiry: now.Add(720 * time.Hour).Unix()},
+	}
+
+	if active := activeTokens(tokens, now); len(active) != 4 {
+		t.Errorf("activeTokens() returned %d tokens, want 4", len(active))
+	}
+
+	tests := []struct {
+		within   time.Duration
+		expected int
+	}{
+		{within: time.Minute, expected: 0},
+		{within: 24 * time.Hour, expected: 1},
+		{within: 168 * time.Hour, expected: 2},
+		{within: 8760 * time.Hour, expected: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.within.String(), func(t *testing.T) {
+			if count := countTokensExpiringWithin(tokens, now, tt.within); count != tt.expected {
+				t.Errorf("countTokensExpiringWithin() = %d, want %d", count, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportAccessFederation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/access/api/v1/system/federation":
+			w.Write([]byte(`[{"id": "jpd-eu", "url": "https://eu.example.com/access", "active": true}, {"id": "jpd-us", "url": "https://us.example.com/access", "active": true}]`))
+		case "/access/api/v1/system/federation/validate_server":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["url"] != "https://eu.example.com/access" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errors":[{"code":"BAD_REQUEST","message":"Server is not reachable"}]}`))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 10)
+	if !e.exportAccessFederation(ch) {
+		t.Fatal("exportAccessFederation() = false, want true")
+	}
+	close(ch)
+	var servers float64
+	reachable := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		switch metric.Desc() {
+		case e.metrics.accessFed["servers"]:
+			servers = m.GetGauge().GetValue()
+		case e.metrics.accessFed["serverReachable"]:
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "server_id" {
+					reachable[label.GetValue()] = m.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	if servers != 2 {
+		t.Errorf("Access federation servers = %v, want 2", servers)
+	}
+	expected := map[string]float64{"jpd-eu": 1, "jpd-us": 0}
+	if len(reachable) != len(expected) {
+		t.Fatalf("Reachable servers = %v, want %v", reachable, expected)
+	}
+	for id, value := range expected {
+		if reachable[id] != value {
+			t.Errorf("Reachable of %s = %v, want %v", id, reachable[id], value)
+		}
+	}
+}
@@ -25,11 +25,16 @@ func (e *Exporter) findArtifacts(period string, queryType string) (artifactQuery
 		"period", period,
 		"queryType", queryType,
 	)
+	// Collections restricted to a single repository only query its artifacts.
+	var repoCriteria string
+	if e.onlyRepo != "" {
+		repoCriteria = fmt.Sprintf("\"repo\" : %q, ", e.onlyRepo)
+	}
 	switch queryType {
 	case "created":
-		query = fmt.Sprintf("items.find({\"modified\" : {\"$last\" : \"%s\"}}).include(\"name\", \"repo\")", period)
+		query = fmt.Sprintf("items.find({%s\"modified\" : {\"$last\" : \"%s\"}}).include(\"name\", \"repo\")", repoCriteria, period)
 	case "downloaded":
-		query = fmt.Sprintf("items.find({\"stat.downloaded\" : {\"$last\" : \"%s\"}}).include(\"name\", \"repo\")", period)
+		query = fmt.Sprintf("items.find({%s\"stat.downloaded\" : {\"$last\" : \"%s\"}}).include(\"name\", \"repo\")", repoCriteria, period)
 	default:
 		e.logger.Error(
 			"Query Type is not supported",
@@ -110,7 +115,7 @@ func (e *Exporter) exportArtifacts(repoSummaries []repoSummary, ch chan<- promet
 				"package_type", repoSummary.PackageType,
 				"value", repoArtifactsSummary.TotalCreated,
 			)
-			createdMetric := artifactsMetrics[createdMetricName]
+			createdMetric := e.metrics.artifacts[createdMetricName]
 			ch <- prometheus.MustNewConstMetric(createdMetric, prometheus.GaugeValue, repoArtifactsSummary.TotalCreated, repoSummary.Name, repoSummary.Type, repoSummary.PackageType, repoSummary.NodeId)
 
 			e.logger.Debug(
@@ -121,8 +126,45 @@ func (e *Exporter) exportArtifacts(repoSummaries []repoSummary, ch chan<- promet
 				"package_type", repoSummary.PackageType,
 				"value", repoArtifactsSummary.TotalDownloaded,
 			)
-			downloadedMetric := artifactsMetrics[downloadedMetricName]
+			downloadedMetric := e.metrics.artifacts[downloadedMetricName]
 			ch <- prometheus.MustNewConstMetric(downloadedMetric, prometheus.GaugeValue, repoArtifactsSummary.TotalDownloaded, repoSummary.Name, repoSummary.Type, repoSummary.PackageType, repoSummary.NodeId)
 		}
 	}
 }
+
+// exportArtifactsCreatedRecent exports the number of artifacts created in all
+// repositories within each configured time window. AQL can't count, so the
+// query of a window returns at most recentMaxResults artifacts and the count
+// is capped at that limit. It returns false if any window couldn't be counted.
+func (e *Exporter) exportArtifactsCreatedRecent(ch chan<- prometheus.Metric) bool {
+	ok := true
+	for _, window := range e.exporterRuntimeConfig.ArtifactsRecentWindows {
+		criteria := fmt.Sprintf("{\"type\" : \"file\", \"created\" : {\"$last\" : \"%s\"}}", window.Period)
+		created, err := e.client.FindItemsLimit(criteria, e.recentMaxResults, "name")
+		if err != nil {
+			e.logger.Error(
+				"Couldn't scrape Artifactory when counting recently created artifacts",
+				"window", window.ShortPeriod,
+				"err", err.Error(),
+			)
+			e.totalAPIErrors.Inc()
+			ok = false
+			continue
+		}
+		if len(created.Results) >= e.recentMaxResults {
+			e.logger.Warn(
+				"Number of recently created artifacts reached the limit of the AQL query",
+				"window", window.ShortPeriod,
+				"limit", e.recentMaxResults,
+			)
+		}
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "createdRecent",
+			"window", window.ShortPeriod,
+			"value", len(created.Results),
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.recent["createdRecent"], prometheus.GaugeValue, float64(len(created.Results)), window.ShortPeriod, created.NodeId)
+	}
+	return ok
+}
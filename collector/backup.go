@@ -0,0 +1,48 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// exportBackups exports the number of configured and enabled backups and
+// whether each of them is enabled. Artifactory doesn't expose the backup history, so the
+// status of past backup runs isn't available.
+func (e *Exporter) exportBackups(ch chan<- prometheus.Metric) bool {
+	backups, err := e.client.FetchBackups()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching system/configuration",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "configured",
+		"value", len(backups.Backups),
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.backup["configured"], prometheus.GaugeValue, float64(len(backups.Backups)), backups.NodeId)
+	enabledTotal := 0
+	for _, backup := range backups.Backups {
+		if backup.Enabled {
+			enabledTotal++
+		}
+		enabled := convArtiToPromBool(backup.Enabled)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "enabled",
+			"key", backup.Key,
+			"value", enabled,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.backup["enabled"], prometheus.GaugeValue, enabled, backup.Key, backup.CronExp, backups.NodeId)
+	}
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "enabledTotal",
+		"value", enabledTotal,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.backup["enabledTotal"], prometheus.GaugeValue, float64(enabledTotal), backups.NodeId)
+	return true
+}
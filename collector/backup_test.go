@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportBackups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<config><backups>
+			<backup><key>backup-daily</key><enabled>true</enabled><cronExp>0 0 2 ? * MON-FRI</cronExp></backup>
+			<backup><key>backup-weekly</key><enabled>true</enabled><cronExp>0 0 2 ? * SAT</cronExp></backup>
+			<backup><key>backup-monthly</key><enabled>false</enabled><cronExp>0 0 2 1 * ?</cronExp></backup>
+		</backups></config>`))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 5)
+	if !e.exportBackups(ch) {
+		t.Fatal("exportBackups() = false, want true")
+	}
+	close(ch)
+	actual := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		for _, name := range []string{"configured", "enabledTotal"} {
+			if metric.Desc() == e.metrics.backup[name] {
+				actual[name] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	if actual["configured"] != 3 {
+		t.Errorf("backup_configured = %v, want 3", actual["configured"])
+	}
+	if actual["enabledTotal"] != 2 {
+		t.Errorf("backups_enabled_total = %v, want 2", actual["enabledTotal"])
+	}
+}
@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// missingChecksumCriteria matches the files without a SHA-256 checksum, e.g.
+// those stored before the SHA-256 migration which it didn't complete for.
+const missingChecksumCriteria = `"type" : "file", "$or" : [{"sha256" : {"$eq" : null}}, {"sha256" : {"$eq" : ""}}]`
+
+// exportMissingChecksums exports the number of artifacts without a SHA-256
+// checksum in every repository. A single AQL query across all repositories
+// returns at most checksumMaxResults artifacts, so the counts are capped at
+// that limit in total. Repositories without such artifacts aren't exported.
+func (e *Exporter) exportMissingChecksums(ch chan<- prometheus.Metric) bool {
+	criteria := "{" + missingChecksumCriteria + "}"
+	if e.onlyRepo != "" {
+		criteria = fmt.Sprintf("{\"repo\" : %q, %s}", e.onlyRepo, missingChecksumCriteria)
+	}
+	result, err := e.client.FindItemsLimit(criteria, e.checksumMaxResults, "repo")
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when counting artifacts without checksum",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	if len(result.Results) >= e.checksumMaxResults {
+		e.logger.Warn(
+			"Number of artifacts without checksum reached the limit of the AQL query",
+			"limit", e.checksumMaxResults,
+		)
+	}
+	missing := make(map[string]int)
+	for _, item := range result.Results {
+		repo, ok := e.repoLabel(item.Repo)
+		if !ok {
+			continue
+		}
+		missing[repo]++
+	}
+	for repo, count := range missing {
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "missingChecksum",
+			"repo", repo,
+			"value", count,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.checksum["missing"], prometheus.GaugeValue, float64(count), repo, result.NodeId)
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportMissingChecksums(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		query := string(body)
+		if !strings.HasSuffix(r.URL.Path, "/api/search/aql") || !strings.Contains(query, `"sha256"`) || !strings.HasSuffix(query, ".limit(100)") {
+			t.Errorf("Unexpected request %s with query %s", r.URL.Path, query)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"results": [
+			{"repo": "libs-release"},
+			{"repo": "libs-release"},
+			{"repo": "libs-snapshot"},
+			{"repo": "tmp-builds"}
+		], "range": {"total": 4}}`))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:    server.URL + "/artifactory",
+		ArtiTimeout:      5 * time.Second,
+		MetricsNamespace: defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{
+			RepoFilter: config.RepoFilter{
+				Denylist:      regexp.MustCompile("^tmp-.*"),
+				DropUnmatched: true,
+			},
+		},
+		ChecksumMaxResults: 100,
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 10)
+	if !e.exportMissingChecksums(ch) {
+		t.Fatal("exportMissingChecksums() = false, want true")
+	}
+	close(ch)
+	actual := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "repo" {
+				actual[label.GetValue()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	expected := map[string]float64{"libs-release": 2, "libs-snapshot": 1}
+	if len(actual) != len(expected) {
+		t.Fatalf("Artifacts without checksum = %v, want %v", actual, expected)
+	}
+	for repo, count := range expected {
+		if actual[repo] != count {
+			t.Errorf("Artifacts without checksum in %s = %v, want %v", repo, actual[repo], count)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// exportCleanupEligible exports the number of artifacts older than the
+// cleanup age in every configured repository. The AQL query of a repository
+// returns at most cleanupMaxResults artifacts, so the count is capped at that
+// limit. It returns false if any repository couldn't be counted.
+func (e *Exporter) exportCleanupEligible(ch chan<- prometheus.Metric) bool {
+	ok := true
+	age := e.exporterRuntimeConfig.CleanupAge
+	for _, repo := range e.cleanupRepos {
+		if e.onlyRepo != "" && repo != e.onlyRepo {
+			continue
+		}
+		criteria := fmt.Sprintf("{\"repo\" : %q, \"type\" : \"file\", \"created\" : {\"$before\" : \"%s\"}}", repo, age.Period)
+		eligible, err := e.client.FindItemsLimit(criteria, e.cleanupMaxResults, "name")
+		if err != nil {
+			e.logger.Error(
+				"Couldn't scrape Artifactory when counting artifacts eligible for cleanup",
+				"repo", repo,
+				"err", err.Error(),
+			)
+			e.totalAPIErrors.Inc()
+			ok = false
+			continue
+		}
+		count := len(eligible.Results)
+		if count >= e.cleanupMaxResults {
+			e.logger.Warn(
+				"Number of artifacts eligible for cleanup reached the limit of the AQL query",
+				"repo", repo,
+				"limit", e.cleanupMaxResults,
+			)
+		}
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "eligible",
+			"repo", repo,
+			"age", age.ShortPeriod,
+			"value", count,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.cleanup["eligible"], prometheus.GaugeValue, float64(count), repo, eligible.NodeId)
+	}
+	return ok
+}
@@ -0,0 +1,84 @@
+package collector
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportCleanupEligible(t *testing.T) {
+	results := map[string]string{
+		"libs-release":  `{"results": [{"name": "a.jar"}], "range": {"total": 1}}`,
+		"libs-snapshot": `{"results": [{"name": "a.jar"}, {"name": "b.jar"}], "range": {"total": 2}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		query := string(body)
+		if !strings.HasSuffix(r.URL.Path, "/api/search/aql") || !strings.HasSuffix(query, ".limit(2)") {
+			t.Errorf("Unexpected request %s with query %s", r.URL.Path, query)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for repo, result := range results {
+			if strings.Contains(query, `"repo" : "`+repo+`"`) {
+				w.Write([]byte(result))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		CleanupRepos:          []string{"libs-release", "libs-snapshot"},
+		CleanupMaxResults:     2,
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 10)
+	if !e.exportCleanupEligible(ch) {
+		t.Fatal("exportCleanupEligible() = false, want true")
+	}
+	close(ch)
+	actual := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "repo" {
+				actual[label.GetValue()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	expected := map[string]float64{"libs-release": 1, "libs-snapshot": 2}
+	if len(actual) != len(expected) {
+		t.Fatalf("Artifacts eligible for cleanup = %v, want %v", actual, expected)
+	}
+	for repo, count := range expected {
+		if actual[repo] != count {
+			t.Errorf("Artifacts eligible for cleanup in %s = %v, want %v", repo, actual[repo], count)
+		}
+	}
+}
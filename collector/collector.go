@@ -1,14 +1,19 @@
 package collector
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
 )
 
 const (
-	namespace = "artifactory"
+	defaultNamespace = "artifactory"
 )
 
 // Label sets reused across metrics for consistency and reduced duplication
@@ -17,132 +22,507 @@ var (
 	filestoreLabelNames   = append([]string{"storage_type", "storage_dir"}, defaultLabelNames...)
 	repoLabelNames        = append([]string{"name", "type", "package_type"}, defaultLabelNames...)
 	replicationLabelNames = append([]string{"name", "type", "url", "cron_exp", "status"}, defaultLabelNames...)
+	replicationLagLabels  = append([]string{"name", "type", "url"}, defaultLabelNames...)
 	federationLabelNames  = append([]string{"name", "remote_url", "remote_name"}, defaultLabelNames...)
 	certificateLabelNames = append([]string{"alias", "issued_by", "expires"}, defaultLabelNames...)
 )
 
 // Helper for creating new metric descriptors
-func newMetric(metricName string, subsystem string, docString string, labelNames []string) *prometheus.Desc {
+func newMetric(namespace string, metricName string, subsystem string, docString string, labelNames []string) *prometheus.Desc {
 	return prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, metricName), docString, labelNames, nil)
 }
 
 type metrics map[string]*prometheus.Desc
 
-// Metric descriptor groups by subsystem
-var (
-	replicationMetrics = metrics{
-		"enabled": newMetric("enabled", "replication", "Replication status for an Artifactory repository (1 = enabled).", replicationLabelNames),
+// metricDescriptors are the metric descriptor groups by subsystem of an
+// exporter.
+type metricDescriptors struct {
+	replication metrics
+	security    metrics
+	storage     metrics
+	system      metrics
+	artifacts   metrics
+	federation  metrics
+	open        metrics
+	access      metrics
+	ha          metrics
+	exporter    metrics
+	trashcan    metrics
+	docker      metrics
+	conversion  metrics
+	service     metrics
+	recent      metrics
+	ssl         metrics
+	backup      metrics
+	drift       metrics
+	token       metrics
+	jvm         metrics
+	remoteRepo  metrics
+	gc          metrics
+	layout      metrics
+	accessFed   metrics
+	repo        metrics
+	cleanup     metrics
+	checksum    metrics
+	config      metrics
+	delta       metrics
+	virtual     metrics
+	bundle      metrics
+}
+
+// newMetricDescriptors creates the descriptors of all metric groups within
+// namespace.
+func newMetricDescriptors(namespace string) metricDescriptors {
+	var d metricDescriptors
+	d.replication = metrics{
+		"enabled": newMetric(namespace, "enabled", "replication", "Replication status for an Artifactory repository (1 = enabled).", replicationLabelNames),
+		"lag":     newMetric(namespace, "lag_seconds", "replication", "Seconds the last successful replication is behind the last modification of the source repository.", replicationLagLabels),
+		"failed":  newMetric(namespace, "last_run_failed", "replication", "Did the last run of the replication fail (1 = failed).", replicationLagLabels),
+		"targets": newMetric(namespace, "targets_total", "replication", "Number of replication targets configured for an Artifactory repository.", append([]string{"name"}, defaultLabelNames...)),
 	}
 
-	securityMetrics = metrics{
-		"users":        newMetric("users", "security", "Number of Artifactory users for each realm.", append([]string{"realm"}, defaultLabelNames...)),
-		"groups":       newMetric("groups", "security", "Number of Artifactory groups", defaultLabelNames),
-		"certificates": newMetric("certificates", "security", "Internal SSL certificate information, seconds to expiration as value", certificateLabelNames),
+	d.security = metrics{
+		"users":                 newMetric(namespace, "users", "security", "Number of Artifactory users for each realm.", append([]string{"realm"}, defaultLabelNames...)),
+		"groups":                newMetric(namespace, "groups", "security", "Number of Artifactory groups", defaultLabelNames),
+		"certificates":          newMetric(namespace, "certificates", "security", "Internal SSL certificate information, seconds to expiration as value", certificateLabelNames),
+		"permissionTargets":     newMetric(namespace, "permission_targets_total", "security", "Number of Artifactory permission targets.", defaultLabelNames),
+		"permissionTargetRepos": newMetric(namespace, "permission_target_repos", "security", "Number of repositories covered by an Artifactory permission target.", append([]string{"name"}, defaultLabelNames...)),
 	}
 
-	storageMetrics = metrics{
-		"artifacts":      newMetric("artifacts", "storage", "Total artifacts count stored in Artifactory.", defaultLabelNames),
-		"artifactsSize":  newMetric("artifacts_size_bytes", "storage", "Total artifacts Size stored in Artifactory in bytes.", defaultLabelNames),
-		"binaries":       newMetric("binaries", "storage", "Total binaries count stored in Artifactory.", defaultLabelNames),
-		"binariesSize":   newMetric("binaries_size_bytes", "storage", "Total binaries Size stored in Artifactory in bytes.", defaultLabelNames),
-		"filestore":      newMetric("filestore_bytes", "storage", "Total available space in the file store in bytes.", filestoreLabelNames),
-		"filestoreUsed":  newMetric("filestore_used_bytes", "storage", "Used space in the file store in bytes.", filestoreLabelNames),
-		"filestoreFree":  newMetric("filestore_free_bytes", "storage", "Free space in the file store in bytes.", filestoreLabelNames),
-		"items":          newMetric("items", "storage", "Total items count stored in Artifactory.", defaultLabelNames),
-		"repoUsed":       newMetric("repo_used_bytes", "storage", "Used space by an Artifactory repository in bytes.", repoLabelNames),
-		"repoFolders":    newMetric("repo_folders", "storage", "Number of folders in an Artifactory repository.", repoLabelNames),
-		"repoFiles":      newMetric("repo_files", "storage", "Number of files in an Artifactory repository.", repoLabelNames),
-		"repoItems":      newMetric("repo_items", "storage", "Number of items in an Artifactory repository.", repoLabelNames),
-		"repoPercentage": newMetric("repo_percentage", "storage", "Percentage of space used by an Artifactory repository.", repoLabelNames),
+	d.storage = metrics{
+		"artifacts":       newMetric(namespace, "artifacts", "storage", "Total artifacts count stored in Artifactory.", defaultLabelNames),
+		"artifactsSize":   newMetric(namespace, "artifacts_size_bytes", "storage", "Total artifacts Size stored in Artifactory in bytes.", defaultLabelNames),
+		"binaries":        newMetric(namespace, "binaries", "storage", "Total binaries count stored in Artifactory.", defaultLabelNames),
+		"binariesSize":    newMetric(namespace, "binaries_size_bytes", "storage", "Total binaries Size stored in Artifactory in bytes.", defaultLabelNames),
+		"filestore":       newMetric(namespace, "filestore_bytes", "storage", "Total available space in the file store in bytes.", filestoreLabelNames),
+		"filestoreUsed":   newMetric(namespace, "filestore_used_bytes", "storage", "Used space in the file store in bytes.", filestoreLabelNames),
+		"filestoreFree":   newMetric(namespace, "filestore_free_bytes", "storage", "Free space in the file store in bytes.", filestoreLabelNames),
+		"items":           newMetric(namespace, "items", "storage", "Total items count stored in Artifactory.", defaultLabelNames),
+		"repoUsed":        newMetric(namespace, "repo_used_bytes", "storage", "Used space by an Artifactory repository in bytes.", repoLabelNames),
+		"repoFolders":     newMetric(namespace, "repo_folders", "storage", "Number of folders in an Artifactory repository.", repoLabelNames),
+		"repoFiles":       newMetric(namespace, "repo_files", "storage", "Number of files in an Artifactory repository.", repoLabelNames),
+		"repoItems":       newMetric(namespace, "repo_items", "storage", "Number of items in an Artifactory repository.", repoLabelNames),
+		"repoPercentage":  newMetric(namespace, "repo_percentage", "storage", "Percentage of space used by an Artifactory repository.", repoLabelNames),
+		"repoAvgSize":     newMetric(namespace, "avg_artifact_bytes", "repo", "Average size of the files in an Artifactory repository in bytes.", repoLabelNames),
+		"remoteCacheUsed": newMetric(namespace, "cache_used_bytes", "remote_repo", "Used space by the cache of a remote Artifactory repository in bytes.", append([]string{"name", "package_type"}, defaultLabelNames...)),
+		"quotaLimit":      newMetric(namespace, "quota_limit_bytes", "storage", "Configured storage quota of the file store in bytes.", defaultLabelNames),
+		"quotaUsedRatio":  newMetric(namespace, "quota_used_ratio", "storage", "Ratio of the configured storage quota used by the file store.", defaultLabelNames),
+		"quotaWarnPct":    newMetric(namespace, "quota_warning_percent", "storage", "Configured storage quota warning threshold in percent of the file store.", defaultLabelNames),
+		"quotaLimitPct":   newMetric(namespace, "quota_limit_percent", "storage", "Configured storage quota limit threshold in percent of the file store.", defaultLabelNames),
+		"infoAge":         newMetric(namespace, "info_age_seconds", "storage", "Time since the storage summary was calculated in seconds.", defaultLabelNames),
+		"packageTypeUsed": newMetric(namespace, "packagetype_used_bytes", "storage", "Used space by all Artifactory repositories of a package type in bytes.", append([]string{"package_type"}, defaultLabelNames...)),
 	}
 
-	systemMetrics = metrics{
-		"healthy":  newMetric("healthy", "system", "Is Artifactory working properly (1 = healthy).", defaultLabelNames),
-		"version":  newMetric("version", "system", "Version and revision of Artifactory as labels.", append([]string{"version", "revision"}, defaultLabelNames...)),
-		"license":  newMetric("license", "system", "License type and expiry as labels, seconds to expiration as value", append([]string{"type", "licensed_to", "expires"}, defaultLabelNames...)),
-		"licenses": newMetric("licenses", "system", "License type and expiry as labels, seconds to expiration as value", append([]string{"type", "valid_through", "licensed_to", "node_url", "license_hash", "expires"}, defaultLabelNames...)),
+	d.system = metrics{
+		"healthy":  newMetric(namespace, "healthy", "system", "Is Artifactory working properly (1 = healthy).", defaultLabelNames),
+		"version":  newMetric(namespace, "version", "system", "Version and revision of Artifactory as labels.", append([]string{"version", "revision"}, defaultLabelNames...)),
+		"uptime":   newMetric(namespace, "uptime_seconds", "", "Time since Artifactory was started in seconds.", defaultLabelNames),
+		"license":  newMetric(namespace, "license", "system", "License type and expiry as labels, seconds to expiration as value", append([]string{"type", "licensed_to", "expires"}, defaultLabelNames...)),
+		"licenses": newMetric(namespace, "licenses", "system", "License type and expiry as labels, seconds to expiration as value", append([]string{"type", "valid_through", "licensed_to", "node_url", "license_hash", "expires"}, defaultLabelNames...)),
 	}
 
-	artifactsMetrics = metrics{}
+	d.delta = metrics{
+		"usedDelta":      newMetric(namespace, "used_bytes_delta", "storage", "Change of the used space in the file store since the previous scrape in bytes.", defaultLabelNames),
+		"repoFilesDelta": newMetric(namespace, "file_count_delta", "repo", "Change of the number of files in a repository since the previous scrape.", append([]string{"repo"}, defaultLabelNames...)),
+	}
 
-	federationMetrics = metrics{
-		"mirrorLag":         newMetric("mirror_lag", "federation", "Federation mirror lag in milliseconds.", federationLabelNames),
-		"unavailableMirror": newMetric("unavailable_mirror", "federation", "Unsynchronized federated mirror status", append([]string{"status"}, federationLabelNames...)),
+	d.virtual = metrics{
+		"members": newMetric(namespace, "members_total", "virtual_repo", "Number of repositories a virtual repository includes, resolving nested virtual repositories.", append([]string{"name"}, defaultLabelNames...)),
 	}
 
-	openMetrics = metrics{
-		"openMetrics": newMetric("open_metrics", "openmetrics", "OpenMetrics proxied from JFrog Platform", defaultLabelNames),
+	d.bundle = metrics{
+		"bundles":  newMetric(namespace, "bundles_total", "release", "Number of release bundles in JFrog Distribution.", defaultLabelNames),
+		"versions": newMetric(namespace, "versions_total", "release_bundle", "Number of versions of a release bundle in JFrog Distribution.", append([]string{"bundle_name"}, defaultLabelNames...)),
 	}
 
-	accessMetrics = metrics{
-		"accessFederationValid": newMetric("access_federation_valid", "access", "Is JFrog Access Federation valid (1 = Circle of Trust validated)", defaultLabelNames),
+	d.checksum = metrics{
+		"missing": newMetric(namespace, "missing_checksum_total", "artifacts", "Number of artifacts in a repository without a SHA-256 checksum.", append([]string{"repo"}, defaultLabelNames...)),
+	}
+
+	d.config = metrics{
+		"descriptor": newMetric(namespace, "descriptor_info", "config", "Base URL and server name of the Artifactory configuration descriptor as labels.", append([]string{"base_url", "server_name"}, defaultLabelNames...)),
+	}
+
+	d.artifacts = metrics{}
+
+	d.federation = metrics{
+		"mirrorLag":         newMetric(namespace, "mirror_lag", "federation", "Federation mirror lag in milliseconds.", federationLabelNames),
+		"unavailableMirror": newMetric(namespace, "unavailable_mirror", "federation", "Unsynchronized federated mirror status", append([]string{"status"}, federationLabelNames...)),
+		"rtfsEnabled":       newMetric(namespace, "rtfs_enabled", "federation", "Is RTFS enabled, making the federation status endpoints unavailable (1 = enabled).", nil),
+	}
+
+	d.open = metrics{
+		"openMetrics": newMetric(namespace, "open_metrics", "openmetrics", "OpenMetrics proxied from JFrog Platform", defaultLabelNames),
+	}
+
+	d.access = metrics{
+		"accessFederationValid": newMetric(namespace, "access_federation_valid", "access", "Is JFrog Access Federation valid (1 = Circle of Trust validated)", defaultLabelNames),
+	}
+
+	d.accessFed = metrics{
+		"servers":         newMetric(namespace, "federation_servers_total", "access", "Number of servers in the JFrog Access Federation (Circle of Trust).", defaultLabelNames),
+		"serverReachable": newMetric(namespace, "federation_server_reachable", "access", "Is trust towards the JFrog Access Federation server validated (1 = reachable).", append([]string{"server_id", "url"}, defaultLabelNames...)),
+	}
+
+	d.trashcan = metrics{
+		"usedSpace": newMetric(namespace, "used_bytes", "trashcan", "Used space by deleted items in the Artifactory trash can in bytes.", defaultLabelNames),
+		"items":     newMetric(namespace, "item_count", "trashcan", "Number of deleted items in the Artifactory trash can.", defaultLabelNames),
+	}
+
+	d.docker = metrics{
+		"images": newMetric(namespace, "images_total", "docker", "Number of images in a Docker repository.", append([]string{"repo"}, defaultLabelNames...)),
+		"tags":   newMetric(namespace, "tags_total", "docker", "Number of tags of all images in a Docker repository.", append([]string{"repo"}, defaultLabelNames...)),
+	}
+
+	d.recent = metrics{
+		"createdRecent": newMetric(namespace, "created_recent_total", "artifacts", "Number of artifacts created in all repositories within the time window.", append([]string{"window"}, defaultLabelNames...)),
+	}
+
+	d.cleanup = metrics{
+		"eligible": newMetric(namespace, "eligible_for_cleanup_total", "artifacts", "Number of artifacts in a repository older than the cleanup age.", append([]string{"repo"}, defaultLabelNames...)),
+	}
+
+	d.conversion = metrics{
+		"inProgress":   newMetric(namespace, "in_progress", "conversion", "Is a data conversion or migration running, e.g. after an upgrade (1 = running).", nil),
+		"pendingTasks": newMetric(namespace, "pending_tasks", "conversion", "Number of scheduled or running data conversion and migration tasks.", nil),
+	}
+
+	d.exporter = metrics{
+		"circuitState":        newMetric(namespace, "circuit_state", "exporter", "Circuit breaker state of an Artifactory API endpoint (0 = closed, 1 = open, 2 = half-open).", []string{"endpoint"}),
+		"subsystemLastScrape": newMetric(namespace, "subsystem_last_scrape_seconds", "exporter", "Seconds since the metrics of a sampled subsystem were last scraped from Artifactory.", []string{"subsystem"}),
+		"scrapeSuccess":       newMetric(namespace, "scrape_success", "exporter", "Whether all enabled subsystems were scraped successfully (1 = success).", nil),
+		"subsystemSuccess":    newMetric(namespace, "subsystem_scrape_success", "exporter", "Whether a subsystem was scraped successfully (1 = success).", []string{"subsystem"}),
+		"upFailureReason":     newMetric(namespace, "up_failure_reason", "", "Reason the last scrape of Artifactory failed, only set if up is 0 (auth, network, timeout or http_error).", []string{"reason"}),
+		"goroutines":          newMetric(namespace, "goroutines", "exporter", "Number of goroutines of the exporter at the last scrape.", nil),
+		"requestsQueued":      newMetric(namespace, "requests_queued", "exporter", "Number of requests to Artifactory waiting for a free slot of the concurrent request limit.", nil),
+		"goroutinesGrowth":    newMetric(namespace, "goroutines_growth", "exporter", "Average change of the number of goroutines of the exporter per scrape over the last 5 scrapes. Keeps being positive if goroutines leak.", nil),
+	}
+
+	d.ha = metrics{
+		"nodes":  newMetric(namespace, "nodes_total", "ha", "Number of nodes in the Artifactory HA cluster.", defaultLabelNames),
+		"nodeUp": newMetric(namespace, "node_up", "ha", "Is the Artifactory HA node healthy (1 = healthy).", append([]string{"ha_node_id", "state"}, defaultLabelNames...)),
+	}
+
+	d.ssl = metrics{
+		"certExpiry": newMetric(namespace, "cert_expiry_seconds", "ssl", "Expiry time of the TLS certificate presented by Artifactory as Unix timestamp. Only exported for HTTPS scrape URIs.", nil),
+	}
+
+	d.backup = metrics{
+		"configured":   newMetric(namespace, "configured", "backup", "Number of backups configured in Artifactory.", defaultLabelNames),
+		"enabled":      newMetric(namespace, "enabled", "backup", "Is the Artifactory backup enabled (1 = enabled).", append([]string{"key", "cron_exp"}, defaultLabelNames...)),
+		"enabledTotal": newMetric(namespace, "enabled_total", "backups", "Number of enabled backups in Artifactory.", defaultLabelNames),
 	}
-)
 
-func InitMetrics(e *Exporter) {
+	d.drift = metrics{
+		"configDrift": newMetric(namespace, "config_drift", "repo", "Does the configuration of the repository differ from the expected one (1 = drifted).", append([]string{"name"}, defaultLabelNames...)),
+	}
+
+	d.token = metrics{
+		"total":    newMetric(namespace, "tokens_total", "access", "Number of active access tokens.", defaultLabelNames),
+		"expiring": newMetric(namespace, "tokens_expiring_soon", "access", "Number of active access tokens expiring within the time window. Tokens without expiry aren't counted.", append([]string{"within"}, defaultLabelNames...)),
+	}
+
+	d.jvm = metrics{
+		"gcSeconds": newMetric(namespace, "gc_collection_seconds_total", "jvm", "Time spent in a JVM garbage collector in seconds.", append([]string{"collector"}, defaultLabelNames...)),
+		"gcCount":   newMetric(namespace, "gc_collection_count", "jvm", "Number of collections of a JVM garbage collector.", append([]string{"collector"}, defaultLabelNames...)),
+	}
+
+	d.remoteRepo = metrics{
+		"offline": newMetric(namespace, "offline", "remote_repo", "Is the remote repository marked offline (1 = offline).", append([]string{"name", "package_type"}, defaultLabelNames...)),
+	}
+
+	d.repo = metrics{
+		"packageTypes":   newMetric(namespace, "distinct_package_types_total", "", "Number of distinct package types of all Artifactory repositories.", defaultLabelNames),
+		"federatedRepos": newMetric(namespace, "federated_repositories_total", "", "Number of federated Artifactory repositories.", defaultLabelNames),
+	}
+
+	d.layout = metrics{
+		"byLayout": newMetric(namespace, "by_layout", "repositories", "Number of repositories using a repository layout.", append([]string{"layout"}, defaultLabelNames...)),
+	}
+
+	d.gc = metrics{
+		"lastRun":  newMetric(namespace, "last_run_timestamp_seconds", "gc", "Unix timestamp of the end of the last successful garbage collection run of the type.", append([]string{"type"}, defaultLabelNames...)),
+		"duration": newMetric(namespace, "duration_seconds", "gc", "Duration of the last successful garbage collection run of the type in seconds.", append([]string{"type"}, defaultLabelNames...)),
+	}
+
+	d.service = metrics{
+		"up": newMetric(namespace, "up", "service", "Is the JFrog Platform service healthy according to the router (1 = healthy).", append([]string{"service_id", "ha_node_id", "state"}, defaultLabelNames...)),
+	}
+
+	return d
+}
+
+// initMetrics creates the metric descriptors of the exporter, including the
+// ones depending on its config.
+func (e *Exporter) initMetrics() {
+	e.metrics = newMetricDescriptors(e.namespace)
 	for _, timeInterval := range e.exporterRuntimeConfig.ArtifactsTimeIntervals {
 		createdMetricName := fmt.Sprintf("created_%s", timeInterval.ShortPeriod)
 		downloadedMetricName := fmt.Sprintf("downloaded_%s", timeInterval.ShortPeriod)
 
-		artifactsMetrics[createdMetricName] = newMetric(createdMetricName, "artifacts", fmt.Sprintf("Number of artifacts created in the repository in the last %d %s.", timeInterval.Duration, timeInterval.Unit), repoLabelNames)
+		e.metrics.artifacts[createdMetricName] = newMetric(e.namespace, createdMetricName, "artifacts", fmt.Sprintf("Number of artifacts created in the repository in the last %d %s.", timeInterval.Duration, timeInterval.Unit), repoLabelNames)
 		e.logger.Debug("Init metric", "metricName", createdMetricName)
-		artifactsMetrics[downloadedMetricName] = newMetric(downloadedMetricName, "artifacts", fmt.Sprintf("Number of artifacts downloaded from the repository in the last %d %s.", timeInterval.Duration, timeInterval.Unit), repoLabelNames)
+		e.metrics.artifacts[downloadedMetricName] = newMetric(e.namespace, downloadedMetricName, "artifacts", fmt.Sprintf("Number of artifacts downloaded from the repository in the last %d %s.", timeInterval.Duration, timeInterval.Unit), repoLabelNames)
 		e.logger.Debug("Init metric", "metricName", downloadedMetricName)
 	}
+	e.initCustomMetrics()
+	e.initPackageMetrics()
 }
 
 // Describe sends the descriptors of all metrics exported by the Artifactory exporter.
 // Note: Metrics manually collected via Collect (like background task metrics)
 // do not appear here, as they are registered and exported independently.
 func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
-	for _, m := range replicationMetrics {
+	for _, m := range e.metrics.replication {
+		ch <- m
+	}
+	for _, m := range e.metrics.security {
+		ch <- m
+	}
+	for _, m := range e.metrics.storage {
+		ch <- m
+	}
+	for _, m := range e.metrics.system {
+		ch <- m
+	}
+	for _, m := range e.metrics.ha {
 		ch <- m
 	}
-	for _, m := range securityMetrics {
+	for _, m := range e.metrics.service {
 		ch <- m
 	}
-	for _, m := range storageMetrics {
+	for _, m := range e.metrics.ssl {
 		ch <- m
 	}
-	for _, m := range systemMetrics {
+	for _, m := range e.metrics.trashcan {
 		ch <- m
 	}
+	for _, m := range e.metrics.repo {
+		ch <- m
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.ConfigDescriptor {
+		for _, m := range e.metrics.config {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.StorageUsedDelta || e.exporterRuntimeConfig.OptionalMetrics.RepoFileCountDelta {
+		for _, m := range e.metrics.delta {
+			ch <- m
+		}
+	}
 	if e.exporterRuntimeConfig.OptionalMetrics.Artifacts {
-		for _, m := range artifactsMetrics {
+		for _, m := range e.metrics.artifacts {
 			ch <- m
 		}
 	}
-	if e.exporterRuntimeConfig.OptionalMetrics.FederationStatus {
-		for _, m := range federationMetrics {
+	if e.exporterRuntimeConfig.OptionalMetrics.Federation() {
+		for _, m := range e.metrics.federation {
+			ch <- m
+		}
+	}
+	for _, m := range e.metrics.conversion {
+		ch <- m
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.ArtifactsRecent {
+		for _, m := range e.metrics.recent {
+			ch <- m
+		}
+	}
+	for _, m := range e.customMetrics {
+		ch <- m
+	}
+	for _, m := range e.packageMetrics {
+		ch <- m
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.CleanupEligible {
+		for _, m := range e.metrics.cleanup {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.MissingChecksums {
+		for _, m := range e.metrics.checksum {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.Docker {
+		for _, m := range e.metrics.docker {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.Backups {
+		for _, m := range e.metrics.backup {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.AccessTokens {
+		for _, m := range e.metrics.token {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.SystemInfo {
+		for _, m := range e.metrics.jvm {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.RemoteRepos {
+		for _, m := range e.metrics.remoteRepo {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.VirtualRepos {
+		for _, m := range e.metrics.virtual {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.ReleaseBundles {
+		for _, m := range e.metrics.bundle {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.GarbageCollection {
+		for _, m := range e.metrics.gc {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.RepoLayouts {
+		for _, m := range e.metrics.layout {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.AccessFederationServers {
+		for _, m := range e.metrics.accessFed {
+			ch <- m
+		}
+	}
+	if len(e.exporterRuntimeConfig.RepoDrift.Repos()) > 0 {
+		for _, m := range e.metrics.drift {
 			ch <- m
 		}
 	}
 	if e.exporterRuntimeConfig.OptionalMetrics.OpenMetrics {
-		for _, m := range openMetrics {
+		for _, m := range e.metrics.open {
 			ch <- m
 		}
 	}
 	if e.exporterRuntimeConfig.OptionalMetrics.AccessFederationValidate {
-		for _, m := range accessMetrics {
+		for _, m := range e.metrics.access {
 			ch <- m
 		}
 	}
 	ch <- e.up.Desc()
+	ch <- e.scrapesInFlight.Desc()
 	ch <- e.totalScrapes.Desc()
 	ch <- e.totalAPIErrors.Desc()
 	ch <- e.jsonParseFailures.Desc()
+	ch <- e.scrapeDuration.Desc()
+	for _, m := range e.metrics.exporter {
+		ch <- m
+	}
+	for _, h := range e.httpTrace {
+		ch <- h.Desc()
+	}
+	e.requestDuration.Describe(ch)
+	e.unmarshalErrors.Describe(ch)
 }
 
 // Collect is called on each Prometheus scrape. It runs metric collection and publishes results.
 func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.logger.Debug(">> Collect() fired")
 
+	e.scrapesInFlight.Inc()
+	defer e.scrapesInFlight.Dec()
+	ch <- e.scrapesInFlight
+
+	if e.singleFlight {
+		e.collectShared(ch)
+		return
+	}
+	e.collect(ch, "")
+}
+
+// repoCollector collects the metrics of an Exporter with the per repository
+// metrics restricted to a single repository.
+type repoCollector struct {
+	exporter *Exporter
+	repo     string
+}
+
+// ForRepo returns a collector which runs a collection of e with the per
+// repository metrics restricted to repo. It's meant for ad-hoc debugging, so
+// it never shares a collection with other scrapes.
+func (e *Exporter) ForRepo(repo string) prometheus.Collector {
+	return repoCollector{exporter: e, repo: repo}
+}
+
+func (c repoCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.exporter.Describe(ch)
+}
+
+func (c repoCollector) Collect(ch chan<- prometheus.Metric) {
+	e := c.exporter
+	e.logger.Debug(">> Collect() fired", "repo", c.repo)
+
+	e.scrapesInFlight.Inc()
+	defer e.scrapesInFlight.Dec()
+	ch <- e.scrapesInFlight
+
+	e.collect(ch, c.repo)
+}
+
+// scrapeFlight holds the metrics of a collection shared by concurrent scrapes.
+type scrapeFlight struct {
+	done    chan struct{}
+	metrics []prometheus.Metric
+}
+
+// collectShared joins the collection already in progress, if any, instead of
+// starting a new one, and publishes its results.
+func (e *Exporter) collectShared(ch chan<- prometheus.Metric) {
+	e.flightMutex.Lock()
+	flight := e.flight
+	leader := flight == nil
+	if leader {
+		flight = &scrapeFlight{done: make(chan struct{})}
+		e.flight = flight
+	}
+	e.flightMutex.Unlock()
+
+	if leader {
+		buf := make(chan prometheus.Metric)
+		go func() {
+			e.collect(buf, "")
+			close(buf)
+		}()
+		for m := range buf {
+			flight.metrics = append(flight.metrics, m)
+		}
+		e.flightMutex.Lock()
+		e.flight = nil
+		e.flightMutex.Unlock()
+		close(flight.done)
+	} else {
+		e.logger.Debug("Joining scrape in progress")
+		<-flight.done
+	}
+
+	for _, m := range flight.metrics {
+		ch <- m
+	}
+}
+
+// collect runs a single collection from Artifactory. If repo isn't empty, the
+// per repository metrics are restricted to it.
+func (e *Exporter) collect(ch chan<- prometheus.Metric, repo string) {
 	// Prevent concurrent scrapes from clashing with metric updates
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
+	e.onlyRepo = repo
+	defer func() { e.onlyRepo = "" }()
 
 	// Execute data collection
+	start := time.Now()
 	up := e.scrape(ch)
+	e.scrapeDuration.Observe(time.Since(start).Seconds())
 
 	// Export status and scrape counters
 	ch <- e.up
@@ -150,24 +530,50 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	ch <- e.totalScrapes
 	ch <- e.totalAPIErrors
 	ch <- e.jsonParseFailures
+	ch <- e.scrapeDuration
+	e.exportCircuitStates(ch)
+	ch <- prometheus.MustNewConstMetric(e.metrics.exporter["requestsQueued"], prometheus.GaugeValue, float64(e.client.RequestsQueued()))
+	e.exportSubsystemSamples(ch)
+	e.exportScrapeSuccess(ch)
+	e.exportUpFailureReason(ch)
+	e.exportGoroutines(ch)
+	for _, h := range e.httpTrace {
+		ch <- h
+	}
+	e.requestDuration.Collect(ch)
+	e.unmarshalErrors.Collect(ch)
 
 	// Manually collect background task metrics from the GaugeVec
 	e.backgroundTaskMetrics.Collect(ch)
+	e.backgroundTaskRunningSeconds.Collect(ch)
+	e.backgroundTaskOldestRunningSeconds.Collect(ch)
 }
 
 // scrape executes metric collection logic, split into helper functions to reduce complexity.
 func (e *Exporter) scrape(ch chan<- prometheus.Metric) float64 {
 	e.totalScrapes.Inc()
+	e.scrapeResults = make(map[string]bool)
+	e.scrapeError = nil
+	// The open metrics are only shared by the subsystems of a collection.
+	defer func() { e.openMetrics = nil }()
 
 	// Reset the metric to avoid duplicate data
 	e.backgroundTaskMetrics.Reset()
+	e.backgroundTaskRunningSeconds.Reset()
+	e.backgroundTaskOldestRunningSeconds.Reset()
 
 	if !e.runExportSteps(ch) {
 		return 0
 	}
 
-	if e.exporterRuntimeConfig.OptionalMetrics.BackgroundTasks {
-		e.collectBackgroundTasks()
+	if e.onlyRepo == "" {
+		// The conversion metrics are derived from the background tasks and
+		// exported even if the background task metrics are disabled.
+		if e.exporterRuntimeConfig.OptionalMetrics.BackgroundTasks {
+			e.track("background_tasks", e.collectBackgroundTasks(ch))
+		} else {
+			e.track("conversion", e.collectConversionTasks(ch))
+		}
 	}
 
 	return 1
@@ -176,73 +582,371 @@ func (e *Exporter) scrape(ch chan<- prometheus.Metric) float64 {
 // runExportSteps performs the main metric collection sequence.
 // Returns false if any required step fails.
 func (e *Exporter) runExportSteps(ch chan<- prometheus.Metric) bool {
+	if e.onlyRepo != "" {
+		return e.runRepoExportSteps(ch)
+	}
 	if e.exporterRuntimeConfig.OptionalMetrics.OpenMetrics {
-		if err := e.exportOpenMetrics(ch); err != nil {
-			return false
+		if err := e.exportOpenMetrics(ch); !e.track("openmetrics", err == nil) {
+			return e.scrapeFailed(err)
 		}
 	}
-	if err := e.exportSystem(ch); err != nil {
-		return false
+	if err := e.exportSystem(ch); !e.track("system", err == nil) {
+		return e.scrapeFailed(err)
 	}
-	if err := e.exportSystemHALicenses(ch); err != nil {
+	// JFrog SaaS instances don't provide the endpoints of self-hosted
+	// instances, which would only fail.
+	if !e.saas {
+		if err := e.exportSystemHALicenses(ch); !e.track("licenses", err == nil) {
+			return e.scrapeFailed(err)
+		}
+		e.track("ha", e.exportHANodes(ch))
+		e.track("services", e.exportServices(ch))
+	}
+	e.exportCertExpiry(ch)
+
+	if !e.track("storage", e.sample("storage", ch, e.exportStorageSubsystem)) {
 		return false
 	}
+	if e.exporterRuntimeConfig.OptionalMetrics.Artifacts {
+		if !e.track("artifacts", e.sample("artifacts", ch, e.exportArtifactsSubsystem)) {
+			return false
+		}
+	}
+	e.track("package_types", e.exportDistinctPackageTypes(ch))
+	e.track("federated_repos", e.exportFederatedRepos(ch))
+
+	if e.exporterRuntimeConfig.OptionalMetrics.Federation() {
+		e.exportFederationSubsystem(ch)
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.Docker {
+		e.track("docker", e.sample("docker", ch, e.exportDockerRepos))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.ArtifactsRecent {
+		e.track("artifacts_recent", e.exportArtifactsCreatedRecent(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.CleanupEligible {
+		e.track("cleanup_eligible", e.exportCleanupEligible(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.MissingChecksums {
+		e.track("missing_checksums", e.exportMissingChecksums(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.Backups {
+		e.track("backups", e.exportBackups(ch))
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.ConfigDescriptor {
+		e.track("config_descriptor", e.exportConfigDescriptor(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.AccessTokens {
+		e.track("access_tokens", e.exportAccessTokens(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.SystemInfo {
+		e.track("system_info", e.exportJVMMetrics(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.RemoteRepos {
+		e.track("remote_repos", e.exportRemoteRepos(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.VirtualRepos {
+		e.track("virtual_repos", e.exportVirtualRepos(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.ReleaseBundles {
+		e.track("release_bundles", e.exportReleaseBundles(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.GarbageCollection {
+		e.track("garbage_collection", e.exportGarbageCollection(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.RepoLayouts {
+		e.track("repo_layouts", e.exportRepoLayouts(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.AccessFederationServers {
+		e.track("access_federation_servers", e.exportAccessFederation(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.NativeMetrics {
+		e.track("native_metrics", e.exportNativeMetrics(ch))
+	}
+
+	if len(e.exporterRuntimeConfig.RepoDrift.Repos()) > 0 {
+		e.track("repo_drift", e.exportRepoConfigDrift(ch))
+	}
+
+	if len(e.packageMetrics) > 0 {
+		e.track("packages", e.exportPackages(ch))
+	}
+
+	if len(e.customAQLQueries) > 0 {
+		e.track("custom_aql", e.exportCustomAQL(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.AccessFederationValidate {
+		err := e.exportAccessFederationValidate(ch)
+		e.track("access_federation_validate", err == nil)
+	}
+
+	return true
+}
+
+// track records whether the scrape of subsystem was successful and returns ok.
+func (e *Exporter) track(subsystem string, ok bool) bool {
+	e.scrapeResults[subsystem] = ok
+	return ok
+}
+
+// scrapeFailed records err as the reason the scrape failed, unless the reason
+// was recorded already, and returns false.
+func (e *Exporter) scrapeFailed(err error) bool {
+	if e.scrapeError == nil {
+		e.scrapeError = err
+	}
+	return false
+}
+
+// exportUpFailureReason exports the reason the last scrape failed, if it did.
+func (e *Exporter) exportUpFailureReason(ch chan<- prometheus.Metric) {
+	if e.scrapeError == nil {
+		return
+	}
+	reason := artifactory.FailureReason(e.scrapeError)
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "upFailureReason",
+		"reason", reason,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.exporter["upFailureReason"], prometheus.GaugeValue, 1, reason)
+}
 
+// exportScrapeSuccess exports whether each subsystem scraped during the last
+// collection was successful, and whether all of them were.
+func (e *Exporter) exportScrapeSuccess(ch chan<- prometheus.Metric) {
+	success := true
+	for subsystem, ok := range e.scrapeResults {
+		success = success && ok
+		ch <- prometheus.MustNewConstMetric(e.metrics.exporter["subsystemSuccess"], prometheus.GaugeValue, convArtiToPromBool(ok), subsystem)
+	}
+	ch <- prometheus.MustNewConstMetric(e.metrics.exporter["scrapeSuccess"], prometheus.GaugeValue, convArtiToPromBool(success))
+}
+
+// exportStorageSubsystem exports the metrics derived from the storage info,
+// including the per repository metrics.
+func (e *Exporter) exportStorageSubsystem(ch chan<- prometheus.Metric) bool {
 	storageInfo, err := e.client.FetchStorageInfo()
 	if err != nil {
 		e.totalAPIErrors.Inc()
-		return false
+		return e.scrapeFailed(err)
 	}
 	e.exportStorage(storageInfo, ch)
+	// The quota isn't per repository, so repository scrapes skip its request.
+	if e.onlyRepo == "" {
+		e.exportStorageQuota(storageInfo, ch)
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.StorageUsedDelta {
+		e.exportStorageUsedDelta(storageInfo, ch)
+	}
 
 	repoSummaryList, err := e.extractRepo(storageInfo)
 	if err != nil {
-		return false
+		return e.scrapeFailed(err)
 	}
-	e.exportRepo(repoSummaryList, ch)
+	e.exportRepo(e.filterRepoSummaries(repoSummaryList), ch)
+	if e.exporterRuntimeConfig.OptionalMetrics.RepoFileCountDelta {
+		e.exportRepoFileCountDelta(repoSummaryList, ch)
+	}
+	e.exportPackageTypes(repoSummaryList, storageInfo.NodeId, ch)
+	e.exportTrashcan(repoSummaryList, ch)
+	e.exportRemoteRepoCaches(repoSummaryList, ch)
+	e.repoSummaries = repoSummaryList
+	return true
+}
 
+// runRepoExportSteps performs the collection of a scrape restricted to a
+// single repository. Only the steps exporting per repository metrics run, so
+// instance-wide endpoints like the users, licenses or HA nodes aren't
+// requested, and the AQL queries are restricted to the repository. The
+// storage info, the replications and the repository lists have no per
+// repository endpoint and are fetched in full, the license to tell whether
+// replications are available.
+func (e *Exporter) runRepoExportSteps(ch chan<- prometheus.Metric) bool {
+	if !e.track("storage", e.exportStorageSubsystem(ch)) {
+		return false
+	}
 	if e.exporterRuntimeConfig.OptionalMetrics.Artifacts {
-		repoSummaryList, err = e.getTotalArtifacts(repoSummaryList)
-		if err != nil {
+		if !e.track("artifacts", e.exportArtifactsSubsystem(ch)) {
 			return false
 		}
-		e.exportArtifacts(repoSummaryList, ch)
+	}
+	// Replications are only available commercially.
+	licenseInfo, err := e.client.FetchLicense()
+	if err != nil {
+		e.totalAPIErrors.Inc()
+		return e.scrapeFailed(err)
+	}
+	if !licenseInfo.IsOSS() {
+		if err := e.exportReplications(ch); !e.track("replication", err == nil) {
+			return e.scrapeFailed(err)
+		}
 	}
 
-	if e.exporterRuntimeConfig.OptionalMetrics.FederationStatus && e.client.IsFederationEnabled() {
-		e.exportFederationMirrorLags(ch)
-		e.exportFederationUnavailableMirrors(ch)
+	if e.exporterRuntimeConfig.OptionalMetrics.Federation() {
+		e.exportFederationSubsystem(ch)
 	}
 
-	if e.exporterRuntimeConfig.OptionalMetrics.AccessFederationValidate {
-		e.exportAccessFederationValidate(ch)
+	if e.exporterRuntimeConfig.OptionalMetrics.Docker {
+		e.track("docker", e.exportDockerRepos(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.CleanupEligible {
+		e.track("cleanup_eligible", e.exportCleanupEligible(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.MissingChecksums {
+		e.track("missing_checksums", e.exportMissingChecksums(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.RemoteRepos {
+		e.track("remote_repos", e.exportRemoteRepos(ch))
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.VirtualRepos {
+		e.track("virtual_repos", e.exportVirtualRepos(ch))
+	}
+
+	if len(e.exporterRuntimeConfig.RepoDrift.Repos()) > 0 {
+		e.track("repo_drift", e.exportRepoConfigDrift(ch))
+	}
+
+	if len(e.packageMetrics) > 0 {
+		e.track("packages", e.exportPackages(ch))
 	}
+	return true
+}
 
+// exportFederationSubsystem exports the federation metrics if the federation
+// endpoints are available, and skips them with a warning otherwise.
+func (e *Exporter) exportFederationSubsystem(ch chan<- prometheus.Metric) {
+	state, err := e.client.FederationState()
+	if state != artifactory.FederationEnabled && state != artifactory.FederationRTFS {
+		e.logger.Warn(
+			"Skipping federation metrics",
+			"state", state,
+			"err", err.Error(),
+		)
+		return
+	}
+	e.track("federation", e.sample("federation", ch, e.exportFederation))
+}
+
+// exportArtifactsSubsystem exports the artifacts created and downloaded in
+// the repositories of the last storage scrape, which may have been sampled
+// at another scrape.
+func (e *Exporter) exportArtifactsSubsystem(ch chan<- prometheus.Metric) bool {
+	artifactsSummaryList, err := e.getTotalArtifacts(slices.Clone(e.repoSummaries))
+	if err != nil {
+		return e.scrapeFailed(err)
+	}
+	e.exportArtifacts(e.filterRepoSummaries(artifactsSummaryList), ch)
 	return true
 }
 
 // collectBackgroundTasks emits a count of background tasks by (type, state) combination.
-func (e *Exporter) collectBackgroundTasks() {
+func (e *Exporter) collectBackgroundTasks(ch chan<- prometheus.Metric) bool {
 	e.logger.Debug("Collecting background tasks metrics")
 
 	tasks, err := e.client.FetchBackgroundTasks()
 	if err != nil {
 		e.logger.Error("Error fetching background tasks", "err", err)
-		return
+		return false
 	}
 
 	// Collect background task metrics from Artifactory API
 	// Use a map to count each (type, state) combo, and avoid duplicate label sets
 	counter := make(map[[2]string]int)
+	// Keep the longest running task of each type only
+	runningSeconds := make(map[string]float64)
+	now := time.Now()
 	for _, task := range tasks {
 		// Extract the class name only (e.g. "BundleCleanupJob") to reduce cardinality
 		segments := strings.Split(task.Type, ".")
 		shortType := segments[len(segments)-1]
 		key := [2]string{shortType, task.State}
 		counter[key]++
+
+		if seconds, ok := task.RunningSeconds(now); ok {
+			if longest, seen := runningSeconds[shortType]; !seen || seconds > longest {
+				runningSeconds[shortType] = seconds
+			}
+		}
 	}
 
 	for key, count := range counter {
 		e.backgroundTaskMetrics.WithLabelValues(key[0], key[1]).Set(float64(count))
 	}
+	oldest := 0.0
+	for taskType, seconds := range runningSeconds {
+		e.backgroundTaskRunningSeconds.WithLabelValues(taskType).Set(seconds)
+		oldest = max(oldest, seconds)
+	}
+	e.backgroundTaskOldestRunningSeconds.WithLabelValues().Set(oldest)
+
+	e.exportConversionTasks(tasks, ch)
+	return true
+}
+
+// collectConversionTasks emits the conversion metrics without the background
+// task metrics. They're left out if the credentials can't read the tasks,
+// which requires admin permissions.
+func (e *Exporter) collectConversionTasks(ch chan<- prometheus.Metric) bool {
+	e.logger.Debug("Collecting conversion tasks metrics")
+
+	tasks, err := e.client.FetchBackgroundTasks()
+	if errors.Is(err, artifactory.ErrAdminRequired) {
+		e.logger.Debug("Reading the background tasks requires admin permissions, skipping the conversion metrics")
+		return true
+	}
+	if err != nil {
+		e.logger.Error("Error fetching background tasks", "err", err)
+		return false
+	}
+	e.exportConversionTasks(tasks, ch)
+	return true
+}
+
+// exportConversionTasks emits whether data conversions are running. Both metrics
+// are zero rather than absent if there are no conversion tasks.
+func (e *Exporter) exportConversionTasks(tasks []artifactory.BackgroundTask, ch chan<- prometheus.Metric) {
+	var pending, running int
+	for _, task := range tasks {
+		if !task.IsConversion() || !task.IsPending() {
+			continue
+		}
+		pending++
+		if task.IsRunning() {
+			running++
+		}
+	}
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "conversionPendingTasks",
+		"value", pending,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.conversion["inProgress"], prometheus.GaugeValue, convArtiToPromBool(running > 0))
+	ch <- prometheus.MustNewConstMetric(e.metrics.conversion["pendingTasks"], prometheus.GaugeValue, float64(pending))
+}
+
+// exportCircuitStates emits the circuit breaker state of every endpoint requested so far.
+func (e *Exporter) exportCircuitStates(ch chan<- prometheus.Metric) {
+	for endpoint, state := range e.client.CircuitStates() {
+		ch <- prometheus.MustNewConstMetric(e.metrics.exporter["circuitState"], prometheus.GaugeValue, float64(state), endpoint)
+	}
 }
@@ -0,0 +1,645 @@
+package collector
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestInitMetricsNamespace(t *testing.T) {
+	e := &Exporter{
+		namespace: "jfrog",
+		logger:    newTestLogger(),
+	}
+	e.initMetrics()
+	// Exporters in the same process, e.g. after a reload, keep their own
+	// descriptors.
+	other := &Exporter{
+		namespace: defaultNamespace,
+		logger:    newTestLogger(),
+	}
+	other.initMetrics()
+
+	groups := []metrics{
+		e.metrics.replication,
+		e.metrics.security,
+		e.metrics.storage,
+		e.metrics.system,
+		e.metrics.federation,
+		e.metrics.open,
+		e.metrics.access,
+		e.metrics.ha,
+		e.metrics.exporter,
+		e.metrics.trashcan,
+		e.metrics.docker,
+		e.metrics.conversion,
+		e.metrics.service,
+		e.metrics.recent,
+		e.metrics.ssl,
+		e.metrics.backup,
+		e.metrics.drift,
+		e.metrics.token,
+		e.metrics.jvm,
+		e.metrics.remoteRepo,
+		e.metrics.gc,
+		e.metrics.layout,
+		e.metrics.accessFed,
+		e.metrics.repo,
+		e.metrics.cleanup,
+		e.metrics.checksum,
+		e.metrics.config,
+		e.metrics.delta,
+		e.packageMetrics,
+		e.customMetrics,
+		e.metrics.virtual,
+		e.metrics.bundle,
+	}
+	for _, group := range groups {
+		for name, desc := range group {
+			if !strings.Contains(desc.String(), `fqName: "jfrog_`) {
+				t.Errorf("Metric %s does not use the configured namespace: %s", name, desc.String())
+			}
+		}
+	}
+}
+
+func TestCollectSingleFlight(t *testing.T) {
+	tests := []struct {
+		name             string
+		singleFlight     bool
+		expectedScrapes  int32
+		expectSameResult bool
+	}{
+		{
+			name:             "Concurrent scrapes share one collection",
+			singleFlight:     true,
+			expectedScrapes:  1,
+			expectSameResult: true,
+		},
+		{
+			name:            "Concurrent scrapes are independent",
+			singleFlight:    false,
+			expectedScrapes: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pings atomic.Int32
+			release := make(chan struct{})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/artifactory/api/system/ping" {
+					pings.Add(1)
+					<-release
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte("OK"))
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+			}))
+			defer server.Close()
+
+			e, err := NewExporter(&config.Config{
+				ArtiScrapeURI:         server.URL + "/artifactory",
+				ArtiTimeout:           5 * time.Second,
+				MetricsNamespace:      defaultNamespace,
+				SingleFlightScrapes:   tt.singleFlight,
+				ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+				Credentials: &config.Credentials{
+					AuthMethod: "userPass",
+					Username:   "test",
+					Password:   "test",
+				},
+				Logger: newTestLogger(),
+			})
+			if err != nil {
+				t.Fatalf("NewExporter() error = %v", err)
+			}
+
+			var wg sync.WaitGroup
+			results := make([]int, 2)
+			scrape := func(i int) {
+				defer wg.Done()
+				ch := make(chan prometheus.Metric)
+				go func() {
+					e.Collect(ch)
+					close(ch)
+				}()
+				for range ch {
+					results[i]++
+				}
+			}
+
+			wg.Add(2)
+			go scrape(0)
+			waitFor(t, func() bool { return pings.Load() == 1 })
+			go scrape(1)
+			waitFor(t, func() bool { return testutil.ToFloat64(e.scrapesInFlight) == 2 })
+			close(release)
+			wg.Wait()
+
+			if pings.Load() != tt.expectedScrapes {
+				t.Errorf("Backend was scraped %d times, want %d", pings.Load(), tt.expectedScrapes)
+			}
+			if got := testutil.ToFloat64(e.totalScrapes); got != float64(tt.expectedScrapes) {
+				t.Errorf("totalScrapes = %v, want %d", got, tt.expectedScrapes)
+			}
+			if tt.expectSameResult && results[0] != results[1] {
+				t.Errorf("Scrapes returned %d and %d metrics, want the same number", results[0], results[1])
+			}
+			if got := testutil.ToFloat64(e.scrapesInFlight); got != 0 {
+				t.Errorf("scrapesInFlight = %v after scrapes completed, want 0", got)
+			}
+		})
+	}
+}
+
+func TestScrapeSuccess(t *testing.T) {
+	tests := []struct {
+		name              string
+		docker            bool
+		saas              bool
+		descriptor        bool
+		denied            []string
+		expectedSuccess   float64
+		expectedSubsystem map[string]float64
+	}{
+		{
+			name:            "All subsystems succeed",
+			expectedSuccess: 1,
+			expectedSubsystem: map[string]float64{
+				"system":          1,
+				"licenses":        1,
+				"ha":              1,
+				"services":        1,
+				"storage":         1,
+				"package_types":   1,
+				"federated_repos": 1,
+				"conversion":      1,
+			},
+		},
+		{
+			name:            "Docker subsystem fails",
+			docker:          true,
+			expectedSuccess: 0,
+			expectedSubsystem: map[string]float64{
+				"system":          1,
+				"licenses":        1,
+				"ha":              1,
+				"services":        1,
+				"storage":         1,
+				"package_types":   1,
+				"federated_repos": 1,
+				"conversion":      1,
+				"docker":          0,
+			},
+		},
+		{
+			name:            "Denied endpoints are skipped",
+			descriptor:      true,
+			denied:          []string{"/router/api/v1/topology/health", "/router/api/v1/system/health", "/artifactory/api/tasks"},
+			expectedSuccess: 1,
+			expectedSubsystem: map[string]float64{
+				"system":            1,
+				"licenses":          1,
+				"ha":                1,
+				"services":          1,
+				"storage":           1,
+				"package_types":     1,
+				"federated_repos":   1,
+				"conversion":        1,
+				"config_descriptor": 1,
+			},
+		},
+		{
+			name:            "SaaS skips self-hosted subsystems",
+			saas:            true,
+			expectedSuccess: 1,
+			expectedSubsystem: map[string]float64{
+				"system":          1,
+				"storage":         1,
+				"package_types":   1,
+				"federated_repos": 1,
+				"conversion":      1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.saas && (r.URL.Path == "/artifactory/api/system/licenses" || r.URL.Path == "/artifactory/api/system/configuration" || strings.HasPrefix(r.URL.Path, "/router/")) {
+					t.Errorf("Unexpected request to %s in SaaS mode", r.URL.Path)
+				}
+				if slices.Contains(tt.denied, r.URL.Path) {
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"errors":[{"status":403,"message":"Forbidden"}]}`))
+					return
+				}
+				switch r.URL.Path {
+				case "/artifactory/api/system/ping":
+					w.Write([]byte("OK"))
+				case "/artifactory/api/system/version":
+					w.Write([]byte(`{"version":"7.77.3","revision":"77703900"}`))
+				case "/artifactory/api/system/license":
+					w.Write([]byte(`{"type":"OSS"}`))
+				case "/artifactory/api/system/licenses":
+					w.Write([]byte(`{"licenses":[]}`))
+				case "/artifactory/api/system/configuration":
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"errors":[{"status":403,"message":"Forbidden"}]}`))
+				case "/artifactory/api/storageinfo":
+					w.Write([]byte(`{"binariesSummary":{"binariesCount":"1"},"repositoriesSummaryList":[]}`))
+				case "/artifactory/api/repositories":
+					w.Write([]byte(`[]`))
+				case "/artifactory/api/tasks":
+					w.Write([]byte(`{"tasks":[]}`))
+				case "/artifactory/api/docker/docker-local/v2/_catalog":
+					w.WriteHeader(http.StatusInternalServerError)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+				}
+			}))
+			defer server.Close()
+
+			e, err := NewExporter(&config.Config{
+				ArtiScrapeURI:    server.URL + "/artifactory",
+				ArtiTimeout:      5 * time.Second,
+				MetricsNamespace: defaultNamespace,
+				DockerRepos:      []string{"docker-local"},
+				SaaS:             tt.saas,
+				ExporterRuntimeConfig: &config.ExporterRuntimeConfig{
+					OptionalMetrics: config.OptionalMetrics{Docker: tt.docker, ConfigDescriptor: tt.descriptor},
+				},
+				Credentials: &config.Credentials{
+					AuthMethod: "userPass",
+					Username:   "test",
+					Password:   "test",
+				},
+				Logger: newTestLogger(),
+			})
+			if err != nil {
+				t.Fatalf("NewExporter() error = %v", err)
+			}
+
+			ch := make(chan prometheus.Metric)
+			go func() {
+				e.Collect(ch)
+				close(ch)
+			}()
+			success := -1.0
+			subsystems := make(map[string]float64)
+			for metric := range ch {
+				var m dto.Metric
+				if err := metric.Write(&m); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+				switch metric.Desc() {
+				case e.metrics.exporter["scrapeSuccess"]:
+					success = m.GetGauge().GetValue()
+				case e.metrics.exporter["subsystemSuccess"]:
+					subsystems[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+				}
+			}
+
+			if success != tt.expectedSuccess {
+				t.Errorf("scrape_success = %v, want %v", success, tt.expectedSuccess)
+			}
+			if len(subsystems) != len(tt.expectedSubsystem) {
+				t.Errorf("subsystem_scrape_success = %v, want %v", subsystems, tt.expectedSubsystem)
+			}
+			for subsystem, expected := range tt.expectedSubsystem {
+				if actual, ok := subsystems[subsystem]; !ok || actual != expected {
+					t.Errorf("subsystem_scrape_success{subsystem=%q} = %v, want %v", subsystem, actual, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestPermissionTargetsFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifactory/api/system/ping":
+			w.Write([]byte("OK"))
+		case "/artifactory/api/system/version":
+			w.Write([]byte(`{"version":"7.77.3","revision":"77703900"}`))
+		case "/artifactory/api/system/license":
+			w.Write([]byte(`{"type":"Enterprise"}`))
+		case "/artifactory/api/security/users":
+			w.Write([]byte(`[{"name":"admin","realm":"internal"}]`))
+		case "/artifactory/api/security/groups":
+			w.Write([]byte(`[]`))
+		case "/artifactory/api/system/security/certificates":
+			w.Write([]byte(`[]`))
+		case "/artifactory/api/replications":
+			w.Write([]byte(`[]`))
+		case "/artifactory/api/storageinfo":
+			w.Write([]byte(`{"binariesSummary":{"binariesCount":"1"},"repositoriesSummaryList":[]}`))
+		case "/artifactory/api/repositories":
+			w.Write([]byte(`[]`))
+		case "/artifactory/api/tasks":
+			w.Write([]byte(`{"tasks":[]}`))
+		case "/artifactory/api/v2/security/permissions":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"errors":[{"status":500,"message":"Internal Server Error"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		SaaS:                  true,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		e.Collect(ch)
+		close(ch)
+	}()
+	up := -1.0
+	subsystems := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		switch metric.Desc() {
+		case e.up.Desc():
+			up = m.GetGauge().GetValue()
+		case e.metrics.exporter["subsystemSuccess"]:
+			subsystems[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+		}
+	}
+	if up != 1 {
+		t.Errorf("up = %v, want 1", up)
+	}
+	if actual, ok := subsystems["permission_targets"]; !ok || actual != 0 {
+		t.Errorf("subsystem_scrape_success{subsystem=\"permission_targets\"} = %v, want 0", actual)
+	}
+	if actual := subsystems["system"]; actual != 1 {
+		t.Errorf("subsystem_scrape_success{subsystem=\"system\"} = %v, want 1", actual)
+	}
+}
+
+func TestCollectForRepo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifactory/api/system/license":
+			w.Write([]byte(`{"type":"Enterprise"}`))
+		case "/artifactory/api/storageinfo":
+			w.Write([]byte(`{"binariesSummary":{"binariesCount":"1"},"repositoriesSummaryList":[]}`))
+		case "/artifactory/api/replications":
+			w.Write([]byte(`[]`))
+		case "/artifactory/api/search/aql":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"repo" : "libs-release"`) {
+				t.Errorf("AQL query %s isn't restricted to the repository", body)
+			}
+			w.Write([]byte(`{"results":[],"range":{"total":0}}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:    server.URL + "/artifactory",
+		ArtiTimeout:      5 * time.Second,
+		MetricsNamespace: defaultNamespace,
+		DockerRepos:      []string{"docker-local"},
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{
+			OptionalMetrics: config.OptionalMetrics{
+				Docker:           true,
+				MissingChecksums: true,
+			},
+		},
+		ChecksumMaxResults: 100,
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		e.ForRepo("libs-release").Collect(ch)
+		close(ch)
+	}()
+	subsystems := make(map[string]float64)
+	for metric := range ch {
+		if metric.Desc() != e.metrics.exporter["subsystemSuccess"] {
+			continue
+		}
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		subsystems[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+	}
+
+	expected := map[string]float64{
+		"storage":           1,
+		"replication":       1,
+		"docker":            1,
+		"missing_checksums": 1,
+	}
+	if len(subsystems) != len(expected) {
+		t.Errorf("subsystem_scrape_success = %v, want %v", subsystems, expected)
+	}
+	for subsystem, value := range expected {
+		if actual, ok := subsystems[subsystem]; !ok || actual != value {
+			t.Errorf("subsystem_scrape_success{subsystem=%q} = %v, want %v", subsystem, actual, value)
+		}
+	}
+}
+
+func TestUpFailureReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		closed   bool
+		expected string
+	}{
+		{
+			name: "Authentication failure",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"errors":[{"status":401,"message":"Bad credentials"}]}`))
+			},
+			expected: "auth",
+		},
+		{
+			name: "Error response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"errors":[{"status":500,"message":"Internal Server Error"}]}`))
+			},
+			expected: "http_error",
+		},
+		{
+			name: "Timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+				w.Write([]byte("OK"))
+			},
+			expected: "timeout",
+		},
+		{
+			name:     "Network failure",
+			handler:  func(w http.ResponseWriter, r *http.Request) {},
+			closed:   true,
+			expected: "network",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+			if tt.closed {
+				server.Close()
+			}
+
+			e, err := NewExporter(&config.Config{
+				ArtiScrapeURI:         server.URL + "/artifactory",
+				ArtiTimeout:           50 * time.Millisecond,
+				MetricsNamespace:      defaultNamespace,
+				ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+				Credentials: &config.Credentials{
+					AuthMethod: "userPass",
+					Username:   "test",
+					Password:   "test",
+				},
+				Logger: newTestLogger(),
+			})
+			if err != nil {
+				t.Fatalf("NewExporter() error = %v", err)
+			}
+
+			ch := make(chan prometheus.Metric)
+			go func() {
+				e.Collect(ch)
+				close(ch)
+			}()
+			var reasons []string
+			for metric := range ch {
+				if metric.Desc() != e.metrics.exporter["upFailureReason"] {
+					continue
+				}
+				var m dto.Metric
+				if err := metric.Write(&m); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+				reasons = append(reasons, m.GetLabel()[0].GetValue())
+			}
+
+			if len(reasons) != 1 || reasons[0] != tt.expected {
+				t.Errorf("up_failure_reason = %v, want [%s]", reasons, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScrapeDurationBuckets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ScrapeDurationBuckets: []float64{1, 5},
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		e.Collect(ch)
+		close(ch)
+	}()
+	var histogram *dto.Histogram
+	for metric := range ch {
+		if metric.Desc() != e.scrapeDuration.Desc() {
+			continue
+		}
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		histogram = m.GetHistogram()
+	}
+
+	if histogram == nil {
+		t.Fatal("scrape_duration_seconds not exported")
+	}
+	if histogram.GetSampleCount() != 1 {
+		t.Errorf("sample count = %d, want 1", histogram.GetSampleCount())
+	}
+	var bounds []float64
+	for _, bucket := range histogram.GetBucket() {
+		bounds = append(bounds, bucket.GetUpperBound())
+	}
+	if len(bounds) != 2 || bounds[0] != 1 || bounds[1] != 5 {
+		t.Errorf("bucket bounds = %v, want [1 5]", bounds)
+	}
+}
+
+// waitFor polls condition until it's true or fails the test after a timeout.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
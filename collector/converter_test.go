@@ -24,7 +24,8 @@ func newTestLogger() *slog.Logger {
 }
 
 var testExporter = &Exporter{
-	logger: newTestLogger(),
+	metrics: newMetricDescriptors(defaultNamespace),
+	logger:  newTestLogger(),
 }
 
 func TestConvMultiplier(t *testing.T) {
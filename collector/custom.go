@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// customAQLResult is the result count of a single custom AQL query.
+type customAQLResult struct {
+	name   string
+	count  float64
+	nodeId string
+	err    error
+}
+
+// initCustomMetrics creates a metric descriptor for every custom AQL query.
+// Like the package metrics, the descriptors belong to e.
+func (e *Exporter) initCustomMetrics() {
+	e.customMetrics = metrics{}
+	for name := range e.customAQLQueries {
+		e.customMetrics[name] = newMetric(e.namespace, name, "custom", fmt.Sprintf("Number of results of the custom AQL query %s.", name), defaultLabelNames)
+		e.logger.Debug("Init metric", "metricName", name)
+	}
+}
+
+// runCustomAQL runs all custom AQL queries, at most customAQLConcurrency at a
+// time, and returns their results sorted by name. Every query returns at most
+// limit results.
+func (e *Exporter) runCustomAQL(limit int) []customAQLResult {
+	names := make([]string, 0, len(e.customAQLQueries))
+	for name := range e.customAQLQueries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	results := make([]customAQLResult, len(names))
+	sem := make(chan struct{}, max(e.customAQLConcurrency, 1))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			query := fmt.Sprintf("%s.limit(%d)", strings.TrimSpace(e.customAQLQueries[name]), limit)
+			result, err := e.client.ExecuteAQL(query)
+			results[i] = customAQLResult{
+				name:   name,
+				count:  float64(len(result.Results)),
+				nodeId: result.NodeId,
+				err:    err,
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// validateCustomAQL runs every custom AQL query once, limited to a single
+// result to keep it cheap. It returns an error if Artifactory rejects a query.
+// Other errors, e.g. if Artifactory isn't reachable yet, are logged only.
+func (e *Exporter) validateCustomAQL() error {
+	for _, result := range e.runCustomAQL(1) {
+		if result.err == nil {
+			continue
+		}
+		var apiErr *artifactory.APIError
+		var unmarshalErr *artifactory.UnmarshalError
+		if errors.As(result.err, &apiErr) || errors.As(result.err, &unmarshalErr) {
+			return fmt.Errorf("custom AQL query %s is invalid: %w", result.name, result.err)
+		}
+		e.logger.Warn(
+			"Couldn't validate custom AQL query",
+			"name", result.name,
+			"err", result.err.Error(),
+		)
+	}
+	return nil
+}
+
+// exportCustomAQL exports the result counts of the custom AQL queries. AQL
+// can't count, so every query returns at most customAQLMaxResults results and
+// its count is capped at that limit. It returns false if any query failed.
+func (e *Exporter) exportCustomAQL(ch chan<- prometheus.Metric) bool {
+	ok := true
+	for _, result := range e.runCustomAQL(e.customAQLMaxResults) {
+		if result.err != nil {
+			e.logger.Error(
+				"Couldn't scrape Artifactory when running custom AQL query",
+				"name", result.name,
+				"err", result.err.Error(),
+			)
+			e.totalAPIErrors.Inc()
+			ok = false
+			continue
+		}
+		if int(result.count) >= e.customAQLMaxResults {
+			e.logger.Warn(
+				"Number of results of the custom AQL query reached its limit",
+				"name", result.name,
+				"limit", e.customAQLMaxResults,
+			)
+		}
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", result.name,
+			"value", result.count,
+		)
+		ch <- prometheus.MustNewConstMetric(e.customMetrics[result.name], prometheus.GaugeValue, result.count, result.nodeId)
+	}
+	return ok
+}
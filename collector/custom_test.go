@@ -0,0 +1,136 @@
+package collector
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestCustomAQL(t *testing.T) {
+	responses := map[string]string{
+		`items.find({"repo": "libs-release"})`: `{"results":[{"name":"a.jar"},{"name":"b.jar"}],"range":{"total":2}}`,
+		`builds.find({"name": "app"})`:         `{"results":[{"build.name":"app"}],"range":{"total":1}}`,
+	}
+
+	tests := []struct {
+		name        string
+		queries     map[string]string
+		maxResults  int
+		expectError bool
+		expected    map[string]float64
+	}{
+		{
+			name: "Valid queries",
+			queries: map[string]string{
+				"release_files": `items.find({"repo": "libs-release"})`,
+				"app_builds":    `builds.find({"name": "app"})`,
+			},
+			maxResults: 10,
+			expected:   map[string]float64{"release_files": 2, "app_builds": 1},
+		},
+		{
+			name:       "Results capped at the limit",
+			queries:    map[string]string{"release_files": `items.find({"repo": "libs-release"})`},
+			maxResults: 1,
+			expected:   map[string]float64{"release_files": 1},
+		},
+		{
+			name:        "Invalid query",
+			queries:     map[string]string{"broken": `items.find(`},
+			maxResults:  10,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var limits []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				query, limit, _ := strings.Cut(string(body), ".limit(")
+				limits = append(limits, strings.TrimSuffix(limit, ")"))
+				response, ok := responses[query]
+				if ok && limit == "1)" {
+					// Like Artifactory, return at most the limit.
+					response = strings.Replace(response, `,{"name":"b.jar"}`, "", 1)
+				}
+				if !ok {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"errors":[{"status":400,"message":"Failed to parse query"}]}`))
+					return
+				}
+				w.Write([]byte(response))
+			}))
+			defer server.Close()
+
+			e, err := NewExporter(&config.Config{
+				ArtiScrapeURI:         server.URL + "/artifactory",
+				ArtiTimeout:           5 * time.Second,
+				MetricsNamespace:      defaultNamespace,
+				CustomAQLQueries:      tt.queries,
+				CustomAQLConcurrency:  1,
+				CustomAQLMaxResults:   tt.maxResults,
+				ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+				Credentials: &config.Credentials{
+					AuthMethod: "userPass",
+					Username:   "test",
+					Password:   "test",
+				},
+				Logger: newTestLogger(),
+			})
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewExporter() error = %v", err)
+			}
+			for _, limit := range limits {
+				if limit != "1" {
+					t.Errorf("Validation used limit %s, want 1", limit)
+				}
+			}
+			limits = nil
+
+			ch := make(chan prometheus.Metric, len(tt.queries))
+			e.exportCustomAQL(ch)
+			close(ch)
+			actual := make(map[string]float64)
+			for metric := range ch {
+				var m dto.Metric
+				if err := metric.Write(&m); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+				for name, desc := range e.customMetrics {
+					if metric.Desc() == desc {
+						actual[name] = m.GetGauge().GetValue()
+					}
+				}
+			}
+			for _, limit := range limits {
+				if limit != strconv.Itoa(tt.maxResults) {
+					t.Errorf("Scrape used limit %s, want %d", limit, tt.maxResults)
+				}
+			}
+			if len(actual) != len(tt.expected) {
+				t.Fatalf("exportCustomAQL() = %v, want %v", actual, tt.expected)
+			}
+			for name, expected := range tt.expected {
+				if actual[name] != expected {
+					t.Errorf("Custom metric %s = %v, want %v", name, actual[name], expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package collector
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// exportConfigDescriptor exports the base URL and server name of the
+// configuration descriptor. The metric is omitted if reading the descriptor
+// requires admin permissions.
+func (e *Exporter) exportConfigDescriptor(ch chan<- prometheus.Metric) bool {
+	descriptor, err := e.client.FetchConfigDescriptor()
+	if errors.Is(err, artifactory.ErrAdminRequired) {
+		e.logger.Debug(
+			"Reading the configuration descriptor requires admin permissions, skipping it",
+			"err", err.Error(),
+		)
+		return true
+	}
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching system/configuration",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "descriptor",
+		"base_url", descriptor.UrlBase,
+		"server_name", descriptor.ServerName,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.config["descriptor"], prometheus.GaugeValue, 1, descriptor.UrlBase, descriptor.ServerName, descriptor.NodeId)
+	return true
+}
@@ -0,0 +1,48 @@
+package collector
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// exportReleaseBundles exports the number of release bundles and the number
+// of versions of each of them. Nothing is exported if JFrog Distribution
+// isn't installed.
+func (e *Exporter) exportReleaseBundles(ch chan<- prometheus.Metric) bool {
+	releaseBundles, err := e.client.FetchReleaseBundles()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching release bundles",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	if !releaseBundles.Available {
+		e.logger.Debug("No release bundles available")
+		return true
+	}
+
+	versions := make(map[string]int)
+	for _, version := range releaseBundles.Versions {
+		versions[version.Name]++
+	}
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "bundles",
+		"value", len(versions),
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.bundle["bundles"], prometheus.GaugeValue, float64(len(versions)), releaseBundles.NodeId)
+	for _, name := range slices.Sorted(maps.Keys(versions)) {
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "versions",
+			"bundle", name,
+			"value", versions[name],
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.bundle["versions"], prometheus.GaugeValue, float64(versions[name]), name, releaseBundles.NodeId)
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportReleaseBundles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/distribution/api/v1/release_bundle" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`[
+			{"name": "webapp", "version": "1.0.0", "state": "SIGNED"},
+			{"name": "webapp", "version": "1.1.0", "state": "OPEN"},
+			{"name": "backend", "version": "2.3.0", "state": "SIGNED"}
+		]`))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 10)
+	if !e.exportReleaseBundles(ch) {
+		t.Fatal("exportReleaseBundles() = false, want true")
+	}
+	close(ch)
+	bundles := -1.0
+	versions := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		switch metric.Desc() {
+		case e.metrics.bundle["bundles"]:
+			bundles = m.GetGauge().GetValue()
+		case e.metrics.bundle["versions"]:
+			versions[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+		}
+	}
+	if bundles != 2 {
+		t.Errorf("Release bundles = %v, want 2", bundles)
+	}
+	expected := map[string]float64{"webapp": 2, "backend": 1}
+	if len(versions) != len(expected) {
+		t.Fatalf("Release bundle versions = %v, want %v", versions, expected)
+	}
+	for name, count := range expected {
+		if versions[name] != count {
+			t.Errorf("Versions of %s = %v, want %v", name, versions[name], count)
+		}
+	}
+}
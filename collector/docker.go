@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// exportDockerRepos exports the image and tag counts of the configured Docker
+// repositories. It returns false if any repository couldn't be fetched.
+func (e *Exporter) exportDockerRepos(ch chan<- prometheus.Metric) bool {
+	var only []string
+	if e.onlyRepo != "" {
+		only = []string{e.onlyRepo}
+	}
+	dockerRepoStats, err := e.client.FetchDockerRepoStats(only...)
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching Docker repository stats",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+	}
+
+	for _, repoStats := range dockerRepoStats {
+		for metricName, metric := range e.metrics.docker {
+			var value float64
+			switch metricName {
+			case "images":
+				value = float64(repoStats.Images)
+			case "tags":
+				value = float64(repoStats.Tags)
+			}
+			e.logger.Debug(
+				logDbgMsgRegMetric,
+				"metric", metricName,
+				"repo", repoStats.RepoKey,
+				"value", value,
+			)
+			ch <- prometheus.MustNewConstMetric(metric, prometheus.GaugeValue, value, repoStats.RepoKey, repoStats.NodeId)
+		}
+	}
+	return err == nil
+}
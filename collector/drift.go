@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+// driftedProperties returns the properties of repo which differ from the
+// expected configuration of drift.
+func driftedProperties(drift config.RepoDrift, repo artifactory.Repository) []string {
+	var drifted []string
+	if expected, ok := drift.Types[repo.Key]; ok && expected != strings.ToLower(repo.Type) {
+		drifted = append(drifted, "type")
+	}
+	if expected, ok := drift.PackageTypes[repo.Key]; ok && expected != strings.ToLower(repo.PackageType) {
+		drifted = append(drifted, "package_type")
+	}
+	return drifted
+}
+
+// exportRepoConfigDrift exports whether the configuration of the repositories
+// with an expected configuration has drifted. Missing repositories have
+// drifted as well.
+func (e *Exporter) exportRepoConfigDrift(ch chan<- prometheus.Metric) bool {
+	repositories, err := e.client.FetchRepositories()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching repositories",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	repos := make(map[string]artifactory.Repository, len(repositories.Repositories))
+	for _, repo := range repositories.Repositories {
+		repos[repo.Key] = repo
+	}
+
+	drift := e.exporterRuntimeConfig.RepoDrift
+	for _, key := range drift.Repos() {
+		var drifted bool
+		repo, ok := repos[key]
+		if !ok {
+			e.logger.Warn(
+				"Repository with an expected configuration doesn't exist",
+				"repo", key,
+			)
+			drifted = true
+		} else if properties := driftedProperties(drift, repo); len(properties) > 0 {
+			e.logger.Warn(
+				"Repository configuration has drifted",
+				"repo", key,
+				"properties", properties,
+			)
+			drifted = true
+		}
+		value := convArtiToPromBool(drifted)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "configDrift",
+			"repo", key,
+			"value", value,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.drift["configDrift"], prometheus.GaugeValue, value, key, repositories.NodeId)
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestDriftedProperties(t *testing.T) {
+	drift := config.RepoDrift{
+		Types:        map[string]string{"libs-release": "local", "npm-remote": "remote"},
+		PackageTypes: map[string]string{"libs-release": "maven", "docker-local": "docker"},
+	}
+
+	tests := []struct {
+		name     string
+		repo     artifactory.Repository
+		expected []string
+	}{
+		{
+			name:     "Matching configuration",
+			repo:     artifactory.Repository{Key: "libs-release", Type: "LOCAL", PackageType: "Maven"},
+			expected: nil,
+		},
+		{
+			name:     "Drifted type",
+			repo:     artifactory.Repository{Key: "npm-remote", Type: "VIRTUAL", PackageType: "Npm"},
+			expected: []string{"type"},
+		},
+		{
+			name:     "Drifted package type",
+			repo:     artifactory.Repository{Key: "docker-local", Type: "LOCAL", PackageType: "Generic"},
+			expected: []string{"package_type"},
+		},
+		{
+			name:     "Drifted type and package type",
+			repo:     artifactory.Repository{Key: "libs-release", Type: "FEDERATED", PackageType: "Gradle"},
+			expected: []string{"type", "package_type"},
+		},
+		{
+			name:     "Repository without expected configuration",
+			repo:     artifactory.Repository{Key: "other", Type: "LOCAL", PackageType: "Generic"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if drifted := driftedProperties(drift, tt.repo); !slices.Equal(drifted, tt.expected) {
+				t.Errorf("driftedProperties() = %v, want %v", drifted, tt.expected)
+			}
+		})
+	}
+}
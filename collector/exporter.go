@@ -3,6 +3,7 @@ package collector
 import (
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -15,51 +16,209 @@ import (
 type Exporter struct {
 	client                *artifactory.Client
 	exporterRuntimeConfig config.ExporterRuntimeConfig
+	namespace             string
+	federationPerNode     bool
+	saas                  bool
 	mutex                 sync.RWMutex
+	// metrics are the metric descriptors within namespace.
+	metrics metricDescriptors
 
-	up                                              prometheus.Gauge
+	singleFlight bool
+	flightMutex  sync.Mutex
+	flight       *scrapeFlight
+
+	scrapeMultipliers map[string]int
+	samples           map[string]*subsystemSample
+	scrapeResults     map[string]bool
+	scrapeError       error
+	// onlyRepo restricts the per repository metrics of the collection in
+	// progress to a single repository, if set.
+	onlyRepo string
+	// openMetrics are the open metrics fetched by the collection in
+	// progress, shared by the subsystems derived from them. Nil until they
+	// are fetched.
+	openMetrics *fetchedOpenMetrics
+
+	customAQLQueries     map[string]string
+	customAQLConcurrency int
+	// customAQLMaxResults bounds every custom AQL query run on a scrape.
+	customAQLMaxResults int
+	// customMetrics are the descriptors of the custom AQL queries.
+	customMetrics metrics
+
+	// nativeMetrics are the names of the open metrics families re-exported
+	// by the native_metrics optional metric.
+	nativeMetrics []string
+
+	// cleanupRepos are the repositories the cleanup_eligible optional metric
+	// counts the artifacts older than the cleanup age in, at most
+	// cleanupMaxResults per repository.
+	cleanupRepos      []string
+	cleanupMaxResults int
+	// checksumMaxResults bounds the AQL query of the missing_checksums
+	// optional metric.
+	checksumMaxResults int
+	// recentMaxResults bounds the AQL query of every time window of the
+	// artifacts_recent optional metric.
+	recentMaxResults int
+
+	httpTrace       httpTraceMetrics
+	requestDuration *prometheus.HistogramVec
+	unmarshalErrors *prometheus.CounterVec
+	scrapeDuration  prometheus.Histogram
+	// packageRepos are the repositories to count the packages of by package
+	// type.
+	packageRepos map[string][]string
+	// packageMetrics are the descriptors of the package counts of the
+	// package types of packageRepos.
+	packageMetrics metrics
+
+	// storageUsed is the used space of the file store at the previous
+	// scrape, nil before the first scrape.
+	storageUsed *float64
+	// repoSummaries are the repositories of the last storage scrape, which
+	// the artifacts subsystem counts the artifacts of.
+	repoSummaries []repoSummary
+	// repoFileCounts are the file counts of the repositories passing the
+	// repository filter at the previous scrape, nil before the first scrape.
+	repoFileCounts map[string]float64
+	// goroutineSamples are the goroutine counts of the last scrapes, oldest first.
+	goroutineSamples []int
+
+	up, scrapesInFlight                             prometheus.Gauge
 	totalScrapes, totalAPIErrors, jsonParseFailures prometheus.Counter
 	logger                                          *slog.Logger
 	backgroundTaskMetrics                           *prometheus.GaugeVec
+	backgroundTaskRunningSeconds                    *prometheus.GaugeVec
+	backgroundTaskOldestRunningSeconds              *prometheus.GaugeVec
 }
 
 // NewExporter returns an initialized Exporter.
 func NewExporter(conf *config.Config) (*Exporter, error) {
 	client := artifactory.NewClient(conf)
+	var httpTrace httpTraceMetrics
+	if conf.HTTPTrace {
+		httpTrace = newHTTPTraceMetrics(conf.MetricsNamespace)
+		client.SetTraceHook(httpTrace.observe)
+	}
+	requestDuration := newRequestDuration(conf.MetricsNamespace)
+	client.SetRequestHook(func(endpoint string, duration time.Duration) {
+		requestDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+	})
+	unmarshalErrors := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: conf.MetricsNamespace,
+		Name:      "exporter_unmarshal_errors_total",
+		Help:      "Number of responses of an Artifactory endpoint which couldn't be parsed as JSON.",
+	}, []string{"endpoint"})
+	client.SetUnmarshalErrorHook(func(endpoint string) {
+		unmarshalErrors.WithLabelValues(endpoint).Inc()
+	})
+	// Diagnose insufficient permissions without blocking startup.
+	go client.CheckOptionalMetricsAccess()
 
 	backgroundTaskMetrics := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Namespace: namespace,
+			Namespace: conf.MetricsNamespace,
 			Name:      "artifactory_background_tasks",
 			Help:      "Number of Artifactory background tasks by type and state",
 		},
 		[]string{"type", "state"},
 	)
+	backgroundTaskRunningSeconds := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: conf.MetricsNamespace,
+			Name:      "background_task_running_seconds",
+			Help:      "Time the longest running Artifactory background task of a type has been running in seconds",
+		},
+		[]string{"type"},
+	)
+	backgroundTaskOldestRunningSeconds := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: conf.MetricsNamespace,
+			Name:      "background_task_oldest_running_seconds",
+			Help:      "Time the longest running Artifactory background task of any type has been running in seconds, 0 if no task is running",
+		},
+		nil,
+	)
 
-	return &Exporter{
+	e := &Exporter{
 		client:                client,
 		exporterRuntimeConfig: *conf.ExporterRuntimeConfig,
+		namespace:             conf.MetricsNamespace,
+		federationPerNode:     conf.FederationPerNode,
+		saas:                  conf.SaaS,
+		singleFlight:          conf.SingleFlightScrapes,
+		scrapeMultipliers:     conf.ScrapeMultipliers,
+		samples:               make(map[string]*subsystemSample),
+		customAQLQueries:      conf.CustomAQLQueries,
+		customAQLConcurrency:  conf.CustomAQLConcurrency,
+		customAQLMaxResults:   conf.CustomAQLMaxResults,
+		nativeMetrics:         conf.NativeMetrics,
+		cleanupRepos:          conf.CleanupRepos,
+		cleanupMaxResults:     conf.CleanupMaxResults,
+		checksumMaxResults:    conf.ChecksumMaxResults,
+		recentMaxResults:      conf.RecentMaxResults,
+		packageRepos:          conf.PackageRepos,
+		httpTrace:             httpTrace,
+		requestDuration:       requestDuration,
+		unmarshalErrors:       unmarshalErrors,
+		scrapeDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: conf.MetricsNamespace,
+			Name:      "exporter_scrape_duration_seconds",
+			Help:      "Duration of the collections from Artifactory in seconds.",
+			Buckets:   conf.ScrapeDurationBuckets,
+		}),
 		up: prometheus.NewGauge(prometheus.GaugeOpts{
-			Namespace: namespace,
+			Namespace: conf.MetricsNamespace,
 			Name:      "up",
 			Help:      "Was the last scrape of artifactory successful.",
 		}),
+		scrapesInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: conf.MetricsNamespace,
+			Name:      "exporter_scrapes_in_flight",
+			Help:      "Number of scrapes currently in progress.",
+		}),
 		totalAPIErrors: prometheus.NewCounter(prometheus.CounterOpts{
-			Namespace: namespace,
+			Namespace: conf.MetricsNamespace,
 			Name:      "exporter_total_api_errors",
 			Help:      "Current total API errors.",
 		}),
 		totalScrapes: prometheus.NewCounter(prometheus.CounterOpts{
-			Namespace: namespace,
+			Namespace: conf.MetricsNamespace,
 			Name:      "exporter_total_scrapes",
 			Help:      "Current total artifactory scrapes.",
 		}),
 		jsonParseFailures: prometheus.NewCounter(prometheus.CounterOpts{
-			Namespace: namespace,
+			Namespace: conf.MetricsNamespace,
 			Name:      "exporter_json_parse_failures",
 			Help:      "Number of errors while parsing Json.",
 		}),
-		logger:                conf.Logger,
-		backgroundTaskMetrics: backgroundTaskMetrics,
-	}, nil
+		logger:                             conf.Logger,
+		backgroundTaskMetrics:              backgroundTaskMetrics,
+		backgroundTaskRunningSeconds:       backgroundTaskRunningSeconds,
+		backgroundTaskOldestRunningSeconds: backgroundTaskOldestRunningSeconds,
+	}
+	e.initMetrics()
+	if err := e.validateCustomAQL(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// CancelRequests aborts all in-flight requests to Artifactory, e.g. when
+// the shutdown grace period has expired.
+func (e *Exporter) CancelRequests() {
+	e.client.CancelRequests()
+}
+
+// Client returns the Artifactory client of the exporter. Requests sent with it
+// share the concurrent request limit and circuit breaker of the scrapes.
+func (e *Exporter) Client() *artifactory.Client {
+	return e.client
+}
+
+// FederationPerNode reports whether the federation status is fetched from
+// every HA node.
+func (e *Exporter) FederationPerNode() bool {
+	return e.federationPerNode
 }
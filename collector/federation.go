@@ -1,18 +1,111 @@
 package collector
 
 import (
+	"sort"
+
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
 )
 
-const FederationRepoType = "FEDERATED"
+// exportFederatedRepos exports the number of federated repositories. Unlike the
+// federation status it's always exported, as it doesn't require RTFS.
+func (e *Exporter) exportFederatedRepos(ch chan<- prometheus.Metric) bool {
+	repositories, err := e.client.FetchFederatedRepositories()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching federated repositories",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	count := float64(len(repositories.Repositories))
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "federatedRepos",
+		"value", count,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.repo["federatedRepos"], prometheus.GaugeValue, count, repositories.NodeId)
+	return true
+}
+
+// exportFederation exports the federation status and whether RTFS was
+// detected while fetching it. It returns false if the status couldn't be
+// fetched.
+func (e *Exporter) exportFederation(ch chan<- prometheus.Metric) bool {
+	e.client.ResetRTFSEnabled()
+	ok := e.exportFederationStatus(ch)
+
+	rtfsEnabled := convArtiToPromBool(e.client.RTFSEnabled())
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "rtfsEnabled",
+		"value", rtfsEnabled,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.federation["rtfsEnabled"], prometheus.GaugeValue, rtfsEnabled)
+	return ok
+}
 
-func (e *Exporter) exportFederationMirrorLags(ch chan<- prometheus.Metric) error {
+// exportFederationStatus exports the federation status of the node answering
+// the scrape URI or, in per-node mode, of every HA node.
+func (e *Exporter) exportFederationStatus(ch chan<- prometheus.Metric) bool {
+	if !e.federationPerNode {
+		return e.exportFederationNode(e.client, "", ch)
+	}
+
+	nodeURLs, err := e.client.FetchHANodeURLs()
+	if err != nil {
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	if len(nodeURLs) == 0 {
+		e.logger.Debug("No HA node URLs found, falling back to the scrape URI")
+		return e.exportFederationNode(e.client, "", ch)
+	}
+
+	nodeIds := make([]string, 0, len(nodeURLs))
+	for nodeId := range nodeURLs {
+		nodeIds = append(nodeIds, nodeId)
+	}
+	sort.Strings(nodeIds)
+	ok := true
+	for _, nodeId := range nodeIds {
+		nodeClient := e.client.NodeClient(nodeURLs[nodeId])
+		if !e.exportFederationNode(nodeClient, nodeId, ch) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// exportFederationNode exports the mirror lags and unavailable mirrors
+// reported by client, as far as they are enabled. It returns false if any of
+// them couldn't be fetched.
+func (e *Exporter) exportFederationNode(client *artifactory.Client, nodeId string, ch chan<- prometheus.Metric) bool {
+	ok := true
+	optionalMetrics := e.exporterRuntimeConfig.OptionalMetrics
+	if optionalMetrics.MirrorLags() && e.exportFederationMirrorLags(client, nodeId, ch) != nil {
+		ok = false
+	}
+	if optionalMetrics.UnavailableMirrors() && e.exportFederationUnavailableMirrors(client, nodeId, ch) != nil {
+		ok = false
+	}
+	return ok
+}
+
+// exportFederationMirrorLags exports the mirror lags reported by client.
+// nodeId is used as label if the response doesn't identify the node.
+func (e *Exporter) exportFederationMirrorLags(client *artifactory.Client, nodeId string, ch chan<- prometheus.Metric) error {
 	// Fetch Federation Mirror Lags
-	federationMirrorLags, err := e.client.FetchMirrorLags()
+	federationMirrorLags, err := client.FetchMirrorLags()
 	if err != nil {
 		e.totalAPIErrors.Inc()
 		return err
 	}
+	if federationMirrorLags.NodeId == "" {
+		federationMirrorLags.NodeId = nodeId
+	}
 
 	if len(federationMirrorLags.MirrorLags) == 0 {
 		e.logger.Debug("No federation mirror lags found")
@@ -28,19 +121,24 @@ func (e *Exporter) exportFederationMirrorLags(ch chan<- prometheus.Metric) error
 			"remote_name", mirrorLag.RemoteRepoKey,
 			"value", mirrorLag.LagInMS,
 		)
-		ch <- prometheus.MustNewConstMetric(federationMetrics["mirrorLag"], prometheus.GaugeValue, float64(mirrorLag.LagInMS), mirrorLag.LocalRepoKey, mirrorLag.RemoteUrl, mirrorLag.RemoteRepoKey, federationMirrorLags.NodeId)
+		ch <- prometheus.MustNewConstMetric(e.metrics.federation["mirrorLag"], prometheus.GaugeValue, float64(mirrorLag.LagInMS), mirrorLag.LocalRepoKey, mirrorLag.RemoteUrl, mirrorLag.RemoteRepoKey, federationMirrorLags.NodeId)
 	}
 
 	return nil
 }
 
-func (e *Exporter) exportFederationUnavailableMirrors(ch chan<- prometheus.Metric) error {
+// exportFederationUnavailableMirrors exports the unavailable mirrors reported by client.
+// nodeId is used as label if the response doesn't identify the node.
+func (e *Exporter) exportFederationUnavailableMirrors(client *artifactory.Client, nodeId string, ch chan<- prometheus.Metric) error {
 	// Fetch Federation Unavailable Mirrors
-	federationUnavailableMirrors, err := e.client.FetchUnavailableMirrors()
+	federationUnavailableMirrors, err := client.FetchUnavailableMirrors()
 	if err != nil {
 		e.totalAPIErrors.Inc()
 		return err
 	}
+	if federationUnavailableMirrors.NodeId == "" {
+		federationUnavailableMirrors.NodeId = nodeId
+	}
 
 	if len(federationUnavailableMirrors.UnavailableMirrors) == 0 {
 		e.logger.Debug("No federation unavailable mirrors found")
@@ -48,6 +146,9 @@ func (e *Exporter) exportFederationUnavailableMirrors(ch chan<- prometheus.Metri
 	}
 
 	for _, unavailableMirror := range federationUnavailableMirrors.UnavailableMirrors {
+		if unavailableMirror.NodeId == "" {
+			unavailableMirror.NodeId = federationUnavailableMirrors.NodeId
+		}
 		e.logger.Debug(
 			"Registering metric",
 			"metric", "federationUnavailableMirror",
@@ -55,8 +156,9 @@ func (e *Exporter) exportFederationUnavailableMirrors(ch chan<- prometheus.Metri
 			"repo", unavailableMirror.LocalRepoKey,
 			"remote_url", unavailableMirror.RemoteUrl,
 			"remote_name", unavailableMirror.RemoteRepoKey,
+			"node_id", unavailableMirror.NodeId,
 		)
-		ch <- prometheus.MustNewConstMetric(federationMetrics["unavailableMirror"], prometheus.GaugeValue, 1, unavailableMirror.Status, unavailableMirror.LocalRepoKey, unavailableMirror.RemoteUrl, unavailableMirror.RemoteRepoKey, federationUnavailableMirrors.NodeId)
+		ch <- prometheus.MustNewConstMetric(e.metrics.federation["unavailableMirror"], prometheus.GaugeValue, 1, unavailableMirror.Status, unavailableMirror.LocalRepoKey, unavailableMirror.RemoteUrl, unavailableMirror.RemoteRepoKey, unavailableMirror.NodeId)
 	}
 
 	return nil
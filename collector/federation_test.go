@@ -0,0 +1,267 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportFederationNode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/federation/status/mirrorsLag"):
+			w.Write([]byte(`[{"localRepoKey":"fed-local","remoteUrl":"http://remote","remoteRepoKey":"fed-remote","lagInMS":1500}]`))
+		case strings.HasSuffix(r.URL.Path, "/federation/status/unavailableMirrors"):
+			w.Write([]byte(`{"unavailableMirrors":[{"localRepoKey":"fed-local","status":"unavailable","remoteUrl":"http://remote","remoteRepoKey":"fed-remote"}],"nodeId":"node-1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name              string
+		optionalMetrics   config.OptionalMetrics
+		expectLags        int
+		expectUnavailable int
+	}{
+		{
+			name:              "Mirror lags and unavailable mirrors",
+			optionalMetrics:   config.OptionalMetrics{FederationMirrorLags: true, FederationUnavailableMirrors: true},
+			expectLags:        1,
+			expectUnavailable: 1,
+		},
+		{
+			name:            "Mirror lags only",
+			optionalMetrics: config.OptionalMetrics{FederationMirrorLags: true},
+			expectLags:      1,
+		},
+		{
+			name:              "Unavailable mirrors only",
+			optionalMetrics:   config.OptionalMetrics{FederationUnavailableMirrors: true},
+			expectUnavailable: 1,
+		},
+		{
+			name:            "No federation metrics",
+			optionalMetrics: config.OptionalMetrics{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewExporter(&config.Config{
+				ArtiScrapeURI:         server.URL + "/artifactory",
+				ArtiTimeout:           5 * time.Second,
+				MetricsNamespace:      defaultNamespace,
+				ExporterRuntimeConfig: &config.ExporterRuntimeConfig{OptionalMetrics: tt.optionalMetrics},
+				Credentials: &config.Credentials{
+					AuthMethod: "userPass",
+					Username:   "test",
+					Password:   "test",
+				},
+				Logger: newTestLogger(),
+			})
+			if err != nil {
+				t.Fatalf("NewExporter() error = %v", err)
+			}
+
+			ch := make(chan prometheus.Metric, 10)
+			if !e.exportFederationNode(e.client, "", ch) {
+				t.Error("exportFederationNode() = false, want true")
+			}
+			close(ch)
+			var lags, unavailable int
+			for metric := range ch {
+				switch metric.Desc() {
+				case e.metrics.federation["mirrorLag"]:
+					lags++
+				case e.metrics.federation["unavailableMirror"]:
+					unavailable++
+				}
+			}
+			if lags != tt.expectLags {
+				t.Errorf("Exported %d mirror lags, want %d", lags, tt.expectLags)
+			}
+			if unavailable != tt.expectUnavailable {
+				t.Errorf("Exported %d unavailable mirrors, want %d", unavailable, tt.expectUnavailable)
+			}
+		})
+	}
+}
+
+func TestExportFederationUnavailableMirrorsNodeId(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "node-2")
+		w.Write([]byte(`{"unavailableMirrors":[{"localRepoKey":"fed-local","status":"unavailable","remoteUrl":"http://remote","remoteRepoKey":"fed-remote"}]}`))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 10)
+	if err := e.exportFederationUnavailableMirrors(e.client, "", ch); err != nil {
+		t.Fatalf("exportFederationUnavailableMirrors() error = %v", err)
+	}
+	close(ch)
+	var nodeIds []string
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "node_id" {
+				nodeIds = append(nodeIds, label.GetValue())
+			}
+		}
+	}
+	if len(nodeIds) != 1 || nodeIds[0] != "node-2" {
+		t.Errorf("node_id labels = %v, want [node-2]", nodeIds)
+	}
+}
+
+func TestFederationGateTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifactory/api/system/ping":
+			w.Write([]byte("OK"))
+		case "/artifactory/api/system/version":
+			w.Write([]byte(`{"version":"7.77.3","revision":"77703900"}`))
+		case "/artifactory/api/system/license":
+			w.Write([]byte(`{"type":"OSS"}`))
+		case "/artifactory/api/storageinfo":
+			w.Write([]byte(`{"binariesSummary":{"binariesCount":"1"},"repositoriesSummaryList":[]}`))
+		case "/artifactory/api/repositories":
+			w.Write([]byte(`[]`))
+		case "/artifactory/api/federation/status/mirrorsLag":
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`[{"localRepoKey":"fed-local","remoteUrl":"http://remote","remoteRepoKey":"fed-remote","lagInMS":1500}]`))
+		case "/artifactory/api/federation/status/unavailableMirrors":
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{"unavailableMirrors":[],"nodeId":"node-1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:     server.URL + "/artifactory",
+		ArtiTimeout:       100 * time.Millisecond,
+		FederationTimeout: 5 * time.Second,
+		MetricsNamespace:  defaultNamespace,
+		SaaS:              true,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{
+			OptionalMetrics: config.OptionalMetrics{FederationMirrorLags: true},
+		},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		e.Collect(ch)
+		close(ch)
+	}()
+	var lags int
+	federationSuccess := -1.0
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		switch metric.Desc() {
+		case e.metrics.federation["mirrorLag"]:
+			lags++
+		case e.metrics.exporter["subsystemSuccess"]:
+			if m.GetLabel()[0].GetValue() == "federation" {
+				federationSuccess = m.GetGauge().GetValue()
+			}
+		}
+	}
+	if federationSuccess != 1 {
+		t.Errorf("subsystem_scrape_success{subsystem=\"federation\"} = %v, want 1", federationSuccess)
+	}
+	if lags != 1 {
+		t.Errorf("Exported %d mirror lags, want 1", lags)
+	}
+}
+
+func TestExportFederatedRepos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/artifactory/api/repositories" || r.URL.Query().Get("type") != "federated" {
+			t.Errorf("Unexpected request to %s", r.URL)
+		}
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		w.Write([]byte(`[
+			{"key": "libs-federated", "type": "FEDERATED", "packageType": "Maven"},
+			{"key": "docker-federated", "type": "FEDERATED", "packageType": "Docker"}
+		]`))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 1)
+	if !e.exportFederatedRepos(ch) {
+		t.Fatal("exportFederatedRepos() = false, want true")
+	}
+	close(ch)
+	metric := <-ch
+	if metric.Desc() != e.metrics.repo["federatedRepos"] {
+		t.Fatalf("Exported %s, want federated_repositories_total", metric.Desc())
+	}
+	var m dto.Metric
+	if err := metric.Write(&m); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if value := m.GetGauge().GetValue(); value != 2 {
+		t.Errorf("federated_repositories_total = %v, want 2", value)
+	}
+	if nodeId := m.GetLabel()[0].GetValue(); nodeId != "test-node" {
+		t.Errorf("node_id = %s, want test-node", nodeId)
+	}
+}
@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// exportGarbageCollection exports the end time and the duration of the last
+// successful garbage collection run of every type, e.g. full or
+// trash_and_binaries. Runs are only reported in the open metrics of some
+// versions, so nothing is exported if they aren't available.
+func (e *Exporter) exportGarbageCollection(ch chan<- prometheus.Metric) bool {
+	openMetrics, err := e.fetchOpenMetrics()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching garbage collection runs",
+			"err", err.Error(),
+		)
+		return false
+	}
+	gcRuns, err := e.client.ParseGarbageCollectionRuns(openMetrics)
+	if err != nil {
+		e.logger.Error(
+			"Couldn't parse the garbage collection runs of the open metrics",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	if !gcRuns.Available {
+		e.logger.Debug("No garbage collection runs available")
+		return true
+	}
+
+	lastRuns := make(map[string]artifactory.GarbageCollectionRun)
+	for _, run := range gcRuns.Runs {
+		if !run.Succeeded() {
+			continue
+		}
+		gcType := strings.ToLower(run.Type)
+		if last, ok := lastRuns[gcType]; !ok || run.End.After(last.End) {
+			lastRuns[gcType] = run
+		}
+	}
+
+	for _, gcType := range slices.Sorted(maps.Keys(lastRuns)) {
+		run := lastRuns[gcType]
+		lastRun := float64(run.End.UnixMilli()) / 1000
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "gcLastRun",
+			"type", gcType,
+			"value", lastRun,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.gc["lastRun"], prometheus.GaugeValue, lastRun, gcType, gcRuns.NodeId)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "gcDuration",
+			"type", gcType,
+			"value", run.DurationSeconds,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.gc["duration"], prometheus.GaugeValue, run.DurationSeconds, gcType, gcRuns.NodeId)
+	}
+	return true
+}
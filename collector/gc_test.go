@@ -0,0 +1,105 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportGarbageCollection(t *testing.T) {
+	fullEnd := time.Now().Add(-2 * time.Hour)
+	trashEnd := time.Now().Add(-30 * time.Minute)
+	fixture := fmt.Sprintf(`# HELP jfrt_artifacts_gc_duration_seconds Time taken by a GC run
+# TYPE jfrt_artifacts_gc_duration_seconds gauge
+jfrt_artifacts_gc_duration_seconds{end_time="%d",gc_type="FULL",start_time="%d",status="COMPLETED"} 60
+jfrt_artifacts_gc_duration_seconds{end_time="%d",gc_type="FULL",start_time="%d",status="COMPLETED"} 90
+jfrt_artifacts_gc_duration_seconds{end_time="%d",gc_type="TRASH_AND_BINARIES",start_time="%d",status="COMPLETED"} 5
+jfrt_artifacts_gc_duration_seconds{end_time="%d",gc_type="TRASH_AND_BINARIES",start_time="%d",status="FAILED"} 1
+# EOF
+`,
+		fullEnd.Add(-24*time.Hour).UnixMilli(), fullEnd.Add(-24*time.Hour-time.Minute).UnixMilli(),
+		fullEnd.UnixMilli(), fullEnd.Add(-90*time.Second).UnixMilli(),
+		trashEnd.UnixMilli(), trashEnd.Add(-5*time.Second).UnixMilli(),
+		time.Now().UnixMilli(), time.Now().Add(-time.Second).UnixMilli(),
+	)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(fixture))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 10)
+	if !e.exportGarbageCollection(ch) {
+		t.Fatal("exportGarbageCollection() = false, want true")
+	}
+	close(ch)
+	lastRuns := make(map[string]float64)
+	durations := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		var gcType string
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "type" {
+				gcType = label.GetValue()
+			}
+		}
+		switch metric.Desc() {
+		case e.metrics.gc["lastRun"]:
+			lastRuns[gcType] = m.GetGauge().GetValue()
+		case e.metrics.gc["duration"]:
+			durations[gcType] = m.GetGauge().GetValue()
+		}
+	}
+
+	expectedDurations := map[string]float64{"full": 90, "trash_and_binaries": 5}
+	expectedLastRuns := map[string]float64{"full": float64(fullEnd.UnixMilli()) / 1000, "trash_and_binaries": float64(trashEnd.UnixMilli()) / 1000}
+	if len(durations) != len(expectedDurations) || len(lastRuns) != len(expectedLastRuns) {
+		t.Fatalf("exportGarbageCollection() durations = %v, last runs = %v, want types %v", durations, lastRuns, expectedDurations)
+	}
+	for gcType, expected := range expectedDurations {
+		if durations[gcType] != expected {
+			t.Errorf("Duration of %s = %v, want %v", gcType, durations[gcType], expected)
+		}
+	}
+	for gcType, expected := range expectedLastRuns {
+		if lastRuns[gcType] != expected {
+			t.Errorf("Last run of %s = %v, want %v", gcType, lastRuns[gcType], expected)
+		}
+	}
+
+	// The other subsystems derived from the open metrics reuse them.
+	if !e.exportJVMMetrics(make(chan prometheus.Metric, 10)) {
+		t.Fatal("exportJVMMetrics() = false, want true")
+	}
+	if actual := requests.Load(); actual != 1 {
+		t.Errorf("Open metrics fetched %d times, want 1", actual)
+	}
+}
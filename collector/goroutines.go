@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// goroutineWindow is the number of scrapes the goroutine growth is averaged over.
+const goroutineWindow = 5
+
+// goroutineGrowth returns the average change of the goroutine count per
+// scrape over the given samples, oldest first. It's 0 with less than two
+// samples.
+func goroutineGrowth(samples []int) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	return float64(samples[len(samples)-1]-samples[0]) / float64(len(samples)-1)
+}
+
+// exportGoroutines samples the number of goroutines of the exporter and
+// emits it along with its growth over the last scrapes, which keeps rising
+// if goroutines leak. Must be called with e.mutex held.
+func (e *Exporter) exportGoroutines(ch chan<- prometheus.Metric) {
+	count := runtime.NumGoroutine()
+	e.goroutineSamples = append(e.goroutineSamples, count)
+	if len(e.goroutineSamples) > goroutineWindow {
+		e.goroutineSamples = e.goroutineSamples[len(e.goroutineSamples)-goroutineWindow:]
+	}
+	ch <- prometheus.MustNewConstMetric(e.metrics.exporter["goroutines"], prometheus.GaugeValue, float64(count))
+	ch <- prometheus.MustNewConstMetric(e.metrics.exporter["goroutinesGrowth"], prometheus.GaugeValue, goroutineGrowth(e.goroutineSamples))
+}
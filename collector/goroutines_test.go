@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestGoroutineGrowth(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []int
+		expected float64
+	}{
+		{name: "No samples", samples: nil, expected: 0},
+		{name: "Single sample", samples: []int{10}, expected: 0},
+		{name: "Stable", samples: []int{10, 12, 10, 11, 10}, expected: 0},
+		{name: "Rising", samples: []int{10, 12, 14, 16, 18}, expected: 2},
+		{name: "Falling", samples: []int{20, 18}, expected: -2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := goroutineGrowth(tt.samples); got != tt.expected {
+				t.Errorf("goroutineGrowth() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExportGoroutinesWindow(t *testing.T) {
+	e := &Exporter{metrics: newMetricDescriptors(defaultNamespace)}
+	for range goroutineWindow + 3 {
+		ch := make(chan prometheus.Metric, 2)
+		e.exportGoroutines(ch)
+		close(ch)
+		if n := len(ch); n != 2 {
+			t.Fatalf("exportGoroutines() sent %d metrics, want 2", n)
+		}
+	}
+	if len(e.goroutineSamples) != goroutineWindow {
+		t.Errorf("kept %d samples, want %d", len(e.goroutineSamples), goroutineWindow)
+	}
+}
@@ -0,0 +1,50 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// exportJVMMetrics exports the JVM garbage collection stats of Artifactory.
+// They are only exposed by some editions and versions, so nothing is
+// exported if they aren't available.
+func (e *Exporter) exportJVMMetrics(ch chan<- prometheus.Metric) bool {
+	openMetrics, err := e.fetchOpenMetrics()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching JVM metrics",
+			"err", err.Error(),
+		)
+		return false
+	}
+	jvmStats, err := e.client.ParseJVMMetrics(openMetrics)
+	if err != nil {
+		e.logger.Error(
+			"Couldn't parse the JVM metrics of the open metrics",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	if !jvmStats.Available {
+		e.logger.Debug("No JVM garbage collection metrics available")
+		return true
+	}
+
+	for _, gc := range jvmStats.GCCollectors {
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "gcSeconds",
+			"collector", gc.Name,
+			"value", gc.Seconds,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.jvm["gcSeconds"], prometheus.CounterValue, gc.Seconds, gc.Name, jvmStats.NodeId)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "gcCount",
+			"collector", gc.Name,
+			"value", gc.Count,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.jvm["gcCount"], prometheus.CounterValue, float64(gc.Count), gc.Name, jvmStats.NodeId)
+	}
+	return true
+}
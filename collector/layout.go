@@ -0,0 +1,45 @@
+package collector
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unknownLayout is the layout of repositories without a configured layout.
+const unknownLayout = "unknown"
+
+// exportRepoLayouts exports the number of repositories using each repository
+// layout, e.g. maven-2-default. It includes all repositories regardless of
+// the repository filter.
+func (e *Exporter) exportRepoLayouts(ch chan<- prometheus.Metric) bool {
+	repoConfigs, err := e.client.FetchRepositoryConfigs()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching repository configurations",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+
+	counts := make(map[string]int)
+	for _, repoConfig := range repoConfigs.Repositories {
+		layout := repoConfig.RepoLayoutRef
+		if layout == "" {
+			layout = unknownLayout
+		}
+		counts[layout]++
+	}
+	for _, layout := range slices.Sorted(maps.Keys(counts)) {
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "byLayout",
+			"layout", layout,
+			"value", counts[layout],
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.layout["byLayout"], prometheus.GaugeValue, float64(counts[layout]), layout, repoConfigs.NodeId)
+	}
+	return true
+}
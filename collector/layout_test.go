@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportRepoLayouts(t *testing.T) {
+	configs := map[string]string{
+		"libs-release":  `{"key": "libs-release", "rclass": "local", "packageType": "maven", "repoLayoutRef": "maven-2-default"}`,
+		"libs-snapshot": `{"key": "libs-snapshot", "rclass": "local", "packageType": "maven", "repoLayoutRef": "maven-2-default"}`,
+		"generic-local": `{"key": "generic-local", "rclass": "local", "packageType": "generic", "repoLayoutRef": "simple-default"}`,
+		"docker-local":  `{"key": "docker-local", "rclass": "local", "packageType": "docker"}`,
+		"npm-remote":    `{"key": "npm-remote", "rclass": "remote", "packageType": "npm", "repoLayoutRef": ""}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/api/repositories") {
+			w.Write([]byte(`[{"key": "libs-release"}, {"key": "libs-snapshot"}, {"key": "generic-local"}, {"key": "docker-local"}, {"key": "npm-remote"}]`))
+			return
+		}
+		key := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		repoConfig, ok := configs[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(repoConfig))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 10)
+	if !e.exportRepoLayouts(ch) {
+		t.Fatal("exportRepoLayouts() = false, want true")
+	}
+	close(ch)
+	actual := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "layout" {
+				actual[label.GetValue()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	expected := map[string]float64{"maven-2-default": 2, "simple-default": 1, "unknown": 2}
+	if len(actual) != len(expected) {
+		t.Fatalf("Repositories by layout = %v, want %v", actual, expected)
+	}
+	for layout, count := range expected {
+		if actual[layout] != count {
+			t.Errorf("Repositories with layout %s = %v, want %v", layout, actual[layout], count)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// nativeMetricsSubsystem is the subsystem of the re-exported native metrics,
+// which keeps their names from colliding with the metrics of the exporter.
+const nativeMetricsSubsystem = "native"
+
+// exportNativeMetrics re-exports the selected metric families of the
+// Artifactory open metrics, prefixed with artifactory_native_. Families which
+// Artifactory doesn't expose are skipped.
+func (e *Exporter) exportNativeMetrics(ch chan<- prometheus.Metric) bool {
+	openMetrics, err := e.fetchOpenMetrics()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching native metrics",
+			"err", err.Error(),
+		)
+		return false
+	}
+	nativeMetrics, err := e.client.ParseNativeMetrics(openMetrics, e.nativeMetrics)
+	if err != nil {
+		e.logger.Error(
+			"Couldn't parse the native metrics of the open metrics",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	for _, family := range nativeMetrics.Families {
+		name := prometheus.BuildFQName(e.namespace, nativeMetricsSubsystem, family.GetName())
+		for _, metric := range family.GetMetric() {
+			m, err := newNativeMetric(name, family.GetHelp(), family.GetType(), metric)
+			if err != nil {
+				e.logger.Warn(
+					"Couldn't re-export native metric",
+					"metric", family.GetName(),
+					"err", err.Error(),
+				)
+				continue
+			}
+			e.logger.Debug(
+				logDbgMsgRegMetric,
+				"metric", name,
+			)
+			ch <- m
+		}
+	}
+	return true
+}
+
+// newNativeMetric converts a metric of a parsed metric family to a constant
+// metric with the given name.
+func newNativeMetric(name, help string, metricType dto.MetricType, metric *dto.Metric) (prometheus.Metric, error) {
+	labelNames := make([]string, 0, len(metric.GetLabel()))
+	labelValues := make([]string, 0, len(metric.GetLabel()))
+	for _, label := range metric.GetLabel() {
+		labelNames = append(labelNames, label.GetName())
+		labelValues = append(labelValues, label.GetValue())
+	}
+	desc := prometheus.NewDesc(name, help, labelNames, nil)
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		return prometheus.NewConstMetric(desc, prometheus.CounterValue, metric.GetCounter().GetValue(), labelValues...)
+	case dto.MetricType_GAUGE:
+		return prometheus.NewConstMetric(desc, prometheus.GaugeValue, metric.GetGauge().GetValue(), labelValues...)
+	case dto.MetricType_HISTOGRAM:
+		histogram := metric.GetHistogram()
+		buckets := make(map[float64]uint64, len(histogram.GetBucket()))
+		for _, bucket := range histogram.GetBucket() {
+			buckets[bucket.GetUpperBound()] = bucket.GetCumulativeCount()
+		}
+		return prometheus.NewConstHistogram(desc, histogram.GetSampleCount(), histogram.GetSampleSum(), buckets, labelValues...)
+	case dto.MetricType_SUMMARY:
+		summary := metric.GetSummary()
+		quantiles := make(map[float64]float64, len(summary.GetQuantile()))
+		for _, quantile := range summary.GetQuantile() {
+			quantiles[quantile.GetQuantile()] = quantile.GetValue()
+		}
+		return prometheus.NewConstSummary(desc, summary.GetSampleCount(), summary.GetSampleSum(), quantiles, labelValues...)
+	default:
+		return prometheus.NewConstMetric(desc, prometheus.UntypedValue, metric.GetUntyped().GetValue(), labelValues...)
+	}
+}
@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+// nativeMetricsCollector collects the native metrics of an exporter.
+type nativeMetricsCollector struct {
+	e *Exporter
+}
+
+func (c nativeMetricsCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c nativeMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.e.exportNativeMetrics(ch)
+}
+
+func TestExportNativeMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`# HELP jfrt_runtime_heap_freememory_bytes Free memory
+# TYPE jfrt_runtime_heap_freememory_bytes gauge
+jfrt_runtime_heap_freememory_bytes 1.2e+09
+# HELP jfrt_http_connections_available_total Available connections
+# TYPE jfrt_http_connections_available_total counter
+jfrt_http_connections_available_total{pool="default"} 20
+# HELP jfrt_db_query_seconds Query duration
+# TYPE jfrt_db_query_seconds histogram
+jfrt_db_query_seconds_bucket{le="0.1"} 3
+jfrt_db_query_seconds_bucket{le="1"} 5
+jfrt_db_query_seconds_bucket{le="+Inf"} 6
+jfrt_db_query_seconds_sum 4.2
+jfrt_db_query_seconds_count 6
+# EOF`))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		NativeMetrics:         []string{"jfrt_http_connections_available_total", "jfrt_db_query_seconds"},
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	expected := `# HELP artifactory_native_jfrt_db_query_seconds Query duration
+# TYPE artifactory_native_jfrt_db_query_seconds histogram
+artifactory_native_jfrt_db_query_seconds_bucket{le="0.1"} 3
+artifactory_native_jfrt_db_query_seconds_bucket{le="1"} 5
+artifactory_native_jfrt_db_query_seconds_bucket{le="+Inf"} 6
+artifactory_native_jfrt_db_query_seconds_sum 4.2
+artifactory_native_jfrt_db_query_seconds_count 6
+# HELP artifactory_native_jfrt_http_connections_available_total Available connections
+# TYPE artifactory_native_jfrt_http_connections_available_total counter
+artifactory_native_jfrt_http_connections_available_total{pool="default"} 20
+`
+	if err := testutil.CollectAndCompare(nativeMetricsCollector{e}, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
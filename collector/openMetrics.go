@@ -7,13 +7,33 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	ioPrometheusClient "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
 )
 
+// fetchedOpenMetrics is the result of fetching the open metrics.
+type fetchedOpenMetrics struct {
+	metrics artifactory.OpenMetrics
+	err     error
+}
+
+// fetchOpenMetrics returns the open metrics of Artifactory. They are only
+// fetched once per collection, as several subsystems are derived from them.
+func (e *Exporter) fetchOpenMetrics() (artifactory.OpenMetrics, error) {
+	if e.openMetrics == nil {
+		openMetrics, err := e.client.FetchOpenMetrics()
+		if err != nil {
+			e.totalAPIErrors.Inc()
+		}
+		e.openMetrics = &fetchedOpenMetrics{metrics: openMetrics, err: err}
+	}
+	return e.openMetrics.metrics, e.openMetrics.err
+}
+
 func (e *Exporter) exportOpenMetrics(ch chan<- prometheus.Metric) error {
-	openMetrics, err := e.client.FetchOpenMetrics()
+	openMetrics, err := e.fetchOpenMetrics()
 	if err != nil {
 		e.logger.Error("There was an issue when try to fetch openMetrics")
-		e.totalAPIErrors.Inc()
 		return err
 	}
 
@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// packageCounter counts the packages in a repository of a package type.
+type packageCounter func(client *artifactory.Client, repoKey string) (artifactory.PackageCount, error)
+
+// packageCounters are the registered package counters by package type. A
+// package type is counted if repositories of it are configured, exporting
+// the <namespace>_<type>_packages_total metric.
+var packageCounters = map[string]packageCounter{}
+
+// registerPackageCounter registers the package counter of a package type. It
+// is meant to be called from init functions.
+func registerPackageCounter(packageType string, counter packageCounter) {
+	packageCounters[packageType] = counter
+}
+
+// initPackageMetrics creates a metric descriptor for every package type with
+// configured repositories. The descriptors belong to e, so exporters created
+// on reload with other repositories don't share them.
+func (e *Exporter) initPackageMetrics() {
+	e.packageMetrics = metrics{}
+	for packageType := range e.packageRepos {
+		if _, ok := packageCounters[packageType]; !ok {
+			e.logger.Warn("No package counter registered for package type", "packageType", packageType)
+			continue
+		}
+		e.packageMetrics[packageType] = newMetric(e.namespace, "packages_total", packageType, fmt.Sprintf("Number of packages in a %s repository.", packageType), append([]string{"repo"}, defaultLabelNames...))
+		e.logger.Debug("Init metric", "metricName", packageType+"_packages_total")
+	}
+}
+
+// exportPackages exports the number of packages in the configured repositories
+// of every package type. It returns false if any repository couldn't be
+// counted.
+func (e *Exporter) exportPackages(ch chan<- prometheus.Metric) bool {
+	ok := true
+	for _, packageType := range slices.Sorted(maps.Keys(e.packageMetrics)) {
+		count := packageCounters[packageType]
+		for _, repo := range e.packageRepos[packageType] {
+			if e.onlyRepo != "" && repo != e.onlyRepo {
+				continue
+			}
+			packages, err := count(e.client, repo)
+			if err != nil {
+				e.logger.Error(
+					"Couldn't scrape Artifactory when counting packages",
+					"packageType", packageType,
+					"repo", repo,
+					"err", err.Error(),
+				)
+				e.totalAPIErrors.Inc()
+				ok = false
+				continue
+			}
+			e.logger.Debug(
+				logDbgMsgRegMetric,
+				"metric", packageType+"_packages_total",
+				"repo", repo,
+				"value", packages.Packages,
+			)
+			ch <- prometheus.MustNewConstMetric(e.packageMetrics[packageType], prometheus.GaugeValue, float64(packages.Packages), repo, packages.NodeId)
+		}
+	}
+	return ok
+}
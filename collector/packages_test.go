@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportPackages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifactory/api/pypi/pypi-local/simple/":
+			w.Write([]byte(`<html><body><a href="requests/">requests</a><a href="flask/">flask</a></body></html>`))
+		case "/artifactory/api/pypi/pypi-remote/simple/":
+			w.Write([]byte(`<html><body><a href="numpy/">numpy</a></body></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		PackageRepos:          map[string][]string{"pypi": {"pypi-local", "pypi-remote"}},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	if _, ok := e.packageMetrics["pypi"]; !ok {
+		t.Fatal("No metric created for package type pypi")
+	}
+	ch := make(chan prometheus.Metric, 10)
+	if !e.exportPackages(ch) {
+		t.Fatal("exportPackages() = false, want true")
+	}
+	close(ch)
+	actual := make(map[string]float64)
+	for metric := range ch {
+		if metric.Desc() != e.packageMetrics["pypi"] {
+			t.Errorf("Unexpected metric %s", metric.Desc())
+		}
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "repo" {
+				actual[label.GetValue()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	expected := map[string]float64{"pypi-local": 2, "pypi-remote": 1}
+	if len(actual) != len(expected) {
+		t.Fatalf("Packages = %v, want %v", actual, expected)
+	}
+	for repo, count := range expected {
+		if actual[repo] != count {
+			t.Errorf("Packages in %s = %v, want %v", repo, actual[repo], count)
+		}
+	}
+}
+
+func TestInitPackageMetricsUnknownType(t *testing.T) {
+	e := &Exporter{
+		namespace:    defaultNamespace,
+		packageRepos: map[string][]string{"unknown": {"repo"}},
+		logger:       newTestLogger(),
+	}
+	e.initMetrics()
+	if len(e.packageMetrics) != 0 {
+		t.Errorf("packageMetrics = %v, want none for unregistered package types", e.packageMetrics)
+	}
+}
+
+func TestInitPackageMetricsReload(t *testing.T) {
+	previous := &Exporter{
+		namespace:    defaultNamespace,
+		packageRepos: map[string][]string{"pypi": {"pypi-local"}},
+		logger:       newTestLogger(),
+	}
+	previous.initMetrics()
+	// An exporter created on reload without package repositories.
+	e := &Exporter{
+		namespace: defaultNamespace,
+		logger:    newTestLogger(),
+	}
+	e.initMetrics()
+	if len(e.packageMetrics) != 0 {
+		t.Errorf("packageMetrics = %v, want none after reload without package repositories", e.packageMetrics)
+	}
+	if _, ok := previous.packageMetrics["pypi"]; !ok {
+		t.Error("Metric of package type pypi of the previous exporter was removed")
+	}
+}
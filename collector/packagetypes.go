@@ -0,0 +1,43 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// countPackageTypes returns the number of distinct package types of
+// repositories, ignoring case.
+func countPackageTypes(repositories []artifactory.Repository) int {
+	packageTypes := make(map[string]struct{})
+	for _, repo := range repositories {
+		if repo.PackageType == "" {
+			continue
+		}
+		packageTypes[strings.ToLower(repo.PackageType)] = struct{}{}
+	}
+	return len(packageTypes)
+}
+
+// exportDistinctPackageTypes exports the number of distinct package types in use.
+func (e *Exporter) exportDistinctPackageTypes(ch chan<- prometheus.Metric) bool {
+	repositories, err := e.client.FetchRepositories()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching repositories",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	count := float64(countPackageTypes(repositories.Repositories))
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "packageTypes",
+		"value", count,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.repo["packageTypes"], prometheus.GaugeValue, count, repositories.NodeId)
+	return true
+}
@@ -0,0 +1,68 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportDistinctPackageTypes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/artifactory/api/repositories" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		w.Write([]byte(`[
+			{"key": "libs-release", "type": "LOCAL", "packageType": "Maven"},
+			{"key": "maven-remote", "type": "REMOTE", "packageType": "maven"},
+			{"key": "npm-local", "type": "LOCAL", "packageType": "Npm"},
+			{"key": "docker-local", "type": "LOCAL", "packageType": "Docker"},
+			{"key": "docker-virtual", "type": "VIRTUAL", "packageType": "Docker"},
+			{"key": "pypi-remote", "type": "REMOTE", "packageType": "Pypi"},
+			{"key": "generic-local", "type": "LOCAL", "packageType": "Generic"}
+		]`))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 1)
+	if !e.exportDistinctPackageTypes(ch) {
+		t.Fatal("exportDistinctPackageTypes() = false, want true")
+	}
+	close(ch)
+	metric := <-ch
+	if metric.Desc() != e.metrics.repo["packageTypes"] {
+		t.Fatalf("Exported %s, want distinct_package_types_total", metric.Desc())
+	}
+	var m dto.Metric
+	if err := metric.Write(&m); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if value := m.GetGauge().GetValue(); value != 5 {
+		t.Errorf("distinct_package_types_total = %v, want 5", value)
+	}
+	if nodeId := m.GetLabel()[0].GetValue(); nodeId != "test-node" {
+		t.Errorf("node_id = %s, want test-node", nodeId)
+	}
+}
@@ -0,0 +1,11 @@
+package collector
+
+import (
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// init registers the PyPI package counter, which counts the projects listed
+// by the simple index of a repository.
+func init() {
+	registerPackageCounter("pypi", (*artifactory.Client).FetchPyPIProjects)
+}
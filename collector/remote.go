@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// exportRemoteRepos exports whether each remote repository is marked offline
+// in its configuration.
+func (e *Exporter) exportRemoteRepos(ch chan<- prometheus.Metric) bool {
+	remoteRepos, err := e.client.FetchRemoteRepositories()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching remote repositories",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+
+	// Remote repositories aggregated into "other" may share labels.
+	merged := newMaxMetrics()
+	for _, remoteRepo := range remoteRepos.Repositories {
+		repo, ok := e.repoLabel(remoteRepo.Key)
+		if !ok {
+			continue
+		}
+		packageType := strings.ToLower(remoteRepo.PackageType)
+		offline := convArtiToPromBool(remoteRepo.Offline)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "offline",
+			"repo", repo,
+			"package_type", packageType,
+			"value", offline,
+		)
+		merged.add(e.metrics.remoteRepo["offline"], offline, repo, packageType, remoteRepos.NodeId)
+	}
+	merged.export(ch)
+	return true
+}
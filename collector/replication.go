@@ -21,29 +21,85 @@ func (e *Exporter) exportReplications(ch chan<- prometheus.Metric) error {
 		e.logger.Debug("No replications stats found")
 		return nil
 	}
+	// Replications of repositories aggregated into "other" may share labels.
+	merged := newMaxMetrics()
+	// Multi-push repositories have a replication per target.
+	var repos []string
+	targets := make(map[string]int)
 	for _, replication := range replications.Replications {
-		for metricName, metric := range replicationMetrics {
+		repo, ok := e.repoLabel(replication.RepoKey)
+		if !ok {
+			continue
+		}
+		if _, exists := targets[repo]; !exists {
+			repos = append(repos, repo)
+		}
+		targets[repo]++
+		rType := strings.ToLower(replication.ReplicationType)
+		rURL := strings.ToLower(replication.URL)
+		cronExp := replication.CronExp
+		status := replication.Status
+		if repo == otherRepoLabel {
+			// The targets, schedules and states of the aggregated repositories
+			// would split "other" into a series per replication again.
+			rURL, cronExp, status = "", "", ""
+		}
+		for metricName, metric := range e.metrics.replication {
 			switch metricName {
 			case "enabled":
 				enabled := convArtiToPromBool(replication.Enabled)
-				repo := replication.RepoKey
-				rType := strings.ToLower(replication.ReplicationType)
-				rURL := strings.ToLower(replication.URL)
-				cronExp := replication.CronExp
-				status := replication.Status
 				e.logger.Debug(
 					"Registering metric",
 					"metric", metricName,
-					"repo", replication.RepoKey,
+					"repo", repo,
 					"type", rType,
 					"url", rURL,
 					"cron", cronExp,
 					"status", status,
 					"value", enabled,
 				)
-				ch <- prometheus.MustNewConstMetric(metric, prometheus.GaugeValue, enabled, repo, rType, rURL, cronExp, status, replications.NodeId)
+				merged.add(metric, enabled, repo, rType, rURL, cronExp, status, replications.NodeId)
+			case "lag":
+				lag, ok := replication.LagSeconds()
+				if !ok {
+					continue
+				}
+				e.logger.Debug(
+					"Registering metric",
+					"metric", metricName,
+					"repo", repo,
+					"type", rType,
+					"url", rURL,
+					"value", lag,
+				)
+				merged.add(metric, lag, repo, rType, rURL, replications.NodeId)
+			case "failed":
+				failed, ok := replication.LastRunFailed()
+				if !ok {
+					continue
+				}
+				value := convArtiToPromBool(failed)
+				e.logger.Debug(
+					"Registering metric",
+					"metric", metricName,
+					"repo", repo,
+					"type", rType,
+					"url", rURL,
+					"value", value,
+				)
+				merged.add(metric, value, repo, rType, rURL, replications.NodeId)
 			}
 		}
 	}
+	merged.export(ch)
+	for _, repo := range repos {
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "targets",
+			"repo", repo,
+			"value", targets[repo],
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.replication["targets"], prometheus.GaugeValue, float64(targets[repo]), repo, replications.NodeId)
+	}
 	return nil
 }
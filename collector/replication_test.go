@@ -0,0 +1,136 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportReplicationTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"replicationType":"PUSH","enabled":true,"repoKey":"libs-release","url":"http://target-1/libs-release"},
+			{"replicationType":"PUSH","enabled":true,"repoKey":"libs-release","url":"http://target-2/libs-release"},
+			{"replicationType":"PUSH","enabled":true,"repoKey":"libs-release","url":"http://target-3/libs-release"},
+			{"replicationType":"PULL","enabled":true,"repoKey":"npm-remote","url":"https://registry.npmjs.org"}
+		]`))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 20)
+	if err := e.exportReplications(ch); err != nil {
+		t.Fatalf("exportReplications() error = %v", err)
+	}
+	close(ch)
+	targets := make(map[string]float64)
+	for metric := range ch {
+		if metric.Desc() != e.metrics.replication["targets"] {
+			continue
+		}
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		targets[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+	}
+	expected := map[string]float64{"libs-release": 3, "npm-remote": 1}
+	if len(targets) != len(expected) {
+		t.Fatalf("Exported targets of %v, want %v", targets, expected)
+	}
+	for repo, count := range expected {
+		if targets[repo] != count {
+			t.Errorf("targets_total{name=%q} = %v, want %v", repo, targets[repo], count)
+		}
+	}
+}
+
+func TestExportReplicationsOther(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"replicationType":"PUSH","enabled":true,"repoKey":"team-a","url":"http://target-1/team-a","cronExp":"0 0 * * * ?","status":"ok"},
+			{"replicationType":"PUSH","enabled":true,"repoKey":"team-b","url":"http://target-2/team-b","cronExp":"0 30 * * * ?","status":"failure"},
+			{"replicationType":"PUSH","enabled":false,"repoKey":"__other__","url":"http://target-3/__other__","cronExp":"0 0 0 * * ?","status":"ok"},
+			{"replicationType":"PUSH","enabled":true,"repoKey":"libs-release","url":"http://target-4/libs-release","cronExp":"0 0 * * * ?","status":"ok"}
+		]`))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:    server.URL + "/artifactory",
+		ArtiTimeout:      5 * time.Second,
+		MetricsNamespace: defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{
+			RepoFilter: config.RepoFilter{Denylist: regexp.MustCompile("^team-.*")},
+		},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 20)
+	if err := e.exportReplications(ch); err != nil {
+		t.Fatalf("exportReplications() error = %v", err)
+	}
+	close(ch)
+	enabled := make(map[string][]map[string]string)
+	targets := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		labels := make(map[string]string)
+		for _, label := range m.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		switch metric.Desc() {
+		case e.metrics.replication["enabled"]:
+			enabled[labels["name"]] = append(enabled[labels["name"]], labels)
+		case e.metrics.replication["targets"]:
+			targets[labels["name"]] = m.GetGauge().GetValue()
+		}
+	}
+	if len(enabled[otherRepoLabel]) != 1 {
+		t.Fatalf("Exported %d enabled series of %s, want 1", len(enabled[otherRepoLabel]), otherRepoLabel)
+	}
+	for _, name := range []string{"url", "cron_exp", "status"} {
+		if value := enabled[otherRepoLabel][0][name]; value != "" {
+			t.Errorf("enabled{name=%q} has %s=%q, want empty", otherRepoLabel, name, value)
+		}
+	}
+	if len(enabled["libs-release"]) != 1 || enabled["libs-release"][0]["url"] != "http://target-4/libs-release" {
+		t.Errorf("enabled{name=\"libs-release\"} = %v, want the url of the replication", enabled["libs-release"])
+	}
+	if targets[otherRepoLabel] != 3 {
+		t.Errorf("targets_total{name=%q} = %v, want 3", otherRepoLabel, targets[otherRepoLabel])
+	}
+}
@@ -0,0 +1,105 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// otherRepoLabel is the repository name the metrics of repositories excluded
+// by the repository filter are aggregated into. A repository with this key is
+// always aggregated, so its metrics can't collide with the aggregated ones.
+const otherRepoLabel = "__other__"
+
+// repoLabel returns the name to label the metrics of repo with. The second
+// return value is false if the metrics of repo should be dropped. When the
+// collection is restricted to a single repository, the metrics of all others
+// are dropped before the repository filter is applied.
+func (e *Exporter) repoLabel(repo string) (string, bool) {
+	if e.onlyRepo != "" && repo != e.onlyRepo {
+		return "", false
+	}
+	filter := e.exporterRuntimeConfig.RepoFilter
+	if repo != otherRepoLabel && filter.Matches(repo) {
+		return repo, true
+	}
+	if filter.DropUnmatched {
+		return "", false
+	}
+	return otherRepoLabel, true
+}
+
+// filterRepoSummaries applies the repository filter to repoSummaries. Excluded
+// repositories are dropped or summed up per type and package type into a
+// summary named otherRepoLabel.
+func (e *Exporter) filterRepoSummaries(repoSummaries []repoSummary) []repoSummary {
+	filtered := make([]repoSummary, 0, len(repoSummaries))
+	others := make(map[[2]string]int)
+	for _, rs := range repoSummaries {
+		name, ok := e.repoLabel(rs.Name)
+		if !ok {
+			continue
+		}
+		if name != otherRepoLabel {
+			filtered = append(filtered, rs)
+			continue
+		}
+		key := [2]string{rs.Type, rs.PackageType}
+		idx, exists := others[key]
+		if !exists {
+			other := rs
+			other.Name = otherRepoLabel
+			other.RepoArtifactsSummary = append([]RepoArtifactsSummary(nil), rs.RepoArtifactsSummary...)
+			others[key] = len(filtered)
+			filtered = append(filtered, other)
+			continue
+		}
+		other := &filtered[idx]
+		other.FoldersCount += rs.FoldersCount
+		other.FilesCount += rs.FilesCount
+		other.UsedSpace += rs.UsedSpace
+		other.ItemsCount += rs.ItemsCount
+		other.Percentage += rs.Percentage
+		for i := range other.RepoArtifactsSummary {
+			if i < len(rs.RepoArtifactsSummary) {
+				other.RepoArtifactsSummary[i].TotalCreated += rs.RepoArtifactsSummary[i].TotalCreated
+				other.RepoArtifactsSummary[i].TotalDownloaded += rs.RepoArtifactsSummary[i].TotalDownloaded
+			}
+		}
+	}
+	return filtered
+}
+
+// maxMetrics collects gauges and keeps the maximum value of metrics with the
+// same labels. It merges the metrics of repositories aggregated into "other".
+type maxMetrics struct {
+	keys   []string
+	values map[string]*maxMetric
+}
+
+type maxMetric struct {
+	desc   *prometheus.Desc
+	value  float64
+	labels []string
+}
+
+func newMaxMetrics() *maxMetrics {
+	return &maxMetrics{values: make(map[string]*maxMetric)}
+}
+
+func (m *maxMetrics) add(desc *prometheus.Desc, value float64, labels ...string) {
+	key := desc.String() + "\xff" + strings.Join(labels, "\xff")
+	if existing, ok := m.values[key]; ok {
+		existing.value = max(existing.value, value)
+		return
+	}
+	m.keys = append(m.keys, key)
+	m.values[key] = &maxMetric{desc: desc, value: value, labels: labels}
+}
+
+func (m *maxMetrics) export(ch chan<- prometheus.Metric) {
+	for _, key := range m.keys {
+		metric := m.values[key]
+		ch <- prometheus.MustNewConstMetric(metric.desc, prometheus.GaugeValue, metric.value, metric.labels...)
+	}
+}
@@ -0,0 +1,169 @@
+package collector
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestFilterRepoSummaries(t *testing.T) {
+	repoSummaries := []repoSummary{
+		{Name: "libs-release", Type: "local", PackageType: "maven", UsedSpace: 100, FilesCount: 1, RepoArtifactsSummary: []RepoArtifactsSummary{{period: "1m", TotalDownloaded: 1}}},
+		{Name: "team-a", Type: "local", PackageType: "maven", UsedSpace: 10, FilesCount: 2, RepoArtifactsSummary: []RepoArtifactsSummary{{period: "1m", TotalDownloaded: 2}}},
+		{Name: "team-b", Type: "local", PackageType: "maven", UsedSpace: 20, FilesCount: 3, RepoArtifactsSummary: []RepoArtifactsSummary{{period: "1m", TotalDownloaded: 3}}},
+		{Name: "team-c", Type: "remote", PackageType: "npm", UsedSpace: 30, FilesCount: 4, RepoArtifactsSummary: []RepoArtifactsSummary{{period: "1m", TotalDownloaded: 4}}},
+	}
+
+	tests := []struct {
+		name          string
+		dropUnmatched bool
+		expected      []repoSummary
+	}{
+		{
+			name: "Aggregate into other",
+			expected: []repoSummary{
+				{Name: "libs-release", Type: "local", PackageType: "maven", UsedSpace: 100, FilesCount: 1, RepoArtifactsSummary: []RepoArtifactsSummary{{period: "1m", TotalDownloaded: 1}}},
+				{Name: otherRepoLabel, Type: "local", PackageType: "maven", UsedSpace: 30, FilesCount: 5, RepoArtifactsSummary: []RepoArtifactsSummary{{period: "1m", TotalDownloaded: 5}}},
+				{Name: otherRepoLabel, Type: "remote", PackageType: "npm", UsedSpace: 30, FilesCount: 4, RepoArtifactsSummary: []RepoArtifactsSummary{{period: "1m", TotalDownloaded: 4}}},
+			},
+		},
+		{
+			name:          "Drop",
+			dropUnmatched: true,
+			expected: []repoSummary{
+				{Name: "libs-release", Type: "local", PackageType: "maven", UsedSpace: 100, FilesCount: 1, RepoArtifactsSummary: []RepoArtifactsSummary{{period: "1m", TotalDownloaded: 1}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Exporter{
+				logger: newTestLogger(),
+				exporterRuntimeConfig: config.ExporterRuntimeConfig{
+					RepoFilter: config.RepoFilter{
+						Allowlist:     regexp.MustCompile("^(?:libs-.*)$"),
+						DropUnmatched: tt.dropUnmatched,
+					},
+				},
+			}
+
+			filtered := e.filterRepoSummaries(repoSummaries)
+			if len(filtered) != len(tt.expected) {
+				t.Fatalf("filterRepoSummaries() returned %d summaries, want %d", len(filtered), len(tt.expected))
+			}
+			for i, expected := range tt.expected {
+				actual := filtered[i]
+				if actual.Name != expected.Name || actual.Type != expected.Type || actual.PackageType != expected.PackageType {
+					t.Errorf("Summary %d = %s/%s/%s, want %s/%s/%s", i, actual.Name, actual.Type, actual.PackageType, expected.Name, expected.Type, expected.PackageType)
+				}
+				if actual.UsedSpace != expected.UsedSpace || actual.FilesCount != expected.FilesCount {
+					t.Errorf("Summary %d used space = %v, files = %v, want %v, %v", i, actual.UsedSpace, actual.FilesCount, expected.UsedSpace, expected.FilesCount)
+				}
+				if actual.RepoArtifactsSummary[0].TotalDownloaded != expected.RepoArtifactsSummary[0].TotalDownloaded {
+					t.Errorf("Summary %d downloads = %v, want %v", i, actual.RepoArtifactsSummary[0].TotalDownloaded, expected.RepoArtifactsSummary[0].TotalDownloaded)
+				}
+			}
+			if repoSummaries[1].RepoArtifactsSummary[0].TotalDownloaded != 2 {
+				t.Error("filterRepoSummaries() modified the summaries passed in")
+			}
+		})
+	}
+}
+
+func TestRepoLabelOnlyRepo(t *testing.T) {
+	tests := []struct {
+		name          string
+		onlyRepo      string
+		dropUnmatched bool
+		repo          string
+		expectedLabel string
+		expectedOk    bool
+	}{
+		{
+			name:          "Unrestricted",
+			repo:          "team-a",
+			expectedLabel: otherRepoLabel,
+			expectedOk:    true,
+		},
+		{
+			name:          "Requested repository",
+			onlyRepo:      "libs-release",
+			repo:          "libs-release",
+			expectedLabel: "libs-release",
+			expectedOk:    true,
+		},
+		{
+			name:     "Other repository",
+			onlyRepo: "libs-release",
+			repo:     "libs-snapshot",
+		},
+		{
+			name:          "Requested repository excluded by allowlist",
+			onlyRepo:      "team-a",
+			repo:          "team-a",
+			expectedLabel: otherRepoLabel,
+			expectedOk:    true,
+		},
+		{
+			name:          "Requested repository excluded by allowlist and dropped",
+			onlyRepo:      "team-a",
+			dropUnmatched: true,
+			repo:          "team-a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Exporter{
+				logger:   newTestLogger(),
+				onlyRepo: tt.onlyRepo,
+				exporterRuntimeConfig: config.ExporterRuntimeConfig{
+					RepoFilter: config.RepoFilter{
+						Allowlist:     regexp.MustCompile("^(?:libs-.*)$"),
+						DropUnmatched: tt.dropUnmatched,
+					},
+				},
+			}
+			label, ok := e.repoLabel(tt.repo)
+			if label != tt.expectedLabel || ok != tt.expectedOk {
+				t.Errorf("repoLabel() = %q, %v, want %q, %v", label, ok, tt.expectedLabel, tt.expectedOk)
+			}
+		})
+	}
+}
+
+func TestMaxMetrics(t *testing.T) {
+	desc := prometheus.NewDesc("test_metric", "Test metric.", []string{"name"}, nil)
+	merged := newMaxMetrics()
+	merged.add(desc, 1, "libs-release")
+	merged.add(desc, 3, otherRepoLabel)
+	merged.add(desc, 5, otherRepoLabel)
+	merged.add(desc, 2, otherRepoLabel)
+
+	ch := make(chan prometheus.Metric, 4)
+	merged.export(ch)
+	close(ch)
+
+	expected := []float64{1, 5}
+	var actual []float64
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		actual = append(actual, m.GetGauge().GetValue())
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("export() sent %d metrics, want %d", len(actual), len(expected))
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("Metric %d = %v, want %v", i, actual[i], expected[i])
+		}
+	}
+}
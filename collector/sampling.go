@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// subsystemSample holds the metrics of a subsystem last scraped from Artifactory.
+type subsystemSample struct {
+	metrics  []prometheus.Metric
+	skipped  int
+	scrapeAt time.Time
+}
+
+// sample runs collect for subsystem only on every n-th scrape, n being the
+// configured scrape interval multiplier of the subsystem. The metrics of the
+// last run are sent again in between. Runs which fail aren't kept, so the
+// next scrape runs collect again. Collections restricted to a single
+// repository always run collect and don't touch the kept metrics. Must be
+// called with e.mutex held.
+func (e *Exporter) sample(subsystem string, ch chan<- prometheus.Metric, collect func(ch chan<- prometheus.Metric) bool) bool {
+	multiplier := e.scrapeMultipliers[subsystem]
+	if multiplier <= 1 || e.onlyRepo != "" {
+		return collect(ch)
+	}
+
+	if last, ok := e.samples[subsystem]; ok && last.skipped < multiplier-1 {
+		e.logger.Debug(
+			"Reusing metrics of last subsystem scrape",
+			"subsystem", subsystem,
+			"age", time.Since(last.scrapeAt),
+		)
+		last.skipped++
+		for _, m := range last.metrics {
+			ch <- m
+		}
+		return true
+	}
+
+	sample := &subsystemSample{scrapeAt: time.Now()}
+	buf := make(chan prometheus.Metric)
+	var ok bool
+	go func() {
+		ok = collect(buf)
+		close(buf)
+	}()
+	for m := range buf {
+		sample.metrics = append(sample.metrics, m)
+		ch <- m
+	}
+	if !ok {
+		delete(e.samples, subsystem)
+		return false
+	}
+	e.samples[subsystem] = sample
+	return true
+}
+
+// exportSubsystemSamples emits the age of the metrics of every sampled subsystem.
+func (e *Exporter) exportSubsystemSamples(ch chan<- prometheus.Metric) {
+	for subsystem, sample := range e.samples {
+		ch <- prometheus.MustNewConstMetric(e.metrics.exporter["subsystemLastScrape"], prometheus.GaugeValue, time.Since(sample.scrapeAt).Seconds(), subsystem)
+	}
+}
@@ -0,0 +1,141 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestSample(t *testing.T) {
+	desc := prometheus.NewDesc("test_metric", "Test metric.", nil, nil)
+
+	tests := []struct {
+		name         string
+		multiplier   int
+		results      []bool
+		expectedRuns []bool
+	}{
+		{
+			name:         "No multiplier",
+			multiplier:   0,
+			results:      []bool{true, true, true},
+			expectedRuns: []bool{true, true, true},
+		},
+		{
+			name:         "Every third scrape",
+			multiplier:   3,
+			results:      []bool{true, true, true, true, true},
+			expectedRuns: []bool{true, false, false, true, false},
+		},
+		{
+			name:         "Failed scrape is retried",
+			multiplier:   3,
+			results:      []bool{false, true, true},
+			expectedRuns: []bool{true, true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Exporter{
+				logger:            newTestLogger(),
+				scrapeMultipliers: map[string]int{"docker": tt.multiplier},
+				samples:           make(map[string]*subsystemSample),
+			}
+
+			for i, result := range tt.results {
+				ran := false
+				collect := func(ch chan<- prometheus.Metric) bool {
+					ran = true
+					ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(i))
+					return result
+				}
+
+				ch := make(chan prometheus.Metric, 10)
+				ok := e.sample("docker", ch, collect)
+				close(ch)
+
+				if ran != tt.expectedRuns[i] {
+					t.Errorf("Scrape %d: collect ran = %v, want %v", i, ran, tt.expectedRuns[i])
+				}
+				if ran && ok != result {
+					t.Errorf("Scrape %d: sample() = %v, want %v", i, ok, result)
+				}
+				if len(ch) != 1 {
+					t.Errorf("Scrape %d: sample() sent %d metrics, want 1", i, len(ch))
+				}
+			}
+		})
+	}
+}
+
+func TestSampleStorageAndArtifactsIndependently(t *testing.T) {
+	var storageScrapes atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifactory/api/system/ping":
+			w.Write([]byte("OK"))
+		case "/artifactory/api/system/version":
+			w.Write([]byte(`{"version":"7.77.3","revision":"77703900"}`))
+		case "/artifactory/api/system/license":
+			w.Write([]byte(`{"type":"OSS"}`))
+		case "/artifactory/api/storageinfo":
+			storageScrapes.Add(1)
+			w.Write([]byte(`{"binariesSummary":{"binariesCount":"1"},"repositoriesSummaryList":[{"repoKey":"libs-release","repoType":"LOCAL","packageType":"Maven","foldersCount":1,"filesCount":2,"usedSpace":"1.5 MB","itemsCount":3,"percentage":"10%"}]}`))
+		case "/artifactory/api/repositories":
+			w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:     server.URL + "/artifactory",
+		ArtiTimeout:       5 * time.Second,
+		MetricsNamespace:  defaultNamespace,
+		SaaS:              true,
+		ScrapeMultipliers: map[string]int{"storage": 2, "artifacts": 3},
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{
+			OptionalMetrics: config.OptionalMetrics{Artifacts: true},
+		},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	var artifactsScrapes int
+	var lastArtifacts *subsystemSample
+	for i := 0; i < 6; i++ {
+		ch := make(chan prometheus.Metric)
+		go func() {
+			e.Collect(ch)
+			close(ch)
+		}()
+		for range ch {
+		}
+		if sample := e.samples["artifacts"]; sample != lastArtifacts {
+			artifactsScrapes++
+			lastArtifacts = sample
+		}
+	}
+	if n := storageScrapes.Load(); n != 3 {
+		t.Errorf("Storage scraped %d times in 6 scrapes, want 3", n)
+	}
+	if artifactsScrapes != 2 {
+		t.Errorf("Artifacts scraped %d times in 6 scrapes, want 2", artifactsScrapes)
+	}
+}
@@ -27,7 +27,7 @@ func (e *Exporter) countUsersPerRealm(users []artifactory.User) realmUserCounts
 }
 
 func (e *Exporter) exportAllSecurityMetrics(ch chan<- prometheus.Metric) error {
-	for metricName, metric := range securityMetrics {
+	for metricName, metric := range e.metrics.security {
 		switch metricName {
 		case "users":
 			err := e.exportUsersCount(metricName, metric, ch)
@@ -44,6 +44,10 @@ func (e *Exporter) exportAllSecurityMetrics(ch chan<- prometheus.Metric) error {
 			if err != nil {
 				return err
 			}
+		case "permissionTargets":
+			// The permission targets are an auxiliary count, which mustn't
+			// fail the scrape.
+			e.track("permission_targets", e.exportPermissionTargets(metricName, metric, ch) == nil)
 		}
 	}
 	if err := e.exportReplications(ch); err != nil {
@@ -113,6 +117,40 @@ func (e *Exporter) exportGroups(metricName string, metric *prometheus.Desc, ch c
 	return nil
 }
 
+func (e *Exporter) exportPermissionTargets(metricName string, metric *prometheus.Desc, ch chan<- prometheus.Metric) error {
+	// Fetch Artifactory permission targets
+	permissionTargets, err := e.client.FetchPermissionTargets()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching security/permissions",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+
+	e.logger.Debug(
+		"Registering metric",
+		"metric", metricName,
+		"value", len(permissionTargets.PermissionTargets),
+	)
+	ch <- prometheus.MustNewConstMetric(metric, prometheus.GaugeValue, float64(len(permissionTargets.PermissionTargets)), permissionTargets.NodeId)
+
+	if !e.exporterRuntimeConfig.OptionalMetrics.PermissionTargetRepos {
+		return nil
+	}
+	for _, permissionTarget := range permissionTargets.PermissionTargets {
+		e.logger.Debug(
+			"Registering metric",
+			"metric", "permissionTargetRepos",
+			"name", permissionTarget.Name,
+			"value", len(permissionTarget.Repositories),
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.security["permissionTargetRepos"], prometheus.GaugeValue, float64(len(permissionTarget.Repositories)), permissionTarget.Name, permissionTargets.NodeId)
+	}
+	return nil
+}
+
 func (e *Exporter) exportCertificates(metricName string, metric *prometheus.Desc, ch chan<- prometheus.Metric) error {
 	// Fetch Artifactory certificates
 	certs, err := e.client.FetchCertificates()
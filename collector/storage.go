@@ -1,7 +1,9 @@
 package collector
 
 import (
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -10,6 +12,21 @@ import (
 
 const msgErrCalcVal = "There was an issue calculating the value"
 
+// trashcanRepoKey is the key under which storageinfo reports the trash can.
+// It's missing when the trash can is disabled.
+const trashcanRepoKey = "auto-trashcan"
+
+// cacheRepoType is the type of the repositories storageinfo reports the local
+// caches of remote repositories as. Their key is the remote repository key
+// followed by cacheRepoSuffix.
+const (
+	cacheRepoType   = "cache"
+	cacheRepoSuffix = "-cache"
+)
+
+// unknownPackageType is used for repositories which don't report a package type.
+const unknownPackageType = "unknown"
+
 func (e *Exporter) exportCount(metricName string, metric *prometheus.Desc, count string, nodeId string, ch chan<- prometheus.Metric) {
 	if count == "" {
 		e.jsonParseFailures.Inc()
@@ -84,6 +101,69 @@ func (e *Exporter) exportFilestore(metricName string, metric *prometheus.Desc, s
 	ch <- prometheus.MustNewConstMetric(metric, prometheus.GaugeValue, value, fileStoreType, fileStoreDir, nodeId)
 }
 
+// exportStorageUsedDelta exports the change of the used space in the file
+// store since the previous scrape. Nothing is exported on the first scrape,
+// nor on scrapes restricted to a single repository, which don't update the
+// previous value.
+func (e *Exporter) exportStorageUsedDelta(storageInfo artifactory.StorageInfo, ch chan<- prometheus.Metric) {
+	if e.onlyRepo != "" || storageInfo.FileStoreSummary.UsedSpace == "" {
+		return
+	}
+	used, _, err := e.convArtiToPromFileStoreData(storageInfo.FileStoreSummary.UsedSpace)
+	if err != nil {
+		e.jsonParseFailures.Inc()
+		e.logger.Warn(
+			msgErrCalcVal,
+			"metric", "usedDelta",
+			"err", err.Error(),
+		)
+		return
+	}
+	previous := e.storageUsed
+	e.storageUsed = &used
+	if previous == nil {
+		return
+	}
+	delta := used - *previous
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "usedDelta",
+		"value", delta,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.delta["usedDelta"], prometheus.GaugeValue, delta, storageInfo.NodeId)
+}
+
+// exportRepoFileCountDelta exports the change of the number of files in every
+// repository since the previous scrape. Only the repositories passing the
+// repository filter are tracked, so the remembered counts stay bounded.
+// Nothing is exported for repositories new since the previous scrape, nor on
+// scrapes restricted to a single repository, which don't update the counts.
+func (e *Exporter) exportRepoFileCountDelta(repoSummaries []repoSummary, ch chan<- prometheus.Metric) {
+	if e.onlyRepo != "" {
+		return
+	}
+	previous := e.repoFileCounts
+	e.repoFileCounts = make(map[string]float64)
+	for _, rs := range repoSummaries {
+		if !e.exporterRuntimeConfig.RepoFilter.Matches(rs.Name) {
+			continue
+		}
+		e.repoFileCounts[rs.Name] = rs.FilesCount
+		count, ok := previous[rs.Name]
+		if !ok {
+			continue
+		}
+		delta := rs.FilesCount - count
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "repoFilesDelta",
+			"repo", rs.Name,
+			"value", delta,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.delta["repoFilesDelta"], prometheus.GaugeValue, delta, rs.Name, rs.NodeId)
+	}
+}
+
 type RepoArtifactsSummary struct {
 	period          string // 30s 1m 15m 2h
 	TotalCreated    float64
@@ -156,7 +236,7 @@ func (e *Exporter) extractRepo(storageInfo artifactory.StorageInfo) ([]repoSumma
 
 func (e *Exporter) exportRepo(repoSummaries []repoSummary, ch chan<- prometheus.Metric) {
 	for _, repoSummary := range repoSummaries {
-		for metricName, metric := range storageMetrics {
+		for metricName, metric := range e.metrics.storage {
 			switch metricName {
 			case "repoUsed":
 				e.logger.Debug(
@@ -208,15 +288,211 @@ func (e *Exporter) exportRepo(repoSummaries []repoSummary, ch chan<- prometheus.
 					"value", repoSummary.Percentage,
 				)
 				ch <- prometheus.MustNewConstMetric(metric, prometheus.GaugeValue, repoSummary.Percentage, repoSummary.Name, repoSummary.Type, repoSummary.PackageType, repoSummary.NodeId)
+			case "repoAvgSize":
+				if repoSummary.FilesCount == 0 {
+					continue
+				}
+				avgSize := repoSummary.UsedSpace / repoSummary.FilesCount
+				e.logger.Debug(
+					logDbgMsgRegMetric,
+					"metric", metricName,
+					"repo", repoSummary.Name,
+					"type", repoSummary.Type,
+					"package_type", repoSummary.PackageType,
+					"value", avgSize,
+				)
+				ch <- prometheus.MustNewConstMetric(metric, prometheus.GaugeValue, avgSize, repoSummary.Name, repoSummary.Type, repoSummary.PackageType, repoSummary.NodeId)
 			}
 		}
 	}
 }
 
+// sumUsedSpaceByPackageType aggregates the used space of all repositories per package type.
+func sumUsedSpaceByPackageType(repoSummaries []repoSummary) map[string]float64 {
+	usedPerPackageType := make(map[string]float64)
+	for _, repoSummary := range repoSummaries {
+		packageType := repoSummary.PackageType
+		if packageType == "" {
+			packageType = unknownPackageType
+		}
+		usedPerPackageType[packageType] += repoSummary.UsedSpace
+	}
+	return usedPerPackageType
+}
+
+func (e *Exporter) exportPackageTypes(repoSummaries []repoSummary, nodeId string, ch chan<- prometheus.Metric) {
+	metric := e.metrics.storage["packageTypeUsed"]
+	for packageType, usedSpace := range sumUsedSpaceByPackageType(repoSummaries) {
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "packageTypeUsed",
+			"package_type", packageType,
+			"value", usedSpace,
+		)
+		ch <- prometheus.MustNewConstMetric(metric, prometheus.GaugeValue, usedSpace, packageType, nodeId)
+	}
+}
+
+// remoteRepoCaches returns the summaries of the caches of remote repositories,
+// named after the remote repository they belong to.
+func remoteRepoCaches(repoSummaries []repoSummary) []repoSummary {
+	caches := []repoSummary{}
+	for _, rs := range repoSummaries {
+		if rs.Type != cacheRepoType {
+			continue
+		}
+		rs.Name = strings.TrimSuffix(rs.Name, cacheRepoSuffix)
+		caches = append(caches, rs)
+	}
+	return caches
+}
+
+func (e *Exporter) exportRemoteRepoCaches(repoSummaries []repoSummary, ch chan<- prometheus.Metric) {
+	caches := e.filterRepoSummaries(remoteRepoCaches(repoSummaries))
+	if len(caches) == 0 {
+		e.logger.Debug("No remote repository caches found")
+		return
+	}
+	for _, cache := range caches {
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "remoteCacheUsed",
+			"repo", cache.Name,
+			"value", cache.UsedSpace,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.storage["remoteCacheUsed"], prometheus.GaugeValue, cache.UsedSpace, cache.Name, cache.PackageType, cache.NodeId)
+	}
+}
+
+// findTrashcan returns the summary of the trash can, if it's enabled.
+func findTrashcan(repoSummaries []repoSummary) (repoSummary, bool) {
+	for _, repoSummary := range repoSummaries {
+		if repoSummary.Name == trashcanRepoKey {
+			return repoSummary, true
+		}
+	}
+	return repoSummary{}, false
+}
+
+func (e *Exporter) exportTrashcan(repoSummaries []repoSummary, ch chan<- prometheus.Metric) {
+	trashcan, enabled := findTrashcan(repoSummaries)
+	if !enabled {
+		e.logger.Debug("Trash can is disabled")
+		return
+	}
+	for metricName, metric := range e.metrics.trashcan {
+		var value float64
+		switch metricName {
+		case "usedSpace":
+			value = trashcan.UsedSpace
+		case "items":
+			value = trashcan.ItemsCount
+		}
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", metricName,
+			"value", value,
+		)
+		ch <- prometheus.MustNewConstMetric(metric, prometheus.GaugeValue, value, trashcan.NodeId)
+	}
+}
+
+func (e *Exporter) exportStorageQuota(storageInfo artifactory.StorageInfo, ch chan<- prometheus.Metric) {
+	storageQuota, err := e.client.FetchStorageQuota()
+	if errors.Is(err, artifactory.ErrAdminRequired) {
+		e.logger.Debug(
+			"Reading the storage quota requires admin permissions, skipping it",
+			"err", err.Error(),
+		)
+		return
+	}
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching storage/quota",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return
+	}
+	if !storageQuota.Enabled {
+		e.logger.Debug("No storage quota configured")
+		return
+	}
+	e.exportStorageQuotaThresholds(storageQuota, ch)
+	totalSpace, _, err := e.convArtiToPromFileStoreData(storageInfo.FileStoreSummary.TotalSpace)
+	if err != nil {
+		e.jsonParseFailures.Inc()
+		e.logger.Warn(
+			msgErrCalcVal,
+			"metric", "quotaLimit",
+			"err", err.Error(),
+		)
+		return
+	}
+	usedSpace, _, err := e.convArtiToPromFileStoreData(storageInfo.FileStoreSummary.UsedSpace)
+	if err != nil {
+		e.jsonParseFailures.Inc()
+		e.logger.Warn(
+			msgErrCalcVal,
+			"metric", "quotaUsedRatio",
+			"err", err.Error(),
+		)
+		return
+	}
+	limit, ok := storageQuota.LimitBytes(totalSpace)
+	if !ok || limit == 0 {
+		e.logger.Debug("Storage quota is unlimited")
+		return
+	}
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "quotaLimit",
+		"value", limit,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.storage["quotaLimit"], prometheus.GaugeValue, limit, storageQuota.NodeId)
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "quotaUsedRatio",
+		"value", usedSpace/limit,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.storage["quotaUsedRatio"], prometheus.GaugeValue, usedSpace/limit, storageQuota.NodeId)
+}
+
+// exportStorageQuotaThresholds exports the warning and limit thresholds of
+// the storage quota as configured in Artifactory.
+func (e *Exporter) exportStorageQuotaThresholds(storageQuota artifactory.StorageQuota, ch chan<- prometheus.Metric) {
+	for metricName, value := range map[string]int{
+		"quotaWarnPct":  storageQuota.DiskSpaceWarningPercentage,
+		"quotaLimitPct": storageQuota.DiskSpaceLimitPercentage,
+	} {
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", metricName,
+			"value", value,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.storage[metricName], prometheus.GaugeValue, float64(value), storageQuota.NodeId)
+	}
+}
+
+// exportStorageInfoAge exports the time since the storage summary was
+// calculated. Nothing is exported if Artifactory doesn't report it.
+func (e *Exporter) exportStorageInfoAge(storageInfo artifactory.StorageInfo, ch chan<- prometheus.Metric) {
+	if storageInfo.LastUpdate <= 0 {
+		return
+	}
+	age := max(time.Since(time.UnixMilli(storageInfo.LastUpdate)).Seconds(), 0)
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "infoAge",
+		"value", age,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.storage["infoAge"], prometheus.GaugeValue, age, storageInfo.NodeId)
+}
+
 func (e *Exporter) exportStorage(storageInfo artifactory.StorageInfo, ch chan<- prometheus.Metric) {
 	fileStoreType := strings.ToLower(storageInfo.FileStoreSummary.StorageType)
 	fileStoreDir := storageInfo.FileStoreSummary.StorageDirectory
-	for metricName, metric := range storageMetrics {
+	for metricName, metric := range e.metrics.storage {
 		switch metricName {
 		case "artifacts":
 			e.exportCount(metricName, metric, storageInfo.BinariesSummary.ArtifactsCount, storageInfo.NodeId, ch)
@@ -234,6 +510,8 @@ func (e *Exporter) exportStorage(storageInfo artifactory.StorageInfo, ch chan<-
 			e.exportFilestore(metricName, metric, storageInfo.FileStoreSummary.FreeSpace, fileStoreType, fileStoreDir, storageInfo.NodeId, ch)
 		case "items":
 			e.exportCount(metricName, metric, storageInfo.BinariesSummary.ItemsCount, storageInfo.NodeId, ch)
+		case "infoAge":
+			e.exportStorageInfoAge(storageInfo, ch)
 		}
 	}
 }
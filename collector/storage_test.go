@@ -0,0 +1,409 @@
+package collector
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestSumUsedSpaceByPackageType(t *testing.T) {
+	fixture := `{
+		"repositoriesSummaryList": [
+			{"repoKey": "docker-local", "repoType": "LOCAL", "usedSpace": "1.5 GB", "packageType": "Docker", "percentage": "N/A"},
+			{"repoKey": "docker-remote", "repoType": "CACHE", "usedSpace": "512 MB", "packageType": "Docker", "percentage": "N/A"},
+			{"repoKey": "no-type", "repoType": "LOCAL", "usedSpace": "100 bytes", "packageType": "", "percentage": "N/A"},
+			{"repoKey": "TOTAL", "repoType": "NA", "usedSpace": "2 GB", "percentage": "N/A"}
+		]
+	}`
+	var storageInfo artifactory.StorageInfo
+	if err := json.Unmarshal([]byte(fixture), &storageInfo); err != nil {
+		t.Fatalf("Unmarshal fixture error = %v", err)
+	}
+
+	repoSummaries, err := testExporter.extractRepo(storageInfo)
+	if err != nil {
+		t.Fatalf("extractRepo() error = %v", err)
+	}
+
+	expected := map[string]float64{
+		"docker":           2 * 1024 * 1024 * 1024,
+		unknownPackageType: 100,
+	}
+	actual := sumUsedSpaceByPackageType(repoSummaries)
+	if len(actual) != len(expected) {
+		t.Fatalf("sumUsedSpaceByPackageType() = %v, want %v", actual, expected)
+	}
+	for packageType, usedSpace := range expected {
+		if !almostEqual(actual[packageType], usedSpace) {
+			t.Errorf("sumUsedSpaceByPackageType()[%s] = %v, want %v", packageType, actual[packageType], usedSpace)
+		}
+	}
+}
+
+func TestFindTrashcan(t *testing.T) {
+	tests := []struct {
+		name          string
+		fixture       string
+		expectEnabled bool
+		expectedUsed  float64
+		expectedItems float64
+	}{
+		{
+			name: "Trash can enabled",
+			fixture: `{"repositoriesSummaryList": [
+				{"repoKey": "libs-release", "repoType": "LOCAL", "usedSpace": "1 GB", "itemsCount": 10, "packageType": "Maven", "percentage": "N/A"},
+				{"repoKey": "auto-trashcan", "repoType": "NA", "usedSpace": "2.5 MB", "itemsCount": 42, "percentage": "N/A"}
+			]}`,
+			expectEnabled: true,
+			expectedUsed:  2.5 * 1024 * 1024,
+			expectedItems: 42,
+		},
+		{
+			name: "Trash can disabled",
+			fixture: `{"repositoriesSummaryList": [
+				{"repoKey": "libs-release", "repoType": "LOCAL", "usedSpace": "1 GB", "itemsCount": 10, "packageType": "Maven", "percentage": "N/A"}
+			]}`,
+			expectEnabled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var storageInfo artifactory.StorageInfo
+			if err := json.Unmarshal([]byte(tt.fixture), &storageInfo); err != nil {
+				t.Fatalf("Unmarshal fixture error = %v", err)
+			}
+			repoSummaries, err := testExporter.extractRepo(storageInfo)
+			if err != nil {
+				t.Fatalf("extractRepo() error = %v", err)
+			}
+
+			trashcan, enabled := findTrashcan(repoSummaries)
+			if enabled != tt.expectEnabled {
+				t.Fatalf("findTrashcan() enabled = %v, want %v", enabled, tt.expectEnabled)
+			}
+			if !almostEqual(trashcan.UsedSpace, tt.expectedUsed) {
+				t.Errorf("Trash can UsedSpace = %v, want %v", trashcan.UsedSpace, tt.expectedUsed)
+			}
+			if trashcan.ItemsCount != tt.expectedItems {
+				t.Errorf("Trash can ItemsCount = %v, want %v", trashcan.ItemsCount, tt.expectedItems)
+			}
+		})
+	}
+}
+
+func TestRemoteRepoCaches(t *testing.T) {
+	fixture := `{
+		"repositoriesSummaryList": [
+			{"repoKey": "maven-remote-cache", "repoType": "CACHE", "usedSpace": "3 MB", "packageType": "Maven", "percentage": "N/A"},
+			{"repoKey": "docker-hub-cache", "repoType": "CACHE", "usedSpace": "1 GB", "packageType": "Docker", "percentage": "N/A"},
+			{"repoKey": "libs-local", "repoType": "LOCAL", "usedSpace": "1 GB", "packageType": "Maven", "percentage": "N/A"},
+			{"repoKey": "TOTAL", "repoType": "NA", "usedSpace": "2 GB", "percentage": "N/A"}
+		]
+	}`
+	var storageInfo artifactory.StorageInfo
+	if err := json.Unmarshal([]byte(fixture), &storageInfo); err != nil {
+		t.Fatalf("Unmarshal fixture error = %v", err)
+	}
+
+	repoSummaries, err := testExporter.extractRepo(storageInfo)
+	if err != nil {
+		t.Fatalf("extractRepo() error = %v", err)
+	}
+
+	caches := remoteRepoCaches(repoSummaries)
+	if len(caches) != 2 {
+		t.Fatalf("remoteRepoCaches() returned %d caches, want 2", len(caches))
+	}
+	expected := map[string]float64{
+		"maven-remote": 3 * 1024 * 1024,
+		"docker-hub":   1024 * 1024 * 1024,
+	}
+	for _, cache := range caches {
+		want, ok := expected[cache.Name]
+		if !ok {
+			t.Errorf("Unexpected cache of remote repository %q", cache.Name)
+			continue
+		}
+		if !almostEqual(cache.UsedSpace, want) {
+			t.Errorf("Cache of %s UsedSpace = %v, want %v", cache.Name, cache.UsedSpace, want)
+		}
+	}
+
+	if caches := remoteRepoCaches(repoSummaries[2:]); len(caches) != 0 {
+		t.Errorf("remoteRepoCaches() without caches returned %d caches, want 0", len(caches))
+	}
+}
+
+func TestExportRepoAvgArtifactSize(t *testing.T) {
+	repoSummaries := []repoSummary{
+		{Name: "libs-release", Type: "local", PackageType: "maven", FilesCount: 4, UsedSpace: 1024},
+		{Name: "empty-local", Type: "local", PackageType: "generic", FilesCount: 0, UsedSpace: 0},
+	}
+
+	ch := make(chan prometheus.Metric, len(repoSummaries)*len(testExporter.metrics.storage))
+	testExporter.exportRepo(repoSummaries, ch)
+	close(ch)
+	actual := make(map[string]float64)
+	for metric := range ch {
+		if metric.Desc() != testExporter.metrics.storage["repoAvgSize"] {
+			continue
+		}
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() == "name" {
+				actual[label.GetValue()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	expected := map[string]float64{"libs-release": 256}
+	if len(actual) != len(expected) {
+		t.Fatalf("Average artifact sizes = %v, want %v", actual, expected)
+	}
+	for name, value := range expected {
+		if actual[name] != value {
+			t.Errorf("Average artifact size of %s = %v, want %v", name, actual[name], value)
+		}
+	}
+}
+
+func TestExportStorageQuotaThresholds(t *testing.T) {
+	storageInfoFixture := `{"fileStoreSummary":{"storageType":"file-system","totalSpace":"1000 bytes","usedSpace":"400 bytes (40%)","freeSpace":"600 bytes (60%)"}}`
+
+	tests := []struct {
+		name     string
+		status   int
+		fixture  string
+		expected map[string]float64
+	}{
+		{
+			name:     "Quota control enabled",
+			fixture:  `{"enabled":true,"diskSpaceLimitPercentage":95,"diskSpaceWarningPercentage":85}`,
+			expected: map[string]float64{"quotaWarnPct": 85, "quotaLimitPct": 95},
+		},
+		{
+			name:     "Quota control disabled",
+			fixture:  `{"enabled":false,"diskSpaceLimitPercentage":95,"diskSpaceWarningPercentage":85}`,
+			expected: map[string]float64{},
+		},
+		{
+			name:     "Admin permissions missing",
+			status:   http.StatusForbidden,
+			fixture:  `{"errors":[{"status":403,"message":"Forbidden"}]}`,
+			expected: map[string]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				w.Write([]byte(tt.fixture))
+			}))
+			defer server.Close()
+
+			e, err := NewExporter(&config.Config{
+				ArtiScrapeURI:         server.URL + "/artifactory",
+				ArtiTimeout:           5 * time.Second,
+				MetricsNamespace:      defaultNamespace,
+				ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+				Credentials: &config.Credentials{
+					AuthMethod: "userPass",
+					Username:   "test",
+					Password:   "test",
+				},
+				Logger: newTestLogger(),
+			})
+			if err != nil {
+				t.Fatalf("NewExporter() error = %v", err)
+			}
+			var storageInfo artifactory.StorageInfo
+			if err := json.Unmarshal([]byte(storageInfoFixture), &storageInfo); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			ch := make(chan prometheus.Metric, len(e.metrics.storage))
+			e.exportStorageQuota(storageInfo, ch)
+			close(ch)
+			actual := make(map[string]float64)
+			for metric := range ch {
+				var m dto.Metric
+				if err := metric.Write(&m); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+				for _, name := range []string{"quotaWarnPct", "quotaLimitPct"} {
+					if metric.Desc() == e.metrics.storage[name] {
+						actual[name] = m.GetGauge().GetValue()
+					}
+				}
+			}
+			if errs := testutil.ToFloat64(e.totalAPIErrors); errs != 0 {
+				t.Errorf("exportStorageQuota() API errors = %v, want 0", errs)
+			}
+			if len(actual) != len(tt.expected) {
+				t.Fatalf("exportStorageQuota() thresholds = %v, want %v", actual, tt.expected)
+			}
+			for name, expected := range tt.expected {
+				if actual[name] != expected {
+					t.Errorf("Metric %s = %v, want %v", name, actual[name], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestExportStorageInfoAge(t *testing.T) {
+	tests := []struct {
+		name       string
+		lastUpdate int64
+		expectAge  bool
+	}{
+		{
+			name:       "Last update reported",
+			lastUpdate: time.Now().Add(-10 * time.Minute).UnixMilli(),
+			expectAge:  true,
+		},
+		{
+			name:       "Last update not reported",
+			lastUpdate: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := make(chan prometheus.Metric, 1)
+			testExporter.exportStorageInfoAge(artifactory.StorageInfo{LastUpdate: tt.lastUpdate}, ch)
+			close(ch)
+			var metrics []prometheus.Metric
+			for metric := range ch {
+				metrics = append(metrics, metric)
+			}
+			if !tt.expectAge {
+				if len(metrics) != 0 {
+					t.Errorf("exportStorageInfoAge() exported %d metrics, want none", len(metrics))
+				}
+				return
+			}
+			if len(metrics) != 1 {
+				t.Fatalf("exportStorageInfoAge() exported %d metrics, want 1", len(metrics))
+			}
+			var m dto.Metric
+			if err := metrics[0].Write(&m); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if age := m.GetGauge().GetValue(); age < 600 || age > 660 {
+				t.Errorf("Storage info age = %v, want about 600", age)
+			}
+		})
+	}
+}
+
+func TestExportStorageUsedDelta(t *testing.T) {
+	e := &Exporter{metrics: newMetricDescriptors(defaultNamespace), logger: newTestLogger()}
+	scrapes := []struct {
+		usedSpace   string
+		onlyRepo    string
+		expectDelta bool
+		delta       float64
+	}{
+		{usedSpace: "1 GB (10%)"},
+		{usedSpace: "1.5 GB (15%)", expectDelta: true, delta: 512 * 1024 * 1024},
+		{usedSpace: "2 GB (20%)", onlyRepo: "libs-release"},
+		{usedSpace: "1 GB (10%)", expectDelta: true, delta: -512 * 1024 * 1024},
+		{usedSpace: "1 GB (10%)", expectDelta: true, delta: 0},
+	}
+
+	for i, scrape := range scrapes {
+		var storageInfo artifactory.StorageInfo
+		storageInfo.FileStoreSummary.UsedSpace = scrape.usedSpace
+		e.onlyRepo = scrape.onlyRepo
+		ch := make(chan prometheus.Metric, 1)
+		e.exportStorageUsedDelta(storageInfo, ch)
+		close(ch)
+		var metrics []prometheus.Metric
+		for metric := range ch {
+			metrics = append(metrics, metric)
+		}
+		if !scrape.expectDelta {
+			if len(metrics) != 0 {
+				t.Errorf("Scrape %d: exportStorageUsedDelta() exported %d metrics, want none", i, len(metrics))
+			}
+			continue
+		}
+		if len(metrics) != 1 {
+			t.Fatalf("Scrape %d: exportStorageUsedDelta() exported %d metrics, want 1", i, len(metrics))
+		}
+		var m dto.Metric
+		if err := metrics[0].Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if delta := m.GetGauge().GetValue(); delta != scrape.delta {
+			t.Errorf("Scrape %d: storage used delta = %v, want %v", i, delta, scrape.delta)
+		}
+	}
+}
+
+func TestExportRepoFileCountDelta(t *testing.T) {
+	e := &Exporter{metrics: newMetricDescriptors(defaultNamespace), logger: newTestLogger()}
+	e.exporterRuntimeConfig.RepoFilter.Denylist = regexp.MustCompile("^tmp-.*")
+	scrapes := []struct {
+		files    map[string]float64
+		onlyRepo string
+		expected map[string]float64
+	}{
+		{files: map[string]float64{"libs-release": 10, "tmp-builds": 5}, expected: map[string]float64{}},
+		{files: map[string]float64{"libs-release": 15, "tmp-builds": 50, "libs-snapshot": 3}, expected: map[string]float64{"libs-release": 5}},
+		{files: map[string]float64{"libs-release": 100, "libs-snapshot": 100}, onlyRepo: "libs-release", expected: map[string]float64{}},
+		{files: map[string]float64{"libs-release": 12, "libs-snapshot": 3}, expected: map[string]float64{"libs-release": -3, "libs-snapshot": 0}},
+	}
+
+	for i, scrape := range scrapes {
+		var repoSummaries []repoSummary
+		for name, files := range scrape.files {
+			repoSummaries = append(repoSummaries, repoSummary{Name: name, FilesCount: files})
+		}
+		e.onlyRepo = scrape.onlyRepo
+		ch := make(chan prometheus.Metric, len(repoSummaries))
+		e.exportRepoFileCountDelta(repoSummaries, ch)
+		close(ch)
+		actual := make(map[string]float64)
+		for metric := range ch {
+			var m dto.Metric
+			if err := metric.Write(&m); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "repo" {
+					actual[label.GetValue()] = m.GetGauge().GetValue()
+				}
+			}
+		}
+		if len(actual) != len(scrape.expected) {
+			t.Errorf("Scrape %d: file count deltas = %v, want %v", i, actual, scrape.expected)
+			continue
+		}
+		for repo, delta := range scrape.expected {
+			if actual[repo] != delta {
+				t.Errorf("Scrape %d: file count delta of %s = %v, want %v", i, repo, actual[repo], delta)
+			}
+		}
+	}
+	if _, ok := e.repoFileCounts["tmp-builds"]; ok {
+		t.Error("File count of a repository excluded by the repository filter is tracked")
+	}
+}
@@ -1,9 +1,14 @@
 package collector
 
 import (
+	"errors"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
 )
 
 func (e *Exporter) exportSystem(ch chan<- prometheus.Metric) error {
@@ -42,7 +47,7 @@ func (e *Exporter) exportSystem(ch chan<- prometheus.Metric) error {
 		) // To preserve the operation, we do nothing but log the event,
 	}
 
-	for metricName, metric := range systemMetrics {
+	for metricName, metric := range e.metrics.system {
 		switch metricName {
 		case "healthy":
 			ch <- prometheus.MustNewConstMetric(
@@ -60,6 +65,18 @@ func (e *Exporter) exportSystem(ch chan<- prometheus.Metric) error {
 				buildInfo.Revision,
 				buildInfo.NodeId,
 			)
+		case "uptime":
+			uptime, ok := buildInfo.UptimeSeconds(time.Now())
+			if !ok {
+				e.logger.Debug("Artifactory uptime is not available")
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(
+				metric,
+				prometheus.GaugeValue,
+				uptime,
+				buildInfo.NodeId,
+			)
 		case "license":
 			ch <- prometheus.MustNewConstMetric(
 				metric,
@@ -101,7 +118,7 @@ func (e *Exporter) exportSystemHALicenses(ch chan<- prometheus.Metric) error {
 				"err", err.Error(),
 			) // To preserve the operation, we do nothing but log the event,
 		}
-		metric := systemMetrics["licenses"]
+		metric := e.metrics.system["licenses"]
 		ch <- prometheus.MustNewConstMetric(
 			metric,
 			prometheus.GaugeValue,
@@ -118,3 +135,93 @@ func (e *Exporter) exportSystemHALicenses(ch chan<- prometheus.Metric) error {
 
 	return nil
 }
+
+func (e *Exporter) exportHANodes(ch chan<- prometheus.Metric) bool {
+	haNodes, err := e.client.FetchHANodes()
+	if errors.Is(err, artifactory.ErrAdminRequired) {
+		e.logger.Debug(
+			"Reading the HA topology requires admin permissions, skipping it",
+			"err", err.Error(),
+		)
+		return true
+	}
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching topology/health",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	if !haNodes.IsHA() {
+		e.logger.Debug("No HA cluster found")
+		return true
+	}
+
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "nodes",
+		"value", len(haNodes.Nodes),
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.ha["nodes"], prometheus.GaugeValue, float64(len(haNodes.Nodes)), haNodes.NodeId)
+	for _, node := range haNodes.Nodes {
+		up := convArtiToPromBool(node.IsHealthy())
+		state := strings.ToLower(node.State)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "nodeUp",
+			"ha_node_id", node.Id,
+			"state", state,
+			"value", up,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.ha["nodeUp"], prometheus.GaugeValue, up, node.Id, state, haNodes.NodeId)
+	}
+	return true
+}
+
+func (e *Exporter) exportServices(ch chan<- prometheus.Metric) bool {
+	routerHealth, err := e.client.FetchRouterHealth()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching router/api/v1/system/health",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+	if len(routerHealth.Services) == 0 {
+		e.logger.Debug("No JFrog Platform router found")
+		return true
+	}
+
+	for _, service := range routerHealth.Services {
+		up := convArtiToPromBool(service.IsHealthy())
+		state := strings.ToLower(service.State)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "serviceUp",
+			"service_id", service.ServiceId,
+			"ha_node_id", service.NodeId,
+			"state", state,
+			"value", up,
+		)
+		ch <- prometheus.MustNewConstMetric(e.metrics.service["up"], prometheus.GaugeValue, up, service.ServiceId, service.NodeId, state, routerHealth.NodeId)
+	}
+	return true
+}
+
+// exportCertExpiry exports the expiry time of the certificate presented by
+// Artifactory during the requests of this scrape.
+func (e *Exporter) exportCertExpiry(ch chan<- prometheus.Metric) {
+	expiry, ok := e.client.CertExpiry()
+	if !ok {
+		return
+	}
+	value := float64(expiry.Unix())
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "certExpiry",
+		"value", value,
+	)
+	ch <- prometheus.MustNewConstMetric(e.metrics.ssl["certExpiry"], prometheus.GaugeValue, value)
+}
@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestCollectBackgroundTasksOldestRunning(t *testing.T) {
+	now := time.Now().UTC()
+	started := func(ago time.Duration) string {
+		return now.Add(-ago).Format("2006-01-02T15:04:05.000Z")
+	}
+	tests := []struct {
+		name     string
+		tasks    string
+		expected float64
+	}{
+		{
+			name: "Running tasks",
+			tasks: `{"tasks": [
+				{"type": "org.jfrog.GcJob", "state": "running", "started": "` + started(2*time.Hour) + `"},
+				{"type": "org.jfrog.IndexJob", "state": "running", "started": "` + started(10*time.Minute) + `"},
+				{"type": "org.jfrog.CleanupJob", "state": "scheduled", "started": "` + started(5*time.Hour) + `"}
+			]}`,
+			expected: (2 * time.Hour).Seconds(),
+		},
+		{
+			name:     "Start time ahead of the clock",
+			tasks:    `{"tasks": [{"type": "org.jfrog.GcJob", "state": "running", "started": "` + started(-time.Hour) + `"}]}`,
+			expected: 0,
+		},
+		{
+			name:     "No running tasks",
+			tasks:    `{"tasks": [{"type": "org.jfrog.GcJob", "state": "scheduled"}]}`,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.tasks))
+			}))
+			defer server.Close()
+
+			e, err := NewExporter(&config.Config{
+				ArtiScrapeURI:         server.URL,
+				ArtiTimeout:           5 * time.Second,
+				MetricsNamespace:      defaultNamespace,
+				ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+				Credentials: &config.Credentials{
+					AuthMethod: "userPass",
+					Username:   "test",
+					Password:   "test",
+				},
+				Logger: newTestLogger(),
+			})
+			if err != nil {
+				t.Fatalf("NewExporter() error = %v", err)
+			}
+
+			ch := make(chan prometheus.Metric, 10)
+			if !e.collectBackgroundTasks(ch) {
+				t.Fatal("collectBackgroundTasks() = false, want true")
+			}
+			// Allow a few seconds of scrape time on top of the expected age.
+			if actual := testutil.ToFloat64(e.backgroundTaskOldestRunningSeconds); actual < tt.expected || actual > tt.expected+5 {
+				t.Errorf("Oldest running task = %v seconds, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCollectConversionTasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tasks": [
+			{"type": "org.jfrog.DbConversionJob", "state": "running"},
+			{"type": "org.jfrog.Sha256MigrationJob", "state": "scheduled"},
+			{"type": "org.jfrog.GcJob", "state": "running"}
+		]}`))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL,
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 10)
+	if !e.collectConversionTasks(ch) {
+		t.Fatal("collectConversionTasks() = false, want true")
+	}
+	close(ch)
+	values := make(map[*prometheus.Desc]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		values[metric.Desc()] = m.GetGauge().GetValue()
+	}
+	if len(values) != 2 {
+		t.Fatalf("Exported %d metrics, want only the 2 conversion metrics", len(values))
+	}
+	if actual := values[e.metrics.conversion["inProgress"]]; actual != 1 {
+		t.Errorf("conversion_in_progress = %v, want 1", actual)
+	}
+	if actual := values[e.metrics.conversion["pendingTasks"]]; actual != 2 {
+		t.Errorf("conversion_pending_tasks = %v, want 2", actual)
+	}
+}
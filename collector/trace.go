@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// httpTraceBuckets range from 1ms to about 4s.
+var httpTraceBuckets = prometheus.ExponentialBuckets(0.001, 2, 13)
+
+// httpTraceMetrics holds a histogram of the durations of every traced phase
+// of the requests to Artifactory.
+type httpTraceMetrics map[artifactory.TracePhase]prometheus.Histogram
+
+func newHTTPTraceMetrics(namespace string) httpTraceMetrics {
+	newHistogram := func(name string, help string) prometheus.Histogram {
+		return prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "exporter",
+			Name:      name,
+			Help:      help,
+			Buckets:   httpTraceBuckets,
+		})
+	}
+	return httpTraceMetrics{
+		artifactory.TraceDNS:     newHistogram("http_dns_seconds", "Time to resolve the Artifactory host name in seconds."),
+		artifactory.TraceConnect: newHistogram("http_connect_seconds", "Time to establish a TCP connection to Artifactory in seconds."),
+		artifactory.TraceTLS:     newHistogram("http_tls_seconds", "Time of the TLS handshake with Artifactory in seconds."),
+	}
+}
+
+// newRequestDuration returns the histogram of the durations of the requests
+// to Artifactory per endpoint.
+func newRequestDuration(namespace string) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "exporter",
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of the requests to an Artifactory endpoint in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint"})
+}
+
+// observe is the trace hook recording the duration of a phase.
+func (m httpTraceMetrics) observe(phase artifactory.TracePhase, duration time.Duration) {
+	if histogram, ok := m[phase]; ok {
+		histogram.Observe(duration.Seconds())
+	}
+}
@@ -0,0 +1,97 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestRequestDuration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	calls := 3
+	for i := 0; i < calls; i++ {
+		if _, err := e.client.FetchHTTP("system/ping"); err != nil {
+			t.Fatalf("FetchHTTP() error = %v", err)
+		}
+	}
+
+	var m dto.Metric
+	if err := e.requestDuration.WithLabelValues("system/ping").(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if count := m.GetHistogram().GetSampleCount(); count != uint64(calls) {
+		t.Errorf("Observed %d requests to system/ping, want %d", count, calls)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version": `))
+	}))
+	defer server.Close()
+
+	e, err := NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      defaultNamespace,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := e.client.FetchBuildInfo(); err == nil {
+			t.Fatal("FetchBuildInfo(): expected error but got none")
+		}
+	}
+	if _, err := e.client.FetchUnavailableMirrors(); err == nil {
+		t.Fatal("FetchUnavailableMirrors(): expected error but got none")
+	}
+
+	expected := map[string]float64{
+		"system/version":                       2,
+		"federation/status/unavailableMirrors": 1,
+	}
+	for endpoint, count := range expected {
+		var m dto.Metric
+		if err := e.unmarshalErrors.WithLabelValues(endpoint).Write(&m); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if actual := m.GetCounter().GetValue(); actual != count {
+			t.Errorf("Unmarshal errors of %s = %v, want %v", endpoint, actual, count)
+		}
+	}
+}
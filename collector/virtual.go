@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// exportVirtualRepos exports the number of repositories each virtual
+// repository includes.
+func (e *Exporter) exportVirtualRepos(ch chan<- prometheus.Metric) bool {
+	virtualRepos, err := e.client.FetchVirtualRepositories()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching virtual repositories",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return false
+	}
+
+	members := make(map[string][]string, len(virtualRepos.Repositories))
+	for _, virtualRepo := range virtualRepos.Repositories {
+		members[virtualRepo.Key] = virtualRepo.Repositories
+	}
+
+	// Virtual repositories aggregated into "other" may share labels.
+	merged := newMaxMetrics()
+	for _, virtualRepo := range virtualRepos.Repositories {
+		repo, ok := e.repoLabel(virtualRepo.Key)
+		if !ok {
+			continue
+		}
+		count := len(virtualRepoMembers(virtualRepo, members))
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "members",
+			"repo", repo,
+			"value", count,
+		)
+		merged.add(e.metrics.virtual["members"], float64(count), repo, virtualRepos.NodeId)
+	}
+	merged.export(ch)
+	return true
+}
+
+// virtualRepoMembers returns the distinct non-virtual repositories included by
+// a virtual repository, resolving the virtual repositories it includes through
+// members. Every virtual repository is resolved at most once, so cycles
+// between virtual repositories terminate.
+func virtualRepoMembers(virtualRepo artifactory.VirtualRepository, members map[string][]string) map[string]struct{} {
+	resolved := make(map[string]struct{})
+	visited := map[string]struct{}{virtualRepo.Key: {}}
+	pending := append([]string(nil), virtualRepo.Repositories...)
+	for len(pending) > 0 {
+		key := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		nested, isVirtual := members[key]
+		if !isVirtual {
+			resolved[key] = struct{}{}
+			continue
+		}
+		if _, ok := visited[key]; ok {
+			continue
+		}
+		visited[key] = struct{}{}
+		pending = append(pending, nested...)
+	}
+	return resolved
+}
@@ -0,0 +1,35 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+func TestVirtualRepoMembers(t *testing.T) {
+	repos := []artifactory.VirtualRepository{
+		{Key: "maven-virtual", Repositories: []string{"libs-release", "libs-snapshot", "maven-remote"}},
+		{Key: "all-virtual", Repositories: []string{"maven-virtual", "npm-virtual", "libs-release", "generic-local"}},
+		{Key: "npm-virtual", Repositories: []string{"npm-local", "npm-remote", "all-virtual"}},
+		{Key: "self-virtual", Repositories: []string{"self-virtual", "generic-local"}},
+		{Key: "empty-virtual"},
+	}
+	members := make(map[string][]string, len(repos))
+	for _, repo := range repos {
+		members[repo.Key] = repo.Repositories
+	}
+
+	expected := map[string]int{
+		"maven-virtual": 3,
+		// Includes npm-virtual, which includes all-virtual again.
+		"all-virtual":   6,
+		"npm-virtual":   6,
+		"self-virtual":  1,
+		"empty-virtual": 0,
+	}
+	for _, repo := range repos {
+		if actual := len(virtualRepoMembers(repo, members)); actual != expected[repo.Key] {
+			t.Errorf("Members of %s = %d, want %d", repo.Key, actual, expected[repo.Key])
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// reHeaderName matches valid HTTP header names.
+var reHeaderName = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+
+// authMethodHeaders are the headers authenticating the requests of each auth method.
+var authMethodHeaders = map[string]string{
+	"userPass":    "Authorization",
+	"accessToken": "Authorization",
+	"apiKey":      "X-JFrog-Art-Api",
+}
+
+// CustomAuthHeader is a header carrying the credentials to Artifactory, for
+// gateways which strip the Authorization header or expect another one.
+type CustomAuthHeader struct {
+	Name  string
+	Value string
+	// Replace sends the header instead of the header of the auth method.
+	Replace bool
+}
+
+// authHeaderData returns the credentials of the auth method available to the
+// value template of a custom auth header.
+func authHeaderData(credentials Credentials) map[string]string {
+	switch credentials.AuthMethod {
+	case "userPass":
+		return map[string]string{
+			"Username": credentials.Username,
+			"Password": credentials.Password,
+			"Basic":    base64.StdEncoding.EncodeToString([]byte(credentials.Username + ":" + credentials.Password)),
+		}
+	case "accessToken":
+		return map[string]string{"AccessToken": credentials.AccessToken}
+	case "apiKey":
+		return map[string]string{"APIKey": credentials.APIKey}
+	}
+	return nil
+}
+
+// newCustomAuthHeader parses a custom auth header given as name=template and
+// renders its value with the credentials. The template may only reference the
+// credentials of the auth method, e.g. {{.AccessToken}}, and the header must
+// not be the header of the auth method unless it replaces it. It returns nil
+// if value is empty.
+func newCustomAuthHeader(value string, replace bool, credentials Credentials) (*CustomAuthHeader, error) {
+	if value == "" {
+		if replace {
+			return nil, fmt.Errorf("`artifactory.auth-header.replace` requires `artifactory.auth-header` to be set")
+		}
+		return nil, nil
+	}
+	name, tmpl, ok := strings.Cut(value, "=")
+	if !ok || tmpl == "" {
+		return nil, fmt.Errorf("invalid `artifactory.auth-header` %q: expected name=template", value)
+	}
+	if !reHeaderName.MatchString(name) {
+		return nil, fmt.Errorf("invalid `artifactory.auth-header` name %q", name)
+	}
+	if !replace && http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(authMethodHeaders[credentials.AuthMethod]) {
+		return nil, fmt.Errorf("`artifactory.auth-header` %s conflicts with the header of auth method %s, set `artifactory.auth-header.replace` to replace it", name, credentials.AuthMethod)
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid `artifactory.auth-header` template: %w", err)
+	}
+	var rendered strings.Builder
+	if err := t.Execute(&rendered, authHeaderData(credentials)); err != nil {
+		return nil, fmt.Errorf("`artifactory.auth-header` template doesn't match auth method %s: %w", credentials.AuthMethod, err)
+	}
+	return &CustomAuthHeader{
+		Name:    name,
+		Value:   rendered.String(),
+		Replace: replace,
+	}, nil
+}
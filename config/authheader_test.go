@@ -0,0 +1,115 @@
+package config
+
+import "testing"
+
+func TestNewCustomAuthHeader(t *testing.T) {
+	userPass := Credentials{AuthMethod: "userPass", Username: "user", Password: "pass"}
+	accessToken := Credentials{AuthMethod: "accessToken", AccessToken: "token"}
+	apiKey := Credentials{AuthMethod: "apiKey", APIKey: "key"}
+
+	tests := []struct {
+		name        string
+		value       string
+		replace     bool
+		credentials Credentials
+		expected    *CustomAuthHeader
+		expectError bool
+	}{
+		{
+			name:        "Unset",
+			credentials: accessToken,
+		},
+		{
+			name:        "Replace without header",
+			replace:     true,
+			credentials: accessToken,
+			expectError: true,
+		},
+		{
+			name:        "Access token",
+			value:       "X-Auth-Token={{.AccessToken}}",
+			credentials: accessToken,
+			expected:    &CustomAuthHeader{Name: "X-Auth-Token", Value: "token"},
+		},
+		{
+			name:        "Basic auth replacing the Authorization header",
+			value:       "Proxy-Authorization=Basic {{.Basic}}",
+			replace:     true,
+			credentials: userPass,
+			expected:    &CustomAuthHeader{Name: "Proxy-Authorization", Value: "Basic dXNlcjpwYXNz", Replace: true},
+		},
+		{
+			name:        "Username",
+			value:       "X-User={{.Username}}",
+			credentials: userPass,
+			expected:    &CustomAuthHeader{Name: "X-User", Value: "user"},
+		},
+		{
+			name:        "Authorization header of the auth method",
+			value:       "authorization=Token {{.AccessToken}}",
+			credentials: accessToken,
+			expectError: true,
+		},
+		{
+			name:        "Authorization header replacing the auth method",
+			value:       "Authorization=Token {{.AccessToken}}",
+			replace:     true,
+			credentials: accessToken,
+			expected:    &CustomAuthHeader{Name: "Authorization", Value: "Token token", Replace: true},
+		},
+		{
+			name:        "API key header of the auth method",
+			value:       "X-JFrog-Art-Api={{.APIKey}}",
+			credentials: apiKey,
+			expectError: true,
+		},
+		{
+			name:        "Credentials of another auth method",
+			value:       "X-Auth-Token={{.AccessToken}}",
+			credentials: userPass,
+			expectError: true,
+		},
+		{
+			name:        "Missing template",
+			value:       "X-Auth-Token",
+			credentials: accessToken,
+			expectError: true,
+		},
+		{
+			name:        "Invalid name",
+			value:       "X Auth={{.AccessToken}}",
+			credentials: accessToken,
+			expectError: true,
+		},
+		{
+			name:        "Invalid template",
+			value:       "X-Auth-Token={{.AccessToken",
+			credentials: accessToken,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, err := newCustomAuthHeader(tt.value, tt.replace, tt.credentials)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newCustomAuthHeader() error = %v", err)
+			}
+			if tt.expected == nil {
+				if header != nil {
+					t.Errorf("newCustomAuthHeader() = %+v, want nil", header)
+				}
+				return
+			}
+			if header == nil || *header != *tt.expected {
+				t.Errorf("newCustomAuthHeader() = %+v, want %+v", header, tt.expected)
+			}
+		})
+	}
+}
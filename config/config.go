@@ -1,9 +1,16 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"log/slog"
 	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
@@ -16,20 +23,111 @@ import (
 var (
 	flagLogFormat          = kingpin.Flag(l.FormatFlagName, l.FormatFlagHelp).Default(l.FormatDefault).Enum(l.FormatsAvailable...)
 	flagLogLevel           = kingpin.Flag(l.LevelFlagName, l.LevelFlagHelp).Default(l.LevelDefault).Enum(l.LevelsAvailable...)
+	flagLogRedact          = kingpin.Flag(l.RedactFlagName, l.RedactFlagHelp).Default("true").Bool()
 	listenAddress          = kingpin.Flag("web.listen-address", "Address to listen on for web interface and telemetry.").Envar("WEB_LISTEN_ADDR").Default(":9531").String()
-	metricsPath            = kingpin.Flag("web.telemetry-path", "Path under which to expose metrics.").Envar("WEB_TELEMETRY_PATH").Default("/metrics").String()
+	healthListenAddress    = kingpin.Flag("web.health-listen-address", "Address to serve the /-/healthy and /-/ready probe endpoints on instead of web.listen-address, e.g. an internal port not exposed to Prometheus.").Envar("WEB_HEALTH_LISTEN_ADDR").String()
+	metricsPath            = kingpin.Flag("web.telemetry-path", "Path under which to expose metrics. Pass a comma-separated list to expose them under multiple paths.").Envar("WEB_TELEMETRY_PATH").Default("/metrics").String()
+	shutdownTimeout        = kingpin.Flag("web.shutdown-timeout", "Grace period for in-flight scrapes to complete on shutdown.").Envar("WEB_SHUTDOWN_TIMEOUT").Default("30s").Duration()
+	logLevelEndpoint       = kingpin.Flag("web.enable-log-level-endpoint", "Enable the /-/loglevel endpoint to get and change the log level at runtime.").Envar("WEB_ENABLE_LOG_LEVEL_ENDPOINT").Default("false").Bool()
+	federationDebug        = kingpin.Flag("web.enable-federation-debug-endpoint", "Enable the /debug/federation endpoint returning the federation status fetched from JFrog Artifactory as JSON. Meant for debugging.").Envar("WEB_ENABLE_FEDERATION_DEBUG_ENDPOINT").Default("false").Bool()
+	disableDefaultMetrics  = kingpin.Flag("web.disable-default-metrics", "Don't expose the default Go runtime (go_*), process (process_*) and metrics handler (promhttp_*) metrics.").Envar("WEB_DISABLE_DEFAULT_METRICS").Default("false").Bool()
+	graphiteAddress        = kingpin.Flag("graphite.address", "Address (host:port) of a Graphite server to periodically push the metrics to, in addition to exposing them. Push is disabled if empty.").Envar("GRAPHITE_ADDRESS").String()
+	graphiteInterval       = kingpin.Flag("graphite.interval", "Interval of pushing the metrics to Graphite.").Envar("GRAPHITE_INTERVAL").Default("1m").Duration()
+	graphitePrefix         = kingpin.Flag("graphite.prefix", "Prefix prepended to the name of every metric pushed to Graphite.").Envar("GRAPHITE_PREFIX").String()
+	pushGateway            = kingpin.Flag("push.gateway", "URL of a Prometheus Pushgateway to push the metrics to in oneshot mode.").Envar("PUSH_GATEWAY").String()
+	pushJob                = kingpin.Flag("push.job", "Job label of the metrics pushed to the Pushgateway.").Envar("PUSH_JOB").Default("artifactory_exporter").String()
+	pushGrouping           = kingpin.Flag("push.grouping", "Grouping label of the metrics pushed to the Pushgateway. Pass multiple times for multiple labels.").PlaceHolder("label=value").StringMap()
+	oneshot                = kingpin.Flag("oneshot", "Scrape JFrog Artifactory once, push the metrics to the Pushgateway and exit without starting the web server.").Envar("ONESHOT").Default("false").Bool()
+	instanceLabel          = kingpin.Flag("instance-label", "Constant label added to every metric of the exporter to tell the Artifactory instances of multiple exporters apart, e.g. instance_name=prod.").Envar("INSTANCE_LABEL").PlaceHolder("name=value").String()
+	metricsNamespace       = kingpin.Flag("metrics-namespace", "Namespace prepended to the name of every metric defined by the exporter.").Envar("METRICS_NAMESPACE").Default("artifactory").String()
 	artiScrapeURI          = kingpin.Flag("artifactory.scrape-uri", "URI on which to scrape JFrog Artifactory.").Envar("ARTI_SCRAPE_URI").Default("http://localhost:8081/artifactory").String()
 	artiSSLVerify          = kingpin.Flag("artifactory.ssl-verify", "Flag that enables SSL certificate verification for the scrape URI").Envar("ARTI_SSL_VERIFY").Default("false").Bool()
+	artiMinTLSVersion      = kingpin.Flag("artifactory.tls-min-version", "Minimum TLS version used to connect to the scrape URI. One of: [1.2, 1.3]").Envar("ARTI_TLS_MIN_VERSION").Default("1.2").String()
+	artiCAFile             = kingpin.Flag("artifactory.ca-file", "Path to a PEM encoded CA bundle used to verify the scrape URI in addition to the system CAs.").Envar("ARTI_CA_FILE").String()
+	artiCACertPEM          = kingpin.Flag("artifactory.ca-cert", "PEM encoded CA bundle used to verify the scrape URI in addition to the system CAs and the CA file.").Envar("ARTI_CA_CERT").String()
+	artiUserAgent          = kingpin.Flag("artifactory.user-agent", "User-Agent header sent with every request to JFrog Artifactory. Defaults to artifactory_exporter/<version>.").Envar("ARTI_USER_AGENT").String()
 	artiTimeout            = kingpin.Flag("artifactory.timeout", "Timeout for trying to get stats from JFrog Artifactory.").Envar("ARTI_TIMEOUT").Default("5s").Duration()
+	artiFollowRedirects    = kingpin.Flag("artifactory.follow-redirects", "Follow redirects returned by JFrog Artifactory. If disabled, redirects are reported as errors.").Envar("ARTI_FOLLOW_REDIRECTS").Default("true").Bool()
+	artiAnonymous          = kingpin.Flag("artifactory.anonymous", "Scrape JFrog Artifactory without credentials, for instances which allow anonymous access. No credentials may be set.").Envar("ARTI_ANONYMOUS").Default("false").Bool()
+	artiAuthHeader         = kingpin.Flag("artifactory.auth-header", "Custom header carrying the credentials to JFrog Artifactory, for gateways which strip the Authorization header. The value is a template referencing the credentials of the auth method, e.g. X-Auth-Token={{.AccessToken}}.").Envar("ARTI_AUTH_HEADER").PlaceHolder("name=template").String()
+	artiAuthHeaderReplace  = kingpin.Flag("artifactory.auth-header.replace", "Send the custom auth header instead of the header of the auth method, e.g. the Authorization header.").Envar("ARTI_AUTH_HEADER_REPLACE").Default("false").Bool()
+	artiSaaS               = kingpin.Flag("artifactory.saas", "Scrape a JFrog SaaS (cloud) instance, skipping the endpoints only self-hosted instances provide: the HA licenses, the HA topology and the router health.").Envar("ARTI_SAAS").Default("false").Bool()
+	artiStrictJSON         = kingpin.Flag("artifactory.strict-json", "Log a warning listing the fields of JFrog Artifactory responses the exporter doesn't know, e.g. after an upgrade changed a response. Meant for debugging.").Envar("ARTI_STRICT_JSON").Default("false").Bool()
+	artiHTTPTrace          = kingpin.Flag("artifactory.http-trace", "Record the DNS lookup, connect and TLS handshake times of the requests to JFrog Artifactory as histograms. Meant for debugging latency.").Envar("ARTI_HTTP_TRACE").Default("false").Bool()
+	artiMaxPages           = kingpin.Flag("artifactory.max-pages", "Maximum number of pages to follow on paginated API responses. 0 disables the limit.").Envar("ARTI_MAX_PAGES").Default("100").Int()
+	artiMaxConcurrent      = kingpin.Flag("artifactory.max-concurrent-requests", "Maximum number of requests in flight to JFrog Artifactory. Further requests wait for a free slot until they time out. 0 disables the limit.").Envar("ARTI_MAX_CONCURRENT_REQUESTS").Default("0").Int()
 	optionalMetrics        = kingpin.Flag("optional-metric", fmt.Sprintf("optional metric to be enabled. Valid metrics are: %v", optionalMetricsList)).PlaceHolder("metric-name").Strings()
 	accessFederationTarget = kingpin.Flag("access-federation-target", "URL of Jfrog Access Federation Target server. Only required if optional metric AccessFederationValidate is enabled").Envar("ACCESS_FEDERATION_TARGET").String()
+	federationTimeout      = kingpin.Flag("federation.timeout", "Timeout of the requests to the federation status endpoints of JFrog Artifactory, which are slower than others. Defaults to artifactory.timeout.").Envar("FEDERATION_TIMEOUT").Default("0s").Duration()
+	federationPerNode      = kingpin.Flag("federation.per-node", "Query the federation status of every HA node directly instead of the node answering the scrape URI. Requires optional metric federation_status, federation_mirror_lags or federation_unavailable_mirrors.").Envar("FEDERATION_PER_NODE").Default("false").Bool()
+	nativeMetrics          = kingpin.Flag("native-metric", "Name of a metric family of the JFrog Artifactory open metrics to re-export with the prefix artifactory_native_. Only required if optional metric native_metrics is enabled. Pass multiple times to re-export multiple metric families.").PlaceHolder("name").Strings()
+	cleanupRepos           = kingpin.Flag("cleanup.repo", "Repository with a retention policy to count the artifacts eligible for cleanup in. Only required if optional metric cleanup_eligible is enabled. Pass multiple times to count multiple repositories.").PlaceHolder("repo-key").Strings()
+	cleanupAge             = kingpin.Flag("cleanup.age", "Minimum age of an artifact to be eligible for cleanup. Only applies if optional metric cleanup_eligible is enabled.").Default("720h").Duration()
+	cleanupMaxResults      = kingpin.Flag("cleanup.max-results", "Maximum number of artifacts returned by the AQL query of a repository, bounding the cost of counting the artifacts eligible for cleanup.").Envar("CLEANUP_MAX_RESULTS").Default("10000").Int()
+	checksumMaxResults     = kingpin.Flag("missing-checksums.max-results", "Maximum number of artifacts returned by the AQL query counting the artifacts without a SHA-256 checksum, bounding its cost. Only applies if optional metric missing_checksums is enabled.").Envar("MISSING_CHECKSUMS_MAX_RESULTS").Default("10000").Int()
+	pypiRepos              = kingpin.Flag("pypi-repo", "PyPI repository to count the projects of. Only required if optional metric pypi_packages is enabled. Pass multiple times to count multiple repositories.").PlaceHolder("repo-key").Strings()
+	dockerRepos            = kingpin.Flag("docker-repo", "Docker repository to count images and tags of. Only required if optional metric docker is enabled. Pass multiple times to count multiple repositories.").PlaceHolder("repo-key").Strings()
+	dockerConcurrency      = kingpin.Flag("docker.concurrency", "Maximum number of concurrent requests when counting the tags of Docker images.").Envar("DOCKER_CONCURRENCY").Default("4").Int()
+	storageCalculate       = kingpin.Flag("storage.calculate", "Trigger a recalculation of the storage summary before every scrape of it and wait for it to finish. This is expensive on large instances.").Envar("STORAGE_CALCULATE").Default("false").Bool()
+	storageCalcTimeout     = kingpin.Flag("storage.calculate-timeout", "Maximum time to wait for the storage summary recalculation to finish.").Envar("STORAGE_CALCULATE_TIMEOUT").Default("30s").Duration()
+	customAQL              = kingpin.Flag("custom-aql", "Custom metric counting the results of an AQL query, exposed as <namespace>_custom_<name>. Pass multiple times to define multiple metrics.").PlaceHolder("name=query").StringMap()
+	customAQLConcurrency   = kingpin.Flag("custom-aql.concurrency", "Maximum number of custom AQL queries run concurrently.").Envar("CUSTOM_AQL_CONCURRENCY").Default("2").Int()
+	customAQLMaxResults    = kingpin.Flag("custom-aql.max-results", "Maximum number of results returned by a custom AQL query, bounding its cost. The counts of the custom metrics are capped at it.").Envar("CUSTOM_AQL_MAX_RESULTS").Default("10000").Int()
 	useCache               = kingpin.Flag("use-cache", "Use cache for API responses to circumvent timeouts").Envar("USE_CACHE").Default("false").Bool()
 	cacheTimeout           = kingpin.Flag("cache-timeout", "Timeout for API responses to fallback to cache").Envar("CACHE_TIMEOUT").Default("30s").Duration()
 	cacheTTL               = kingpin.Flag("cache-ttl", "Time to live for cached API responses").Envar("CACHE_TTL").Default("5m").Duration()
+	singleFlight           = kingpin.Flag("single-flight-scrapes", "Let concurrent scrapes share the result of a single collection from JFrog Artifactory.").Envar("SINGLE_FLIGHT_SCRAPES").Default("false").Bool()
+	circuitThreshold       = kingpin.Flag("circuit-breaker.threshold", "Number of consecutive failures of an endpoint after which requests to it are skipped. 0 disables the circuit breaker.").Envar("CIRCUIT_BREAKER_THRESHOLD").Default("0").Int()
+	circuitCooldown        = kingpin.Flag("circuit-breaker.cooldown", "Time requests to a failing endpoint are skipped before testing whether it recovered.").Envar("CIRCUIT_BREAKER_COOLDOWN").Default("1m").Duration()
+	retryMax               = kingpin.Flag("retry.max", "Number of times a request to JFrog Artifactory failing with a network error or a 429 or 5xx response is retried. Applies to the subsystems without a retry.subsystem setting.").Envar("RETRY_MAX").Default("0").Int()
+	retrySubsystem         = kingpin.Flag("retry.subsystem", fmt.Sprintf("Number of times the failed requests of a subsystem are retried, taking precedence over retry.max. Valid subsystems are: %v. Pass multiple times for multiple subsystems.", retrySubsystems)).PlaceHolder("subsystem=n").StringMap()
+	repoLabelAllowlist     = kingpin.Flag("repo-label.allowlist", "Regular expression matching the repositories to export per repository metrics for. Defaults to all repositories.").Envar("REPO_LABEL_ALLOWLIST").String()
+	repoLabelDenylist      = kingpin.Flag("repo-label.denylist", "Regular expression matching the repositories not to export per repository metrics for, even if matched by the allowlist.").Envar("REPO_LABEL_DENYLIST").String()
+	repoLabelUnmatched     = kingpin.Flag("repo-label.unmatched", "What to do with the metrics of repositories excluded by the allowlist or denylist. One of: [other, drop]").Envar("REPO_LABEL_UNMATCHED").Default("other").Enum("other", "drop")
+	repoDriftTypes         = kingpin.Flag("repo-drift.type", fmt.Sprintf("Expected type of a repository, reported as drifted if it differs. One of: %v. Pass multiple times for multiple repositories.", repoTypes)).PlaceHolder("repo=type").StringMap()
+	repoDriftPackageTypes  = kingpin.Flag("repo-drift.package-type", "Expected package type of a repository, reported as drifted if it differs. Pass multiple times for multiple repositories.").PlaceHolder("repo=package-type").StringMap()
+	scrapeMultipliers      = kingpin.Flag("scrape-interval-multiplier", fmt.Sprintf("Only scrape the metrics of a subsystem from JFrog Artifactory on every n-th scrape, serving the last values in between. Valid subsystems are: %v", sampledSubsystems)).PlaceHolder("subsystem=n").StringMap()
+	scrapeDurationBuckets  = kingpin.Flag("scrape-duration.buckets", "Upper bound of a bucket of the scrape duration histogram in seconds. Pass multiple times for multiple buckets.").Default("0.1", "0.25", "0.5", "1", "2.5", "5", "10", "30", "60").Float64List()
 	artifactsTimeIntervals = kingpin.Flag("artifacts-time-interval", "Time interval for created and downloaded stats").Default("1m", "5m", "15m").DurationList()
+	artifactsRecentWindows = kingpin.Flag("artifacts-recent-window", "Time window to count the artifacts created in across all repositories. Only applies if optional metric artifacts_recent is enabled.").Default("15m").DurationList()
+	recentMaxResults       = kingpin.Flag("artifacts-recent.max-results", "Maximum number of artifacts returned by the AQL query of a time window, bounding the cost of counting the recently created artifacts. Only applies if optional metric artifacts_recent is enabled.").Envar("ARTIFACTS_RECENT_MAX_RESULTS").Default("10000").Int()
+	tokensExpiringWindows  = kingpin.Flag("access-tokens-expiring-window", "Time window to count the access tokens expiring within. Only applies if optional metric access_tokens is enabled.").Default("168h").DurationList()
+	configFile             = kingpin.Flag(configFileFlagName, "Path to a YAML file setting flags by name. Flags and environment variables take precedence over its values.").Envar("CONFIG_FILE").String()
+	validate               = kingpin.Flag("validate", "Validate the configuration and connectivity to JFrog Artifactory, then exit without starting the web server.").Default("false").Bool()
 )
 
-var optionalMetricsList = []string{"artifacts", "replication_status", "federation_status", "open_metrics", "access_federation_validate", "background_tasks"}
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// reMetricsNamespace matches valid Prometheus metric name prefixes.
+var reMetricsNamespace = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// reLabelName matches valid Prometheus label names.
+var reLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reCustomMetricName matches valid names of custom metrics.
+var reCustomMetricName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reAQLLimit matches the limit of an AQL query.
+var reAQLLimit = regexp.MustCompile(`\.limit\s*\(`)
+
+var optionalMetricsList = []string{"artifacts", "replication_status", "federation_status", "open_metrics", "access_federation_validate", "background_tasks", "permission_target_repos", "docker", "artifacts_recent", "backups", "access_tokens", "federation_mirror_lags", "federation_unavailable_mirrors", "system_info", "remote_repos", "garbage_collection", "repo_layouts", "access_federation_servers", "native_metrics", "cleanup_eligible", "storage_used_delta", "pypi_packages", "virtual_repos", "release_bundles", "repo_file_count_delta", "missing_checksums", "config_descriptor"}
+
+// repoTypes are the types of Artifactory repositories.
+var repoTypes = []string{"local", "remote", "virtual", "federated"}
+
+// sampledSubsystems are the subsystems which support a scrape interval multiplier.
+var sampledSubsystems = []string{"storage", "artifacts", "docker", "federation"}
+
+// selfHostedOptionalMetrics are the optional metrics whose endpoints only
+// self-hosted instances provide, as they read the open metrics or the
+// configuration descriptor.
+var selfHostedOptionalMetrics = []string{"open_metrics", "backups", "system_info", "garbage_collection", "native_metrics", "config_descriptor"}
+
+// retrySubsystems are the subsystems whose requests support a retry setting.
+var retrySubsystems = []string{"ping", "aql", "system", "storage", "replication", "repositories", "security", "federation", "access", "docker", "pypi", "tasks", "open_metrics"}
 
 // Credentials represents Username and Password or API Key for
 // Artifactory Authentication
@@ -38,44 +136,538 @@ type Credentials struct {
 	Username    string `required:"false" envconfig:"ARTI_USERNAME"`
 	Password    string `required:"false" envconfig:"ARTI_PASSWORD"`
 	AccessToken string `required:"false" envconfig:"ARTI_ACCESS_TOKEN"`
+	APIKey      string `required:"false" envconfig:"ARTI_API_KEY"`
+}
+
+// setAuthMethod sets the AuthMethod matching the credentials. Exactly one of
+// username and password, access token or API key has to be set, unless
+// anonymous access is chosen, which requires that none of them is set.
+func (c *Credentials) setAuthMethod(anonymous bool) error {
+	if anonymous {
+		if *c != (Credentials{}) {
+			return fmt.Errorf("no credentials may be set with `artifactory.anonymous`, unset `ARTI_USERNAME`, `ARTI_PASSWORD`, `ARTI_ACCESS_TOKEN` and `ARTI_API_KEY`")
+		}
+		c.AuthMethod = "anonymous"
+		return nil
+	}
+	var methods []string
+	if c.Username != "" || c.Password != "" {
+		if c.Username == "" || c.Password == "" {
+			return fmt.Errorf("`ARTI_USERNAME` and `ARTI_PASSWORD` environment variables have to be set together")
+		}
+		methods = append(methods, "userPass")
+	}
+	if c.AccessToken != "" {
+		methods = append(methods, "accessToken")
+	}
+	if c.APIKey != "" {
+		methods = append(methods, "apiKey")
+	}
+	if len(methods) == 0 {
+		return fmt.Errorf("either `ARTI_USERNAME` and `ARTI_PASSWORD`, `ARTI_ACCESS_TOKEN` or `ARTI_API_KEY` environment variable has to be set, or `artifactory.anonymous` for anonymous access")
+	}
+	if len(methods) != 1 {
+		return fmt.Errorf("either `ARTI_USERNAME` and `ARTI_PASSWORD`, `ARTI_ACCESS_TOKEN` or `ARTI_API_KEY` environment variable has to be set")
+	}
+	c.AuthMethod = methods[0]
+	return nil
 }
 
 // Updated OptionalMetrics struct to include YAML tags for better configuration management
 type OptionalMetrics struct {
-	Artifacts                bool `yaml:"artifacts"`
-	ReplicationStatus        bool `yaml:"replication_status"`
-	FederationStatus         bool `yaml:"federation_status"`
-	OpenMetrics              bool `yaml:"open_metrics"`
-	AccessFederationValidate bool `yaml:"access_federation_validate"`
-	BackgroundTasks          bool `yaml:"background_tasks"`
+	Artifacts                    bool `yaml:"artifacts"`
+	ReplicationStatus            bool `yaml:"replication_status"`
+	FederationStatus             bool `yaml:"federation_status"`
+	FederationMirrorLags         bool `yaml:"federation_mirror_lags"`
+	FederationUnavailableMirrors bool `yaml:"federation_unavailable_mirrors"`
+	OpenMetrics                  bool `yaml:"open_metrics"`
+	AccessFederationValidate     bool `yaml:"access_federation_validate"`
+	BackgroundTasks              bool `yaml:"background_tasks"`
+	PermissionTargetRepos        bool `yaml:"permission_target_repos"`
+	Docker                       bool `yaml:"docker"`
+	ArtifactsRecent              bool `yaml:"artifacts_recent"`
+	Backups                      bool `yaml:"backups"`
+	AccessTokens                 bool `yaml:"access_tokens"`
+	SystemInfo                   bool `yaml:"system_info"`
+	RemoteRepos                  bool `yaml:"remote_repos"`
+	GarbageCollection            bool `yaml:"garbage_collection"`
+	RepoLayouts                  bool `yaml:"repo_layouts"`
+	AccessFederationServers      bool `yaml:"access_federation_servers"`
+	NativeMetrics                bool `yaml:"native_metrics"`
+	CleanupEligible              bool `yaml:"cleanup_eligible"`
+	StorageUsedDelta             bool `yaml:"storage_used_delta"`
+	PyPIPackages                 bool `yaml:"pypi_packages"`
+	VirtualRepos                 bool `yaml:"virtual_repos"`
+	ReleaseBundles               bool `yaml:"release_bundles"`
+	RepoFileCountDelta           bool `yaml:"repo_file_count_delta"`
+	MissingChecksums             bool `yaml:"missing_checksums"`
+	ConfigDescriptor             bool `yaml:"config_descriptor"`
+}
+
+// Enabled returns the names of all enabled optional metrics.
+func (o OptionalMetrics) Enabled() []string {
+	enabled := []string{}
+	for _, metric := range optionalMetricsList {
+		var on bool
+		switch metric {
+		case "artifacts":
+			on = o.Artifacts
+		case "replication_status":
+			on = o.ReplicationStatus
+		case "federation_status":
+			on = o.FederationStatus
+		case "open_metrics":
+			on = o.OpenMetrics
+		case "access_federation_validate":
+			on = o.AccessFederationValidate
+		case "background_tasks":
+			on = o.BackgroundTasks
+		case "permission_target_repos":
+			on = o.PermissionTargetRepos
+		case "docker":
+			on = o.Docker
+		case "artifacts_recent":
+			on = o.ArtifactsRecent
+		case "backups":
+			on = o.Backups
+		case "access_tokens":
+			on = o.AccessTokens
+		case "federation_mirror_lags":
+			on = o.FederationMirrorLags
+		case "federation_unavailable_mirrors":
+			on = o.FederationUnavailableMirrors
+		case "system_info":
+			on = o.SystemInfo
+		case "remote_repos":
+			on = o.RemoteRepos
+		case "garbage_collection":
+			on = o.GarbageCollection
+		case "repo_layouts":
+			on = o.RepoLayouts
+		case "access_federation_servers":
+			on = o.AccessFederationServers
+		case "native_metrics":
+			on = o.NativeMetrics
+		case "cleanup_eligible":
+			on = o.CleanupEligible
+		case "storage_used_delta":
+			on = o.StorageUsedDelta
+		case "pypi_packages":
+			on = o.PyPIPackages
+		case "virtual_repos":
+			on = o.VirtualRepos
+		case "release_bundles":
+			on = o.ReleaseBundles
+		case "repo_file_count_delta":
+			on = o.RepoFileCountDelta
+		case "missing_checksums":
+			on = o.MissingChecksums
+		case "config_descriptor":
+			on = o.ConfigDescriptor
+		}
+		if on {
+			enabled = append(enabled, metric)
+		}
+	}
+	return enabled
+}
+
+// Federation returns true if any of the federation metrics is enabled. The
+// federation_status metric is a shorthand enabling all of them.
+func (o OptionalMetrics) Federation() bool {
+	return o.MirrorLags() || o.UnavailableMirrors()
+}
+
+// MirrorLags returns true if the federation mirror lags are enabled, either
+// by federation_mirror_lags or by federation_status.
+func (o OptionalMetrics) MirrorLags() bool {
+	return o.FederationStatus || o.FederationMirrorLags
+}
+
+// UnavailableMirrors returns true if the unavailable federation mirrors are
+// enabled, either by federation_unavailable_mirrors or by federation_status.
+func (o OptionalMetrics) UnavailableMirrors() bool {
+	return o.FederationStatus || o.FederationUnavailableMirrors
+}
+
+// newOptionalMetrics enables the optional metrics with the given names.
+func newOptionalMetrics(metrics []string) (OptionalMetrics, error) {
+	optMetrics := OptionalMetrics{}
+	for _, metric := range metrics {
+		switch metric {
+		case "artifacts":
+			optMetrics.Artifacts = true
+		case "replication_status":
+			optMetrics.ReplicationStatus = true
+		case "federation_status":
+			optMetrics.FederationStatus = true
+		case "open_metrics":
+			optMetrics.OpenMetrics = true
+		case "access_federation_validate":
+			optMetrics.AccessFederationValidate = true
+		case "background_tasks":
+			optMetrics.BackgroundTasks = true
+		case "permission_target_repos":
+			optMetrics.PermissionTargetRepos = true
+		case "docker":
+			optMetrics.Docker = true
+		case "artifacts_recent":
+			optMetrics.ArtifactsRecent = true
+		case "backups":
+			optMetrics.Backups = true
+		case "access_tokens":
+			optMetrics.AccessTokens = true
+		case "federation_mirror_lags":
+			optMetrics.FederationMirrorLags = true
+		case "federation_unavailable_mirrors":
+			optMetrics.FederationUnavailableMirrors = true
+		case "system_info":
+			optMetrics.SystemInfo = true
+		case "remote_repos":
+			optMetrics.RemoteRepos = true
+		case "garbage_collection":
+			optMetrics.GarbageCollection = true
+		case "repo_layouts":
+			optMetrics.RepoLayouts = true
+		case "access_federation_servers":
+			optMetrics.AccessFederationServers = true
+		case "native_metrics":
+			optMetrics.NativeMetrics = true
+		case "cleanup_eligible":
+			optMetrics.CleanupEligible = true
+		case "storage_used_delta":
+			optMetrics.StorageUsedDelta = true
+		case "pypi_packages":
+			optMetrics.PyPIPackages = true
+		case "virtual_repos":
+			optMetrics.VirtualRepos = true
+		case "release_bundles":
+			optMetrics.ReleaseBundles = true
+		case "repo_file_count_delta":
+			optMetrics.RepoFileCountDelta = true
+		case "missing_checksums":
+			optMetrics.MissingChecksums = true
+		case "config_descriptor":
+			optMetrics.ConfigDescriptor = true
+		default:
+			return optMetrics, fmt.Errorf("unknown optional metric: %s. Valid optional metrics are: %v", metric, optionalMetricsList)
+		}
+	}
+	return optMetrics, nil
 }
 
 type timeInterval struct {
+	Interval    time.Duration
 	Duration    int
 	Unit        string
 	Period      string
 	ShortPeriod string
 }
 
+// RepoFilter limits the repositories per repository metrics are exported for.
+type RepoFilter struct {
+	Allowlist *regexp.Regexp
+	Denylist  *regexp.Regexp
+	// DropUnmatched drops the metrics of excluded repositories instead of
+	// aggregating them into a single "other" repository.
+	DropUnmatched bool
+}
+
+// Matches returns true if per repository metrics are exported for repo.
+func (f RepoFilter) Matches(repo string) bool {
+	if f.Allowlist != nil && !f.Allowlist.MatchString(repo) {
+		return false
+	}
+	return f.Denylist == nil || !f.Denylist.MatchString(repo)
+}
+
+// RepoDrift holds the expected configuration of repositories, keyed by
+// repository key. Repositories whose configuration differs have drifted.
+type RepoDrift struct {
+	Types        map[string]string
+	PackageTypes map[string]string
+}
+
+// Repos returns the sorted keys of all repositories with an expected configuration.
+func (d RepoDrift) Repos() []string {
+	repos := make([]string, 0, len(d.Types)+len(d.PackageTypes))
+	for repo := range d.Types {
+		repos = append(repos, repo)
+	}
+	for repo := range d.PackageTypes {
+		if _, ok := d.Types[repo]; !ok {
+			repos = append(repos, repo)
+		}
+	}
+	slices.Sort(repos)
+	return repos
+}
+
+// newRepoDrift validates and normalizes the expected repository configuration.
+func newRepoDrift(types map[string]string, packageTypes map[string]string) (RepoDrift, error) {
+	drift := RepoDrift{
+		Types:        make(map[string]string, len(types)),
+		PackageTypes: make(map[string]string, len(packageTypes)),
+	}
+	for repo, repoType := range types {
+		repoType = strings.ToLower(repoType)
+		if !slices.Contains(repoTypes, repoType) {
+			return drift, fmt.Errorf("invalid expected type of repository %s: %q. Valid types are: %v", repo, repoType, repoTypes)
+		}
+		drift.Types[repo] = repoType
+	}
+	for repo, packageType := range packageTypes {
+		if packageType == "" {
+			return drift, fmt.Errorf("expected package type of repository %s must not be empty", repo)
+		}
+		drift.PackageTypes[repo] = strings.ToLower(packageType)
+	}
+	return drift, nil
+}
+
+// compileRepoRegexp compiles a repository filter expression, which has to
+// match the whole repository key. An empty expression returns nil.
+func compileRepoRegexp(flag string, expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression for `%s`: %w", flag, err)
+	}
+	return re, nil
+}
+
+// newTimeIntervals converts durations to AQL relative time periods.
+func newTimeIntervals(durations []time.Duration) []timeInterval {
+	timeIntervals := make([]timeInterval, len(durations))
+	for idx, interval := range durations {
+		duration, unit := getAqlTimeFormat(interval)
+		timeIntervals[idx] = timeInterval{
+			Interval:    interval,
+			Duration:    duration,
+			Unit:        unit,
+			Period:      fmt.Sprintf("%d%s", duration, unit),
+			ShortPeriod: fmt.Sprintf("%d%s", duration, unit[:1]),
+		}
+	}
+	return timeIntervals
+}
+
 type ExporterRuntimeConfig struct {
 	OptionalMetrics        OptionalMetrics
 	ArtifactsTimeIntervals []timeInterval
+	ArtifactsRecentWindows []timeInterval
+	TokensExpiringWindows  []timeInterval
+	CleanupAge             timeInterval
+	RepoFilter             RepoFilter
+	RepoDrift              RepoDrift
 }
 
 // Config represents all configuration options for running the Exporter.
 type Config struct {
-	ListenAddress          string
-	MetricsPath            string
-	ArtiScrapeURI          string
-	Credentials            *Credentials
-	ArtiSSLVerify          bool
-	ArtiTimeout            time.Duration
-	UseCache               bool
-	CacheTimeout           time.Duration
-	CacheTTL               time.Duration
-	ExporterRuntimeConfig  *ExporterRuntimeConfig
-	AccessFederationTarget string
-	Logger                 *slog.Logger
+	ListenAddress           string
+	HealthListenAddress     string
+	MetricsPaths            []string
+	MetricsNamespace        string
+	InstanceLabel           map[string]string
+	DisableDefaultMetrics   bool
+	ShutdownTimeout         time.Duration
+	GraphiteAddress         string
+	GraphiteInterval        time.Duration
+	GraphitePrefix          string
+	PushGateway             string
+	PushJob                 string
+	PushGrouping            map[string]string
+	Oneshot                 bool
+	ArtiScrapeURI           string
+	Credentials             *Credentials
+	ArtiSSLVerify           bool
+	MinTLSVersion           uint16
+	CAFile                  string
+	CACertPEM               string
+	UserAgent               string
+	ArtiTimeout             time.Duration
+	ArtiMaxPages            int
+	MaxConcurrentRequests   int
+	ArtiNoFollowRedirects   bool
+	HTTPTrace               bool
+	CustomAuthHeader        *CustomAuthHeader
+	StrictJSON              bool
+	SaaS                    bool
+	UseCache                bool
+	CacheTimeout            time.Duration
+	CacheTTL                time.Duration
+	SingleFlightScrapes     bool
+	ScrapeMultipliers       map[string]int
+	ScrapeDurationBuckets   []float64
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+	MaxRetries              int
+	SubsystemRetries        map[string]int
+	ExporterRuntimeConfig   *ExporterRuntimeConfig
+	AccessFederationTarget  string
+	DockerRepos             []string
+	NativeMetrics           []string
+	CleanupRepos            []string
+	PackageRepos            map[string][]string
+	CleanupMaxResults       int
+	ChecksumMaxResults      int
+	RecentMaxResults        int
+	DockerConcurrency       int
+	CustomAQLQueries        map[string]string
+	CustomAQLConcurrency    int
+	CustomAQLMaxResults     int
+	StorageCalculate        bool
+	StorageCalculateTimeout time.Duration
+	FederationPerNode       bool
+	FederationTimeout       time.Duration
+	Validate                bool
+	LogLevelEndpoint        bool
+	FederationDebugEndpoint bool
+	LogLevel                *slog.LevelVar
+	Logger                  *slog.Logger
+	// logConfig are the log settings the logger was created with.
+	logConfig l.Config
+}
+
+// RootCAs returns the system CAs extended by the CA file and the inline CA bundle.
+// It returns nil if neither is configured, so the system CAs are used as is.
+func (c *Config) RootCAs() (*x509.CertPool, error) {
+	if c.CAFile == "" && c.CACertPEM == "" {
+		return nil, nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid PEM encoded certificates found in CA file %s", c.CAFile)
+		}
+	}
+	if c.CACertPEM != "" {
+		if !pool.AppendCertsFromPEM([]byte(c.CACertPEM)) {
+			return nil, fmt.Errorf("no valid PEM encoded certificates found in inline CA bundle")
+		}
+	}
+	return pool, nil
+}
+
+// parseScrapeMultipliers validates the scrape interval multiplier of each subsystem.
+func parseScrapeMultipliers(flags map[string]string) (map[string]int, error) {
+	multipliers := make(map[string]int, len(flags))
+	for subsystem, value := range flags {
+		if !slices.Contains(sampledSubsystems, subsystem) {
+			return nil, fmt.Errorf("unknown subsystem for scrape interval multiplier: %s. Valid subsystems are: %v", subsystem, sampledSubsystems)
+		}
+		multiplier, err := strconv.Atoi(value)
+		if err != nil || multiplier < 1 {
+			return nil, fmt.Errorf("scrape interval multiplier of subsystem %s must be a positive integer, got %q", subsystem, value)
+		}
+		multipliers[subsystem] = multiplier
+	}
+	return multipliers, nil
+}
+
+// parseSubsystemRetries validates the number of retries of each subsystem.
+func parseSubsystemRetries(flags map[string]string) (map[string]int, error) {
+	retries := make(map[string]int, len(flags))
+	for subsystem, value := range flags {
+		if !slices.Contains(retrySubsystems, subsystem) {
+			return nil, fmt.Errorf("unknown subsystem for retries: %s. Valid subsystems are: %v", subsystem, retrySubsystems)
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("retries of subsystem %s must be a non-negative integer, got %q", subsystem, value)
+		}
+		retries[subsystem] = n
+	}
+	return retries, nil
+}
+
+// parseMetricsPaths splits the comma-separated list of paths to expose the
+// metrics under. Every path has to start with a slash.
+func parseMetricsPaths(value string) ([]string, error) {
+	var paths []string
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid metrics path: %q. It has to start with /", path)
+		}
+		if slices.Contains(paths, path) {
+			return nil, fmt.Errorf("duplicate metrics path: %s", path)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// reservedLabelNames are the label names of the exporter's own metrics, which
+// the instance label must not collide with. Keep it in sync with the labels of
+// the metrics of the collector package.
+var reservedLabelNames = []string{"alias", "base_url", "bundle_name", "collector", "cron_exp", "endpoint", "expires", "ha_node_id", "issued_by", "key", "layout", "license_hash", "licensed_to", "name", "node_id", "node_url", "package_type", "realm", "reason", "remote_name", "remote_url", "repo", "revision", "server_id", "server_name", "service_id", "state", "status", "storage_dir", "storage_type", "subsystem", "type", "url", "valid_through", "version", "window", "within"}
+
+// parseInstanceLabel parses the name=value pair of the constant label added to
+// every metric. It returns nil if value is empty. Names starting with __ are
+// reserved for internal use by Prometheus, and the names of the exporter's own
+// labels would collide with them.
+func parseInstanceLabel(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	name, labelValue, ok := strings.Cut(value, "=")
+	if !ok || labelValue == "" {
+		return nil, fmt.Errorf("invalid instance label: %q. It has to be in the form name=value", value)
+	}
+	if !reLabelName.MatchString(name) || strings.HasPrefix(name, "__") {
+		return nil, fmt.Errorf("invalid instance label name: %q. It has to match %s and must not start with __", name, reLabelName)
+	}
+	if slices.Contains(reservedLabelNames, name) {
+		return nil, fmt.Errorf("invalid instance label name: %q. It is already used by the metrics of the exporter, reserved names are: %v", name, reservedLabelNames)
+	}
+	return map[string]string{name: labelValue}, nil
+}
+
+// validateSaaSOptionalMetrics checks that none of the optional metrics only
+// self-hosted instances provide is enabled.
+func validateSaaSOptionalMetrics(optMetrics OptionalMetrics) error {
+	for _, metric := range optMetrics.Enabled() {
+		if slices.Contains(selfHostedOptionalMetrics, metric) {
+			return fmt.Errorf("optional metric %s isn't available on JFrog SaaS instances, disable it or unset `artifactory.saas`", metric)
+		}
+	}
+	return nil
+}
+
+// newFederationTimeout returns the timeout of the federation status requests,
+// falling back to the timeout of all other requests if it isn't set.
+func newFederationTimeout(federationTimeout, artiTimeout time.Duration) time.Duration {
+	if federationTimeout == 0 {
+		return artiTimeout
+	}
+	return federationTimeout
+}
+
+// validateBuckets checks that the upper bounds of histogram buckets are
+// positive and sorted in strictly ascending order.
+func validateBuckets(buckets []float64) error {
+	if len(buckets) == 0 {
+		return fmt.Errorf("at least one bucket must be set")
+	}
+	for i, bucket := range buckets {
+		if bucket <= 0 {
+			return fmt.Errorf("bucket %v must be positive", bucket)
+		}
+		if i > 0 && bucket <= buckets[i-1] {
+			return fmt.Errorf("buckets must be sorted in ascending order, got %v after %v", bucket, buckets[i-1])
+		}
+	}
+	return nil
 }
 
 func getAqlTimeFormat(d time.Duration) (int, string) {
@@ -97,19 +689,32 @@ func NewConfig() (*Config, error) {
 
 	kingpin.HelpFlag.Short('h')
 	kingpin.Version(version.Info() + " " + version.BuildContext())
+	fileCredentials, err := applyConfigFile(kingpin.CommandLine, os.Args[1:])
+	if err != nil {
+		return nil, err
+	}
 	kingpin.Parse()
+	return newConfig(fileCredentials)
+}
 
+// newConfig creates the Config from the parsed flags, the environment and the
+// credentials of the config file.
+func newConfig(fileCredentials Credentials) (*Config, error) {
 	var credentials Credentials
 	err := envconfig.Process("", &credentials)
 	if err != nil {
 		return nil, err
 	}
-	if credentials.Username != "" && credentials.Password != "" && credentials.AccessToken == "" {
-		credentials.AuthMethod = "userPass"
-	} else if credentials.Username == "" && credentials.Password == "" && credentials.AccessToken != "" {
-		credentials.AuthMethod = "accessToken"
-	} else {
-		return nil, fmt.Errorf("`ARTI_USERNAME` and `ARTI_PASSWORD` or `ARTI_ACCESS_TOKEN` environment variable has to be set")
+	// Credentials set in the environment replace those of the config file.
+	if credentials == (Credentials{}) {
+		credentials = fileCredentials
+	}
+	if err := credentials.setAuthMethod(*artiAnonymous); err != nil {
+		return nil, err
+	}
+	customAuthHeader, err := newCustomAuthHeader(*artiAuthHeader, *artiAuthHeaderReplace, credentials)
+	if err != nil {
+		return nil, err
 	}
 
 	_, err = url.Parse(*artiScrapeURI)
@@ -117,40 +722,111 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
-	optMetrics := OptionalMetrics{}
-	for _, metric := range *optionalMetrics {
-		switch metric {
-		case "artifacts":
-			optMetrics.Artifacts = true
-		case "replication_status":
-			optMetrics.ReplicationStatus = true
-		case "federation_status":
-			optMetrics.FederationStatus = true
-		case "open_metrics":
-			optMetrics.OpenMetrics = true
-		case "access_federation_validate":
-			optMetrics.AccessFederationValidate = true
-		case "background_tasks":
-			optMetrics.BackgroundTasks = true
-		default:
-			return nil, fmt.Errorf("unknown optional metric: %s. Valid optional metrics are: %v", metric, optionalMetricsList)
-		}
+	paths, err := parseMetricsPaths(*metricsPath)
+	if err != nil {
+		return nil, err
 	}
 
-	timeIntervals := make([]timeInterval, len(*artifactsTimeIntervals))
-	for idx, interval := range *artifactsTimeIntervals {
-		duration, unit := getAqlTimeFormat(interval)
-		timeIntervals[idx] = timeInterval{
-			Duration:    duration,
-			Unit:        unit,
-			Period:      fmt.Sprintf("%d%s", duration, unit),
-			ShortPeriod: fmt.Sprintf("%d%s", duration, unit[:1]),
+	instanceLabels, err := parseInstanceLabel(*instanceLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reMetricsNamespace.MatchString(*metricsNamespace) {
+		return nil, fmt.Errorf("invalid metrics namespace: %q. It has to match %s", *metricsNamespace, reMetricsNamespace)
+	}
+
+	minTLSVersion, ok := tlsVersions[*artiMinTLSVersion]
+	if !ok {
+		return nil, fmt.Errorf("unknown minimum TLS version: %s. Valid versions are: 1.2, 1.3", *artiMinTLSVersion)
+	}
+
+	if *circuitThreshold < 0 {
+		return nil, fmt.Errorf("`circuit-breaker.threshold` must not be negative, got %d", *circuitThreshold)
+	}
+
+	if *retryMax < 0 {
+		return nil, fmt.Errorf("`retry.max` must not be negative, got %d", *retryMax)
+	}
+	subsystemRetries, err := parseSubsystemRetries(*retrySubsystem)
+	if err != nil {
+		return nil, err
+	}
+
+	if *graphiteAddress != "" && *graphiteInterval <= 0 {
+		return nil, fmt.Errorf("`graphite.interval` must be positive, got %s", *graphiteInterval)
+	}
+
+	if *pushGateway != "" {
+		if _, err := url.Parse(*pushGateway); err != nil {
+			return nil, fmt.Errorf("invalid `push.gateway` URL: %w", err)
+		}
+		if *pushJob == "" {
+			return nil, fmt.Errorf("`push.job` must not be empty")
 		}
+	} else if *oneshot {
+		return nil, fmt.Errorf("a Pushgateway must be set with `push.gateway` if oneshot is enabled")
+	}
+
+	if *healthListenAddress != "" && *healthListenAddress == *listenAddress {
+		return nil, fmt.Errorf("`web.health-listen-address` must differ from `web.listen-address`, got %s", *healthListenAddress)
+	}
+
+	if *artiMaxPages < 0 {
+		return nil, fmt.Errorf("`artifactory.max-pages` must not be negative, got %d", *artiMaxPages)
+	}
+	if *artiMaxConcurrent < 0 {
+		return nil, fmt.Errorf("`artifactory.max-concurrent-requests` must not be negative, got %d", *artiMaxConcurrent)
+	}
+
+	multipliers, err := parseScrapeMultipliers(*scrapeMultipliers)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateBuckets(*scrapeDurationBuckets); err != nil {
+		return nil, fmt.Errorf("invalid `scrape-duration.buckets`: %w", err)
+	}
+
+	repoAllowlist, err := compileRepoRegexp("repo-label.allowlist", *repoLabelAllowlist)
+	if err != nil {
+		return nil, err
+	}
+	repoDenylist, err := compileRepoRegexp("repo-label.denylist", *repoLabelDenylist)
+	if err != nil {
+		return nil, err
+	}
+
+	optMetrics, err := newOptionalMetrics(*optionalMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	if optMetrics.ArtifactsRecent && len(*artifactsRecentWindows) == 0 {
+		return nil, fmt.Errorf("at least one time window must be set with `artifacts-recent-window` if optional metric artifacts_recent is enabled")
+	}
+
+	if optMetrics.AccessTokens && len(*tokensExpiringWindows) == 0 {
+		return nil, fmt.Errorf("at least one time window must be set with `access-tokens-expiring-window` if optional metric access_tokens is enabled")
+	}
+
+	repoDrift, err := newRepoDrift(*repoDriftTypes, *repoDriftPackageTypes)
+	if err != nil {
+		return nil, err
 	}
 
 	exporterRuntimeConfig := ExporterRuntimeConfig{
 		OptionalMetrics:        optMetrics,
-		ArtifactsTimeIntervals: timeIntervals,
+		ArtifactsTimeIntervals: newTimeIntervals(*artifactsTimeIntervals),
+		ArtifactsRecentWindows: newTimeIntervals(*artifactsRecentWindows),
+		TokensExpiringWindows:  newTimeIntervals(*tokensExpiringWindows),
+		CleanupAge:             newTimeIntervals([]time.Duration{*cleanupAge})[0],
+		RepoFilter: RepoFilter{
+			Allowlist:     repoAllowlist,
+			Denylist:      repoDenylist,
+			DropUnmatched: *repoLabelUnmatched == "drop",
+		},
+		RepoDrift: repoDrift,
 	}
 
 	if *accessFederationTarget != "" {
@@ -162,25 +838,144 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("JFrog Access Federation target URL must be set if optional metric AccessFederationValidate is enabled")
 	}
 
-	logger := l.New(
-		l.Config{
-			Format: *flagLogFormat,
-			Level:  *flagLogLevel,
-		},
-	)
-	return &Config{
-		ListenAddress:          *listenAddress,
-		MetricsPath:            *metricsPath,
-		ArtiScrapeURI:          *artiScrapeURI,
-		Credentials:            &credentials,
-		ArtiSSLVerify:          *artiSSLVerify,
-		ArtiTimeout:            *artiTimeout,
-		UseCache:               *useCache,
-		CacheTimeout:           *cacheTimeout,
-		CacheTTL:               *cacheTTL,
-		ExporterRuntimeConfig:  &exporterRuntimeConfig,
-		AccessFederationTarget: *accessFederationTarget,
-		Logger:                 logger,
-	}, nil
+	if *federationTimeout < 0 {
+		return nil, fmt.Errorf("`federation.timeout` must not be negative, got %s", *federationTimeout)
+	}
+
+	if optMetrics.NativeMetrics && len(*nativeMetrics) == 0 {
+		return nil, fmt.Errorf("at least one metric family must be set with `native-metric` if optional metric native_metrics is enabled")
+	}
+	if *artiSaaS {
+		if err := validateSaaSOptionalMetrics(optMetrics); err != nil {
+			return nil, err
+		}
+	}
+	if optMetrics.CleanupEligible && len(*cleanupRepos) == 0 {
+		return nil, fmt.Errorf("at least one repository must be set with `cleanup.repo` if optional metric cleanup_eligible is enabled")
+	}
+	if *cleanupAge < time.Minute {
+		return nil, fmt.Errorf("`cleanup.age` must be at least 1m, got %s", *cleanupAge)
+	}
+	if *cleanupMaxResults < 1 {
+		return nil, fmt.Errorf("`cleanup.max-results` must be at least 1, got %d", *cleanupMaxResults)
+	}
+	if *checksumMaxResults < 1 {
+		return nil, fmt.Errorf("`missing-checksums.max-results` must be at least 1, got %d", *checksumMaxResults)
+	}
+	if *recentMaxResults < 1 {
+		return nil, fmt.Errorf("`artifacts-recent.max-results` must be at least 1, got %d", *recentMaxResults)
+	}
+	packageRepos := make(map[string][]string)
+	if optMetrics.PyPIPackages {
+		if len(*pypiRepos) == 0 {
+			return nil, fmt.Errorf("at least one PyPI repository must be set with `pypi-repo` if optional metric pypi_packages is enabled")
+		}
+		packageRepos["pypi"] = *pypiRepos
+	}
+	if optMetrics.Docker && len(*dockerRepos) == 0 {
+		return nil, fmt.Errorf("at least one Docker repository must be set with `docker-repo` if optional metric docker is enabled")
+	}
+	if *dockerConcurrency < 1 {
+		return nil, fmt.Errorf("`docker.concurrency` must be at least 1, got %d", *dockerConcurrency)
+	}
+
+	for name, query := range *customAQL {
+		if !reCustomMetricName.MatchString(name) {
+			return nil, fmt.Errorf("invalid name of custom AQL metric: %q. It has to match %s", name, reCustomMetricName)
+		}
+		if strings.TrimSpace(query) == "" {
+			return nil, fmt.Errorf("query of custom AQL metric %s must not be empty", name)
+		}
+		if reAQLLimit.MatchString(query) {
+			return nil, fmt.Errorf("query of custom AQL metric %s must not set a limit, it's limited by `custom-aql.max-results`", name)
+		}
+	}
+	if *customAQLConcurrency < 1 {
+		return nil, fmt.Errorf("`custom-aql.concurrency` must be at least 1, got %d", *customAQLConcurrency)
+	}
+	if *customAQLMaxResults < 1 {
+		return nil, fmt.Errorf("`custom-aql.max-results` must be at least 1, got %d", *customAQLMaxResults)
+	}
+
+	logConfig := l.Config{
+		Format: *flagLogFormat,
+		Level:  *flagLogLevel,
+		Redact: *flagLogRedact,
+	}
+	if customAuthHeader != nil {
+		logConfig.SensitiveHeaders = []string{customAuthHeader.Name}
+	}
+	logLevel := new(slog.LevelVar)
+	loggerConfig := logConfig
+	loggerConfig.LevelVar = logLevel
+	logger := l.New(loggerConfig)
+	conf := &Config{
+		ListenAddress:           *listenAddress,
+		HealthListenAddress:     *healthListenAddress,
+		MetricsPaths:            paths,
+		InstanceLabel:           instanceLabels,
+		MetricsNamespace:        *metricsNamespace,
+		DisableDefaultMetrics:   *disableDefaultMetrics,
+		ShutdownTimeout:         *shutdownTimeout,
+		GraphiteAddress:         *graphiteAddress,
+		GraphiteInterval:        *graphiteInterval,
+		GraphitePrefix:          *graphitePrefix,
+		PushGateway:             *pushGateway,
+		PushJob:                 *pushJob,
+		PushGrouping:            *pushGrouping,
+		Oneshot:                 *oneshot,
+		ArtiScrapeURI:           *artiScrapeURI,
+		Credentials:             &credentials,
+		ArtiSSLVerify:           *artiSSLVerify,
+		MinTLSVersion:           minTLSVersion,
+		CAFile:                  *artiCAFile,
+		CACertPEM:               *artiCACertPEM,
+		UserAgent:               *artiUserAgent,
+		ArtiTimeout:             *artiTimeout,
+		ArtiMaxPages:            *artiMaxPages,
+		MaxConcurrentRequests:   *artiMaxConcurrent,
+		ArtiNoFollowRedirects:   !*artiFollowRedirects,
+		HTTPTrace:               *artiHTTPTrace,
+		CustomAuthHeader:        customAuthHeader,
+		StrictJSON:              *artiStrictJSON,
+		SaaS:                    *artiSaaS,
+		UseCache:                *useCache,
+		CacheTimeout:            *cacheTimeout,
+		CacheTTL:                *cacheTTL,
+		SingleFlightScrapes:     *singleFlight,
+		ScrapeMultipliers:       multipliers,
+		ScrapeDurationBuckets:   *scrapeDurationBuckets,
+		CircuitBreakerThreshold: *circuitThreshold,
+		CircuitBreakerCooldown:  *circuitCooldown,
+		MaxRetries:              *retryMax,
+		SubsystemRetries:        subsystemRetries,
+		ExporterRuntimeConfig:   &exporterRuntimeConfig,
+		AccessFederationTarget:  *accessFederationTarget,
+		DockerRepos:             *dockerRepos,
+		NativeMetrics:           *nativeMetrics,
+		CleanupRepos:            *cleanupRepos,
+		PackageRepos:            packageRepos,
+		CleanupMaxResults:       *cleanupMaxResults,
+		ChecksumMaxResults:      *checksumMaxResults,
+		RecentMaxResults:        *recentMaxResults,
+		DockerConcurrency:       *dockerConcurrency,
+		CustomAQLQueries:        *customAQL,
+		CustomAQLConcurrency:    *customAQLConcurrency,
+		CustomAQLMaxResults:     *customAQLMaxResults,
+		StorageCalculate:        *storageCalculate,
+		StorageCalculateTimeout: *storageCalcTimeout,
+		FederationPerNode:       *federationPerNode,
+		FederationTimeout:       newFederationTimeout(*federationTimeout, *artiTimeout),
+		Validate:                *validate,
+		LogLevelEndpoint:        *logLevelEndpoint,
+		FederationDebugEndpoint: *federationDebug,
+		LogLevel:                logLevel,
+		Logger:                  logger,
+		logConfig:               logConfig,
+	}
+	if _, err := conf.RootCAs(); err != nil {
+		return nil, err
+	}
+	return conf, nil
 
 }
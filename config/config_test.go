@@ -1,8 +1,14 @@
 package config
 
 import (
+	"encoding/pem"
 	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
@@ -369,6 +375,27 @@ func TestOptionalMetricsList(t *testing.T) {
 		"open_metrics",
 		"access_federation_validate",
 		"background_tasks",
+		"permission_target_repos",
+		"docker",
+		"artifacts_recent",
+		"backups",
+		"access_tokens",
+		"federation_mirror_lags",
+		"federation_unavailable_mirrors",
+		"system_info",
+		"remote_repos",
+		"garbage_collection",
+		"repo_layouts",
+		"access_federation_servers",
+		"native_metrics",
+		"cleanup_eligible",
+		"storage_used_delta",
+		"pypi_packages",
+		"virtual_repos",
+		"release_bundles",
+		"repo_file_count_delta",
+		"missing_checksums",
+		"config_descriptor",
 	}
 
 	if len(optionalMetricsList) != len(expectedMetrics) {
@@ -463,6 +490,245 @@ func TestOptionalMetricsStruct(t *testing.T) {
 	}
 }
 
+func TestMetricsNamespaceValidation(t *testing.T) {
+	tests := []struct {
+		namespace string
+		valid     bool
+	}{
+		{namespace: "artifactory", valid: true},
+		{namespace: "jfrog_artifactory", valid: true},
+		{namespace: "_private", valid: true},
+		{namespace: "", valid: false},
+		{namespace: "1artifactory", valid: false},
+		{namespace: "jfrog-artifactory", valid: false},
+		{namespace: "jfrog artifactory", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			if valid := reMetricsNamespace.MatchString(tt.namespace); valid != tt.valid {
+				t.Errorf("reMetricsNamespace.MatchString(%q) = %v, want %v", tt.namespace, valid, tt.valid)
+			}
+		})
+	}
+}
+
+func TestOptionalMetricsEnabled(t *testing.T) {
+	if enabled := (OptionalMetrics{}).Enabled(); len(enabled) != 0 {
+		t.Errorf("Enabled() = %v, want no metrics", enabled)
+	}
+
+	opt := OptionalMetrics{
+		Artifacts:       true,
+		OpenMetrics:     true,
+		BackgroundTasks: true,
+	}
+	expected := []string{"artifacts", "open_metrics", "background_tasks"}
+	enabled := opt.Enabled()
+	if len(enabled) != len(expected) {
+		t.Fatalf("Enabled() = %v, want %v", enabled, expected)
+	}
+	for i, metric := range expected {
+		if enabled[i] != metric {
+			t.Errorf("Enabled()[%d] = %s, want %s", i, enabled[i], metric)
+		}
+	}
+}
+
+func TestNewOptionalMetricsFederation(t *testing.T) {
+	tests := []struct {
+		name              string
+		metrics           []string
+		expectLags        bool
+		expectUnavailable bool
+	}{
+		{
+			name:    "No federation metrics",
+			metrics: []string{"artifacts"},
+		},
+		{
+			name:              "Federation status enables both",
+			metrics:           []string{"federation_status"},
+			expectLags:        true,
+			expectUnavailable: true,
+		},
+		{
+			name:       "Mirror lags only",
+			metrics:    []string{"federation_mirror_lags"},
+			expectLags: true,
+		},
+		{
+			name:              "Unavailable mirrors only",
+			metrics:           []string{"federation_unavailable_mirrors"},
+			expectUnavailable: true,
+		},
+		{
+			name:              "Mirror lags and unavailable mirrors",
+			metrics:           []string{"federation_mirror_lags", "federation_unavailable_mirrors"},
+			expectLags:        true,
+			expectUnavailable: true,
+		},
+		{
+			name:              "Federation status and a granular metric",
+			metrics:           []string{"federation_status", "federation_mirror_lags"},
+			expectLags:        true,
+			expectUnavailable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt, err := newOptionalMetrics(tt.metrics)
+			if err != nil {
+				t.Fatalf("newOptionalMetrics() error = %v", err)
+			}
+			if opt.MirrorLags() != tt.expectLags {
+				t.Errorf("MirrorLags() = %v, want %v", opt.MirrorLags(), tt.expectLags)
+			}
+			if opt.UnavailableMirrors() != tt.expectUnavailable {
+				t.Errorf("UnavailableMirrors() = %v, want %v", opt.UnavailableMirrors(), tt.expectUnavailable)
+			}
+			if opt.Federation() != (tt.expectLags || tt.expectUnavailable) {
+				t.Errorf("Federation() = %v, want %v", opt.Federation(), tt.expectLags || tt.expectUnavailable)
+			}
+		})
+	}
+}
+
+func TestOptionalMetricsFederationStatus(t *testing.T) {
+	opt := OptionalMetrics{FederationStatus: true}
+	if !opt.Federation() || !opt.MirrorLags() || !opt.UnavailableMirrors() {
+		t.Errorf("Federation(), MirrorLags(), UnavailableMirrors() = %v, %v, %v with FederationStatus, want all true", opt.Federation(), opt.MirrorLags(), opt.UnavailableMirrors())
+	}
+	if enabled := opt.Enabled(); len(enabled) != 1 || enabled[0] != "federation_status" {
+		t.Errorf("Enabled() = %v, want [federation_status]", enabled)
+	}
+}
+
+func TestSetAuthMethod(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials Credentials
+		anonymous   bool
+		expected    string
+		expectError bool
+	}{
+		{
+			name:        "Username and password",
+			credentials: Credentials{Username: "user", Password: "pass"},
+			expected:    "userPass",
+		},
+		{
+			name:        "Access token",
+			credentials: Credentials{AccessToken: "token"},
+			expected:    "accessToken",
+		},
+		{
+			name:        "API key",
+			credentials: Credentials{APIKey: "key"},
+			expected:    "apiKey",
+		},
+		{
+			name:        "No credentials",
+			expectError: true,
+		},
+		{
+			name:        "Username without password",
+			credentials: Credentials{Username: "user"},
+			expectError: true,
+		},
+		{
+			name:        "API key and access token",
+			credentials: Credentials{AccessToken: "token", APIKey: "key"},
+			expectError: true,
+		},
+		{
+			name:        "API key and username and password",
+			credentials: Credentials{Username: "user", Password: "pass", APIKey: "key"},
+			expectError: true,
+		},
+		{
+			name:      "Anonymous",
+			anonymous: true,
+			expected:  "anonymous",
+		},
+		{
+			name:        "Anonymous with access token",
+			credentials: Credentials{AccessToken: "token"},
+			anonymous:   true,
+			expectError: true,
+		},
+		{
+			name:        "Anonymous with username",
+			credentials: Credentials{Username: "user"},
+			anonymous:   true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.credentials.setAuthMethod(tt.anonymous)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got auth method %s", tt.credentials.AuthMethod)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("setAuthMethod() error = %v", err)
+			}
+			if tt.credentials.AuthMethod != tt.expected {
+				t.Errorf("AuthMethod = %s, want %s", tt.credentials.AuthMethod, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewRepoDrift(t *testing.T) {
+	t.Run("Valid expectations", func(t *testing.T) {
+		drift, err := newRepoDrift(
+			map[string]string{"libs-release": "LOCAL", "npm-remote": "remote"},
+			map[string]string{"libs-release": "Maven", "docker-local": "docker"},
+		)
+		if err != nil {
+			t.Fatalf("newRepoDrift() error = %v", err)
+		}
+		if drift.Types["libs-release"] != "local" {
+			t.Errorf("Types[libs-release] = %s, want local", drift.Types["libs-release"])
+		}
+		if drift.PackageTypes["libs-release"] != "maven" {
+			t.Errorf("PackageTypes[libs-release] = %s, want maven", drift.PackageTypes["libs-release"])
+		}
+		expectedRepos := []string{"docker-local", "libs-release", "npm-remote"}
+		if repos := drift.Repos(); !slices.Equal(repos, expectedRepos) {
+			t.Errorf("Repos() = %v, want %v", repos, expectedRepos)
+		}
+	})
+
+	t.Run("Invalid type", func(t *testing.T) {
+		if _, err := newRepoDrift(map[string]string{"libs-release": "hosted"}, nil); err == nil {
+			t.Error("Expected error but got none")
+		}
+	})
+
+	t.Run("Empty package type", func(t *testing.T) {
+		if _, err := newRepoDrift(nil, map[string]string{"libs-release": ""}); err == nil {
+			t.Error("Expected error but got none")
+		}
+	})
+
+	t.Run("No expectations", func(t *testing.T) {
+		drift, err := newRepoDrift(nil, nil)
+		if err != nil {
+			t.Fatalf("newRepoDrift() error = %v", err)
+		}
+		if repos := drift.Repos(); len(repos) != 0 {
+			t.Errorf("Repos() = %v, want none", repos)
+		}
+	})
+}
+
 // Test environment variable processing
 func TestEnvconfigProcessing(t *testing.T) {
 	tests := []struct {
@@ -597,3 +863,429 @@ func TestConfigurationEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestRootCAs(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(caPEM), 0o600); err != nil {
+		t.Fatalf("Writing CA file error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		caFile      string
+		caCertPEM   string
+		expectPool  bool
+		expectError bool
+	}{
+		{
+			name: "No custom CAs",
+		},
+		{
+			name:       "Inline PEM",
+			caCertPEM:  caPEM,
+			expectPool: true,
+		},
+		{
+			name:       "CA file",
+			caFile:     caFile,
+			expectPool: true,
+		},
+		{
+			name:       "CA file and inline PEM",
+			caFile:     caFile,
+			caCertPEM:  caPEM,
+			expectPool: true,
+		},
+		{
+			name:        "Invalid inline PEM",
+			caCertPEM:   "not a certificate",
+			expectError: true,
+		},
+		{
+			name:        "Missing CA file",
+			caFile:      filepath.Join(t.TempDir(), "missing.pem"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &Config{CAFile: tt.caFile, CACertPEM: tt.caCertPEM}
+			pool, err := conf.RootCAs()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RootCAs() error = %v", err)
+			}
+			if (pool != nil) != tt.expectPool {
+				t.Errorf("RootCAs() returned pool = %v, want pool = %v", pool != nil, tt.expectPool)
+			}
+		})
+	}
+}
+
+func TestParseScrapeMultipliers(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		expected    map[string]int
+		expectError bool
+	}{
+		{
+			name:     "No multipliers",
+			flags:    map[string]string{},
+			expected: map[string]int{},
+		},
+		{
+			name:     "Valid multipliers",
+			flags:    map[string]string{"artifacts": "5", "docker": "10"},
+			expected: map[string]int{"artifacts": 5, "docker": 10},
+		},
+		{
+			name:        "Unknown subsystem",
+			flags:       map[string]string{"users": "5"},
+			expectError: true,
+		},
+		{
+			name:        "Zero multiplier",
+			flags:       map[string]string{"storage": "0"},
+			expectError: true,
+		},
+		{
+			name:        "Not a number",
+			flags:       map[string]string{"storage": "often"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			multipliers, err := parseScrapeMultipliers(tt.flags)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseScrapeMultipliers() error = %v", err)
+			}
+			if len(multipliers) != len(tt.expected) {
+				t.Fatalf("parseScrapeMultipliers() = %v, want %v", multipliers, tt.expected)
+			}
+			for subsystem, expected := range tt.expected {
+				if multipliers[subsystem] != expected {
+					t.Errorf("Multiplier of %s = %d, want %d", subsystem, multipliers[subsystem], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSubsystemRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		expected    map[string]int
+		expectError bool
+	}{
+		{
+			name:     "No retries",
+			flags:    map[string]string{},
+			expected: map[string]int{},
+		},
+		{
+			name:     "Valid retries",
+			flags:    map[string]string{"ping": "5", "aql": "0"},
+			expected: map[string]int{"ping": 5, "aql": 0},
+		},
+		{
+			name:        "Unknown subsystem",
+			flags:       map[string]string{"users": "5"},
+			expectError: true,
+		},
+		{
+			name:        "Negative retries",
+			flags:       map[string]string{"ping": "-1"},
+			expectError: true,
+		},
+		{
+			name:        "Not a number",
+			flags:       map[string]string{"ping": "often"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retries, err := parseSubsystemRetries(tt.flags)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSubsystemRetries() error = %v", err)
+			}
+			if len(retries) != len(tt.expected) {
+				t.Fatalf("parseSubsystemRetries() = %v, want %v", retries, tt.expected)
+			}
+			for subsystem, expected := range tt.expected {
+				if n, ok := retries[subsystem]; !ok || n != expected {
+					t.Errorf("Retries of %s = %d, want %d", subsystem, n, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestRepoFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist string
+		denylist  string
+		matches   map[string]bool
+	}{
+		{
+			name:    "No filter",
+			matches: map[string]bool{"libs-release": true, "docker-local": true},
+		},
+		{
+			name:      "Allowlist matches whole key",
+			allowlist: "libs-.*",
+			matches:   map[string]bool{"libs-release": true, "docker-local": false, "old-libs-release": false},
+		},
+		{
+			name:      "Denylist overrides allowlist",
+			allowlist: "libs-.*|docker-.*",
+			denylist:  ".*-snapshot",
+			matches:   map[string]bool{"libs-release": true, "libs-snapshot": false, "docker-local": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowlist, err := compileRepoRegexp("repo-label.allowlist", tt.allowlist)
+			if err != nil {
+				t.Fatalf("compileRepoRegexp() error = %v", err)
+			}
+			denylist, err := compileRepoRegexp("repo-label.denylist", tt.denylist)
+			if err != nil {
+				t.Fatalf("compileRepoRegexp() error = %v", err)
+			}
+			filter := RepoFilter{Allowlist: allowlist, Denylist: denylist}
+			for repo, expected := range tt.matches {
+				if got := filter.Matches(repo); got != expected {
+					t.Errorf("Matches(%q) = %v, want %v", repo, got, expected)
+				}
+			}
+		})
+	}
+
+	if _, err := compileRepoRegexp("repo-label.allowlist", "libs-("); err == nil {
+		t.Error("Expected error for invalid regular expression but got none")
+	}
+}
+
+func TestParseMetricsPaths(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "Single path",
+			value:    "/metrics",
+			expected: []string{"/metrics"},
+		},
+		{
+			name:     "Multiple paths",
+			value:    "/metrics, /artifactory/metrics",
+			expected: []string{"/metrics", "/artifactory/metrics"},
+		},
+		{
+			name:        "Relative path",
+			value:       "/metrics,metrics",
+			expectError: true,
+		},
+		{
+			name:        "Empty path",
+			value:       "/metrics,",
+			expectError: true,
+		},
+		{
+			name:        "Duplicate path",
+			value:       "/metrics,/metrics",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := parseMetricsPaths(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMetricsPaths() error = %v", err)
+			}
+			if !slices.Equal(paths, tt.expected) {
+				t.Errorf("parseMetricsPaths() = %v, want %v", paths, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateBuckets(t *testing.T) {
+	tests := []struct {
+		name        string
+		buckets     []float64
+		expectError bool
+	}{
+		{
+			name:    "Ascending buckets",
+			buckets: []float64{0.1, 1, 10},
+		},
+		{
+			name:        "No buckets",
+			buckets:     nil,
+			expectError: true,
+		},
+		{
+			name:        "Unsorted buckets",
+			buckets:     []float64{1, 0.1},
+			expectError: true,
+		},
+		{
+			name:        "Duplicate buckets",
+			buckets:     []float64{1, 1},
+			expectError: true,
+		},
+		{
+			name:        "Negative bucket",
+			buckets:     []float64{-1, 1},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBuckets(tt.buckets)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("validateBuckets() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestNewFederationTimeout(t *testing.T) {
+	if got := newFederationTimeout(0, 5*time.Second); got != 5*time.Second {
+		t.Errorf("newFederationTimeout() without federation timeout = %s, want 5s", got)
+	}
+	if got := newFederationTimeout(30*time.Second, 5*time.Second); got != 30*time.Second {
+		t.Errorf("newFederationTimeout() with federation timeout = %s, want 30s", got)
+	}
+}
+
+func TestParseInstanceLabel(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    map[string]string
+		expectError bool
+	}{
+		{
+			name:  "Not set",
+			value: "",
+		},
+		{
+			name:     "Valid label",
+			value:    "instance_name=prod",
+			expected: map[string]string{"instance_name": "prod"},
+		},
+		{
+			name:     "Value containing =",
+			value:    "instance_name=a=b",
+			expected: map[string]string{"instance_name": "a=b"},
+		},
+		{
+			name:        "Missing value",
+			value:       "instance_name",
+			expectError: true,
+		},
+		{
+			name:        "Empty value",
+			value:       "instance_name=",
+			expectError: true,
+		},
+		{
+			name:        "Invalid name",
+			value:       "instance-name=prod",
+			expectError: true,
+		},
+		{
+			name:        "Reserved name",
+			value:       "__name__=prod",
+			expectError: true,
+		},
+		{
+			name:        "Label of the exporter",
+			value:       "node_id=prod",
+			expectError: true,
+		},
+		{
+			name:        "Repository label",
+			value:       "repo=prod",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, err := parseInstanceLabel(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseInstanceLabel() error = %v", err)
+			}
+			if !maps.Equal(labels, tt.expected) {
+				t.Errorf("parseInstanceLabel() = %v, want %v", labels, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateSaaSOptionalMetrics(t *testing.T) {
+	if err := validateSaaSOptionalMetrics(OptionalMetrics{Artifacts: true, ReplicationStatus: true}); err != nil {
+		t.Errorf("validateSaaSOptionalMetrics() error = %v", err)
+	}
+	for _, metric := range selfHostedOptionalMetrics {
+		optMetrics, err := newOptionalMetrics([]string{metric})
+		if err != nil {
+			t.Fatalf("newOptionalMetrics(%s) error = %v", metric, err)
+		}
+		if err := validateSaaSOptionalMetrics(optMetrics); err == nil {
+			t.Errorf("validateSaaSOptionalMetrics() with %s: expected error but got none", metric)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+	"gopkg.in/yaml.v3"
+)
+
+const configFileFlagName = "config.file"
+
+// fileCredentialKeys are the config file keys of the credentials, which are
+// otherwise only read from the environment.
+var fileCredentialKeys = []string{"artifactory.username", "artifactory.password", "artifactory.access-token", "artifactory.api-key"}
+
+// reEnvReference matches references to environment variables in string values
+// of the config file, either ${NAME} or ${NAME:-default}, and escaped dollar
+// signs ($$).
+var reEnvReference = regexp.MustCompile(`\$\$|\$\{([a-zA-Z_][a-zA-Z0-9_]*)(:-[^}]*)?\}`)
+
+// replacedDefaults are the original defaults of the flags whose defaults were
+// replaced by the values of the config file, restored before reloading it.
+var replacedDefaults = map[*kingpin.FlagClause][]string{}
+
+// cumulativeValue is implemented by flag values which can be passed multiple times.
+type cumulativeValue interface {
+	IsCumulative() bool
+}
+
+// applyConfigFile loads the YAML config file passed with the config.file flag
+// or CONFIG_FILE environment variable, if any, and sets its values as the
+// defaults of the flags of app. The keys of the file are the flag names, so
+// flags and environment variables set take precedence over the file. The
+// credentials of the file are returned, as they aren't flags.
+func applyConfigFile(app *kingpin.Application, args []string) (Credentials, error) {
+	var credentials Credentials
+	path := configFilePath(app, args)
+	if path == "" {
+		return credentials, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return credentials, fmt.Errorf("could not read config file: %w", err)
+	}
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return credentials, fmt.Errorf("could not parse config file %s: %w", path, err)
+	}
+
+	for key, value := range values {
+		if slices.Contains(fileCredentialKeys, key) {
+			s, ok := value.(string)
+			if !ok {
+				return credentials, fmt.Errorf("invalid value of key %q in config file %s: expected a string", key, path)
+			}
+			s, err := expandEnv(s)
+			if err != nil {
+				return credentials, fmt.Errorf("invalid value of key %q in config file %s: %w", key, path, err)
+			}
+			switch key {
+			case "artifactory.username":
+				credentials.Username = s
+			case "artifactory.password":
+				credentials.Password = s
+			case "artifactory.access-token":
+				credentials.AccessToken = s
+			case "artifactory.api-key":
+				credentials.APIKey = s
+			}
+			continue
+		}
+
+		flag := app.GetFlag(key)
+		if flag == nil || key == configFileFlagName || key == "help" || key == "version" {
+			return credentials, fmt.Errorf("unknown key %q in config file %s", key, path)
+		}
+		defaults, err := flagValues(value)
+		if err != nil {
+			return credentials, fmt.Errorf("invalid value of key %q in config file %s: %w", key, path, err)
+		}
+		if v, ok := flag.Model().Value.(cumulativeValue); (!ok || !v.IsCumulative()) && len(defaults) != 1 {
+			return credentials, fmt.Errorf("invalid value of key %q in config file %s: expected a single value", key, path)
+		}
+		if _, ok := replacedDefaults[flag]; !ok {
+			replacedDefaults[flag] = flag.Model().Default
+		}
+		flag.Default(defaults...)
+	}
+	return credentials, nil
+}
+
+// configFilePath returns the path of the config file passed in args or the
+// environment. Parse errors are ignored here and reported when parsing args.
+func configFilePath(app *kingpin.Application, args []string) string {
+	context, _ := app.ParseContext(args)
+	if context != nil {
+		for _, element := range context.Elements {
+			flag, ok := element.Clause.(*kingpin.FlagClause)
+			if ok && flag.Model().Name == configFileFlagName && element.Value != nil {
+				return *element.Value
+			}
+		}
+	}
+	if flag := app.GetFlag(configFileFlagName); flag != nil && flag.Model().Envar != "" {
+		return os.Getenv(flag.Model().Envar)
+	}
+	return ""
+}
+
+// flagValues converts a YAML value to flag values. Lists are converted to
+// multiple values and maps to key=value pairs.
+func flagValues(value any) ([]string, error) {
+	switch v := value.(type) {
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := flagValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	case map[string]any:
+		values := make([]string, 0, len(v))
+		for key, item := range v {
+			s, err := flagValue(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, key+"="+s)
+		}
+		slices.Sort(values)
+		return values, nil
+	default:
+		s, err := flagValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+	}
+}
+
+func flagValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return expandEnv(v)
+	case bool, int, float64:
+		return fmt.Sprint(v), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unsupported value %s", strings.TrimSpace(fmt.Sprint(v)))
+	}
+}
+
+// expandEnv replaces references to environment variables in s with their
+// values. ${NAME} fails if the variable isn't set, while ${NAME:-default}
+// falls back to the default, which may be empty. $$ is replaced with $.
+func expandEnv(s string) (string, error) {
+	var err error
+	expanded := reEnvReference.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$$" {
+			return "$"
+		}
+		match := reEnvReference.FindStringSubmatch(ref)
+		if value, ok := os.LookupEnv(match[1]); ok {
+			return value
+		}
+		if match[2] != "" {
+			return match[2][len(":-"):]
+		}
+		if err == nil {
+			err = fmt.Errorf("environment variable %s is not set", match[1])
+		}
+		return ref
+	})
+	return expanded, err
+}
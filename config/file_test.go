@@ -0,0 +1,178 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	tests := []struct {
+		name              string
+		file              string
+		env               map[string]string
+		args              []string
+		expectError       bool
+		expectedURI       string
+		expectedMetrics   []string
+		expectedMaxPages  int
+		expectedUsername  string
+		expectedMultiples map[string]string
+	}{
+		{
+			name: "Values from file",
+			file: `
+artifactory.scrape-uri: https://artifactory.example.com/artifactory
+artifactory.max-pages: 10
+optional-metric: [artifacts, docker]
+scrape-interval-multiplier:
+  artifacts: 5
+artifactory.username: exporter
+artifactory.password: secret
+`,
+			expectedURI:       "https://artifactory.example.com/artifactory",
+			expectedMetrics:   []string{"artifacts", "docker"},
+			expectedMaxPages:  10,
+			expectedUsername:  "exporter",
+			expectedMultiples: map[string]string{"artifacts": "5"},
+		},
+		{
+			name:              "Flags override file",
+			file:              "artifactory.scrape-uri: https://artifactory.example.com/artifactory\nartifactory.max-pages: 10\n",
+			args:              []string{"--artifactory.max-pages=20"},
+			expectedURI:       "https://artifactory.example.com/artifactory",
+			expectedMaxPages:  20,
+			expectedMultiples: map[string]string{},
+		},
+		{
+			name: "Environment variables expanded",
+			file: `
+artifactory.scrape-uri: https://${ARTI_TEST_HOST}/artifactory
+artifactory.max-pages: ${ARTI_TEST_MAX_PAGES:-10}
+artifactory.username: ${ARTI_TEST_USER}
+`,
+			env:               map[string]string{"ARTI_TEST_HOST": "artifactory.example.com", "ARTI_TEST_USER": "exporter"},
+			expectedURI:       "https://artifactory.example.com/artifactory",
+			expectedMaxPages:  10,
+			expectedUsername:  "exporter",
+			expectedMultiples: map[string]string{},
+		},
+		{
+			name:        "Environment variable not set",
+			file:        "artifactory.username: ${ARTI_TEST_UNSET_USER}\n",
+			expectError: true,
+		},
+		{
+			name:        "Unknown key",
+			file:        "artifactory.scrape-url: https://artifactory.example.com/artifactory\n",
+			expectError: true,
+		},
+		{
+			name:        "List for single value flag",
+			file:        "artifactory.scrape-uri: [a, b]\n",
+			expectError: true,
+		},
+		{
+			name:        "Invalid YAML",
+			file:        "artifactory.scrape-uri: [a\n",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			path := filepath.Join(t.TempDir(), "config.yml")
+			if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			app := kingpin.New("test", "")
+			app.Flag(configFileFlagName, "").String()
+			uri := app.Flag("artifactory.scrape-uri", "").Default("http://localhost:8081/artifactory").String()
+			maxPages := app.Flag("artifactory.max-pages", "").Default("100").Int()
+			metrics := app.Flag("optional-metric", "").Strings()
+			multipliers := app.Flag("scrape-interval-multiplier", "").StringMap()
+
+			args := append([]string{"--" + configFileFlagName + "=" + path}, tt.args...)
+			credentials, err := applyConfigFile(app, args)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyConfigFile() error = %v", err)
+			}
+			if _, err := app.Parse(args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if *uri != tt.expectedURI {
+				t.Errorf("artifactory.scrape-uri = %s, want %s", *uri, tt.expectedURI)
+			}
+			if *maxPages != tt.expectedMaxPages {
+				t.Errorf("artifactory.max-pages = %d, want %d", *maxPages, tt.expectedMaxPages)
+			}
+			if len(*metrics) != len(tt.expectedMetrics) {
+				t.Errorf("optional-metric = %v, want %v", *metrics, tt.expectedMetrics)
+			}
+			if len(*multipliers) != len(tt.expectedMultiples) {
+				t.Errorf("scrape-interval-multiplier = %v, want %v", *multipliers, tt.expectedMultiples)
+			}
+			for subsystem, expected := range tt.expectedMultiples {
+				if (*multipliers)[subsystem] != expected {
+					t.Errorf("scrape-interval-multiplier[%s] = %s, want %s", subsystem, (*multipliers)[subsystem], expected)
+				}
+			}
+			if credentials.Username != tt.expectedUsername {
+				t.Errorf("Username = %s, want %s", credentials.Username, tt.expectedUsername)
+			}
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("ARTI_TEST_TOKEN", "secret")
+	t.Setenv("ARTI_TEST_EMPTY", "")
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    string
+		expectError bool
+	}{
+		{name: "No reference", value: "plain", expected: "plain"},
+		{name: "Set variable", value: "Bearer ${ARTI_TEST_TOKEN}", expected: "Bearer secret"},
+		{name: "Empty variable", value: "${ARTI_TEST_EMPTY}", expected: ""},
+		{name: "Default of unset variable", value: "${ARTI_TEST_UNSET:-fallback}", expected: "fallback"},
+		{name: "Empty default of unset variable", value: "${ARTI_TEST_UNSET:-}", expected: ""},
+		{name: "Default of set variable", value: "${ARTI_TEST_TOKEN:-fallback}", expected: "secret"},
+		{name: "Escaped dollar", value: "$${ARTI_TEST_TOKEN}", expected: "${ARTI_TEST_TOKEN}"},
+		{name: "AQL operator", value: `items.find({"name": {"$match": "*.jar"}})`, expected: `items.find({"name": {"$match": "*.jar"}})`},
+		{name: "Unset variable", value: "${ARTI_TEST_UNSET}", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, err := expandEnv(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandEnv() error = %v", err)
+			}
+			if expanded != tt.expected {
+				t.Errorf("expandEnv() = %q, want %q", expanded, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"time"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// restartFields are the fields of Config which only apply on startup, keyed
+// by field name, with the setting they are configured with. Reload keeps
+// their values.
+var restartFields = map[string]string{
+	"ListenAddress":           "web.listen-address",
+	"HealthListenAddress":     "web.health-listen-address",
+	"MetricsPaths":            "web.telemetry-path",
+	"ShutdownTimeout":         "web.shutdown-timeout",
+	"LogLevelEndpoint":        "web.enable-log-level-endpoint",
+	"FederationDebugEndpoint": "web.enable-federation-debug-endpoint",
+	"DisableDefaultMetrics":   "web.disable-default-metrics",
+	"MetricsNamespace":        "metrics-namespace",
+	"InstanceLabel":           "instance-label",
+	"GraphiteAddress":         "graphite.address",
+	"GraphiteInterval":        "graphite.interval",
+	"GraphitePrefix":          "graphite.prefix",
+	"PushGateway":             "push.gateway",
+	"PushJob":                 "push.job",
+	"PushGrouping":            "push.grouping",
+	"Oneshot":                 "oneshot",
+	"Validate":                "validate",
+	"Credentials":             "credentials",
+	"CustomAuthHeader":        "artifactory.auth-header",
+}
+
+// Reload parses the flags, environment and config file again and returns the
+// new config. Changes of the fields in restartFields and of the log settings
+// are ignored with a warning, as they require a restart.
+func (c *Config) Reload() (*Config, error) {
+	resetFlags(kingpin.CommandLine)
+	fileCredentials, err := applyConfigFile(kingpin.CommandLine, os.Args[1:])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := kingpin.CommandLine.Parse(os.Args[1:]); err != nil {
+		return nil, err
+	}
+	conf, err := newConfig(fileCredentials)
+	if err != nil {
+		return nil, err
+	}
+	c.keepRestartFields(conf)
+	return conf, nil
+}
+
+// keepRestartFields sets the fields of conf which require a restart to their
+// values in c, logging a warning for every changed setting.
+func (c *Config) keepRestartFields(conf *Config) {
+	current := reflect.ValueOf(c).Elem()
+	reloaded := reflect.ValueOf(conf).Elem()
+	for field, setting := range restartFields {
+		value := current.FieldByName(field)
+		if !reflect.DeepEqual(value.Interface(), reloaded.FieldByName(field).Interface()) {
+			c.Logger.Warn(
+				"Ignoring changed setting on reload, it requires a restart",
+				"setting", setting,
+			)
+		}
+		reloaded.FieldByName(field).Set(value)
+	}
+	// The sensitive headers follow the custom auth header, whose change is
+	// already warned about.
+	currentLog, reloadedLog := c.logConfig, conf.logConfig
+	currentLog.SensitiveHeaders, reloadedLog.SensitiveHeaders = nil, nil
+	if !reflect.DeepEqual(currentLog, reloadedLog) {
+		c.Logger.Warn(
+			"Ignoring changed setting on reload, it requires a restart",
+			"setting", "log.*",
+		)
+	}
+	conf.logConfig = c.logConfig
+	conf.LogLevel = c.LogLevel
+	conf.Logger = c.Logger
+}
+
+// resetFlags restores the defaults of the flags of app replaced by the config
+// file and clears the values of all flags, so parsing them again doesn't keep
+// values which were removed. Repeatable flags would accumulate their values
+// of every parse otherwise.
+func resetFlags(app *kingpin.Application) {
+	for flag, defaults := range replacedDefaults {
+		flag.Default(defaults...)
+	}
+	for _, flag := range app.Model().Flags {
+		getter, ok := flag.Value.(kingpin.Getter)
+		if !ok {
+			continue
+		}
+		// Values which can't be cleared, e.g. of enums, always have a default.
+		switch v := getter.Get().(type) {
+		case map[string]string:
+			clear(v)
+		case string:
+			flag.Value.Set("")
+		case bool:
+			flag.Value.Set("false")
+		case int, int64, uint, uint64, float64:
+			flag.Value.Set("0")
+		case time.Duration:
+			flag.Value.Set("0s")
+		default:
+			if p := reflect.ValueOf(v); p.Kind() == reflect.Pointer && p.Elem().Kind() == reflect.Slice {
+				p.Elem().SetZero()
+			}
+		}
+	}
+}
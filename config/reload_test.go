@@ -0,0 +1,105 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestResetFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	writeFile := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	app := kingpin.New("test", "")
+	app.Flag(configFileFlagName, "").String()
+	uri := app.Flag("artifactory.scrape-uri", "").Default("http://localhost:8081/artifactory").String()
+	target := app.Flag("access-federation-target", "").String()
+	metrics := app.Flag("optional-metric", "").Strings()
+	multipliers := app.Flag("scrape-interval-multiplier", "").StringMap()
+	args := []string{"--" + configFileFlagName + "=" + path, "--optional-metric=docker"}
+	parse := func() {
+		t.Helper()
+		if _, err := applyConfigFile(app, args); err != nil {
+			t.Fatalf("applyConfigFile() error = %v", err)
+		}
+		if _, err := app.Parse(args); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+	}
+
+	writeFile(`
+artifactory.scrape-uri: https://artifactory.example.com/artifactory
+access-federation-target: https://target.example.com
+scrape-interval-multiplier:
+  artifacts: 5
+  docker: 2
+`)
+	parse()
+	writeFile(`
+scrape-interval-multiplier:
+  docker: 3
+`)
+	resetFlags(app)
+	parse()
+
+	if *uri != "http://localhost:8081/artifactory" {
+		t.Errorf("artifactory.scrape-uri = %s, want the default after it was removed from the file", *uri)
+	}
+	if *target != "" {
+		t.Errorf("access-federation-target = %s, want empty after it was removed from the file", *target)
+	}
+	if !slices.Equal(*metrics, []string{"docker"}) {
+		t.Errorf("optional-metric = %v, want [docker]", *metrics)
+	}
+	if len(*multipliers) != 1 || (*multipliers)["docker"] != "3" {
+		t.Errorf("scrape-interval-multiplier = %v, want map[docker:3]", *multipliers)
+	}
+}
+
+func TestKeepRestartFields(t *testing.T) {
+	var logs bytes.Buffer
+	current := &Config{
+		ListenAddress:    ":9531",
+		MetricsNamespace: "artifactory",
+		ArtiScrapeURI:    "http://localhost:8081/artifactory",
+		Credentials:      &Credentials{AuthMethod: "accessToken", AccessToken: "token"},
+		LogLevel:         new(slog.LevelVar),
+		Logger:           slog.New(slog.NewTextHandler(&logs, nil)),
+	}
+	reloaded := &Config{
+		ListenAddress:    ":9999",
+		MetricsNamespace: "artifactory",
+		ArtiScrapeURI:    "https://artifactory.example.com/artifactory",
+		Credentials:      &Credentials{AuthMethod: "accessToken", AccessToken: "token"},
+		LogLevel:         new(slog.LevelVar),
+		Logger:           slog.New(slog.NewTextHandler(&logs, nil)),
+	}
+
+	current.keepRestartFields(reloaded)
+
+	if reloaded.ListenAddress != ":9531" {
+		t.Errorf("ListenAddress = %s, want the current :9531", reloaded.ListenAddress)
+	}
+	if reloaded.ArtiScrapeURI != "https://artifactory.example.com/artifactory" {
+		t.Errorf("ArtiScrapeURI = %s, want the reloaded value", reloaded.ArtiScrapeURI)
+	}
+	if reloaded.Logger != current.Logger || reloaded.LogLevel != current.LogLevel {
+		t.Error("Reloaded config doesn't keep the current logger")
+	}
+	if !strings.Contains(logs.String(), "setting=web.listen-address") {
+		t.Errorf("No warning about the changed listen address logged:\n%s", logs.String())
+	}
+	if strings.Count(logs.String(), "level=WARN") != 1 {
+		t.Errorf("Expected a single warning, got:\n%s", logs.String())
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+	"github.com/peimanja/artifactory_exporter/collector"
+)
+
+// federationDebugNode holds what a node returned for the federation status
+// endpoints. NodeId is the queried HA node and empty for the scrape URI.
+type federationDebugNode struct {
+	NodeId                  string                          `json:"nodeId,omitempty"`
+	MirrorLags              *artifactory.MirrorLags         `json:"mirrorLags,omitempty"`
+	MirrorLagsError         string                          `json:"mirrorLagsError,omitempty"`
+	UnavailableMirrors      *artifactory.UnavailableMirrors `json:"unavailableMirrors,omitempty"`
+	UnavailableMirrorsError string                          `json:"unavailableMirrorsError,omitempty"`
+}
+
+// fetchFederationDebugNode fetches the mirror lags and unavailable mirrors
+// from client, keeping the errors next to the results.
+func fetchFederationDebugNode(client *artifactory.Client, nodeId string) federationDebugNode {
+	node := federationDebugNode{NodeId: nodeId}
+	if mirrorLags, err := client.FetchMirrorLags(); err != nil {
+		node.MirrorLagsError = err.Error()
+	} else {
+		node.MirrorLags = &mirrorLags
+	}
+	if unavailableMirrors, err := client.FetchUnavailableMirrors(); err != nil {
+		node.UnavailableMirrorsError = err.Error()
+	} else {
+		node.UnavailableMirrors = &unavailableMirrors
+	}
+	return node
+}
+
+// federationDebugHandler returns the parsed responses of the federation
+// status endpoints as JSON on GET, for debugging the federation metrics. The
+// requests are sent with the client of the current exporter, so they're
+// limited like the scrapes and follow config reloads. In per-node mode every
+// HA node is queried, like for the metrics.
+func federationDebugHandler(exporter func() *collector.Exporter, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		log.Debug(
+			"Fetching federation status for debugging",
+			"remote", r.RemoteAddr,
+		)
+
+		current := exporter()
+		client := current.Client()
+		var nodes []federationDebugNode
+		var nodeURLs map[string]string
+		if current.FederationPerNode() {
+			var err error
+			if nodeURLs, err = client.FetchHANodeURLs(); err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+		}
+		if len(nodeURLs) == 0 {
+			nodes = append(nodes, fetchFederationDebugNode(client, ""))
+		}
+		for _, nodeId := range slices.Sorted(maps.Keys(nodeURLs)) {
+			nodes = append(nodes, fetchFederationDebugNode(client.NodeClient(nodeURLs[nodeId]), nodeId))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(nodes); err != nil {
+			log.Error(
+				"Error writing federation debug response",
+				"err", err.Error(),
+			)
+		}
+	}
+}
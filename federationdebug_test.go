@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/peimanja/artifactory_exporter/collector"
+	"github.com/peimanja/artifactory_exporter/config"
+	"github.com/peimanja/artifactory_exporter/logger"
+)
+
+func TestFederationDebugHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "node-1")
+		switch r.URL.Path {
+		case "/artifactory/api/federation/status/mirrorsLag":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"errors":[{"status":500,"message":"Internal Server Error"}]}`))
+		case "/artifactory/api/federation/status/unavailableMirrors":
+			w.Write([]byte(`{"unavailableMirrors": [{"repoKey": "fed-local", "status": "UNAVAILABLE", "localRepoKey": "fed-local", "remoteUrl": "https://eu.example.com/artifactory/fed-local", "remoteRepoKey": "fed-local"}]}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	log := logger.New(logger.Config{Level: "info"})
+	exporter, err := collector.NewExporter(&config.Config{
+		ArtiScrapeURI:         server.URL + "/artifactory",
+		ArtiTimeout:           5 * time.Second,
+		MetricsNamespace:      "artifactory",
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: log,
+	})
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+	handler := federationDebugHandler(func() *collector.Exporter { return exporter }, log)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/federation", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var nodes []federationDebugNode
+	if err := json.NewDecoder(rec.Body).Decode(&nodes); err != nil {
+		t.Fatalf("Decoding response error = %v", err)
+	}
+	if len(nodes) != 1 {
+		t.Fatalf("Response has %d nodes, want 1", len(nodes))
+	}
+	node := nodes[0]
+	if node.MirrorLags != nil || node.MirrorLagsError == "" {
+		t.Errorf("MirrorLags = %+v, error = %q, want the error of the failed request", node.MirrorLags, node.MirrorLagsError)
+	}
+	unavailable := node.UnavailableMirrors
+	if unavailable == nil || len(unavailable.UnavailableMirrors) != 1 || unavailable.UnavailableMirrors[0].RepoKey != "fed-local" || unavailable.UnavailableMirrors[0].NodeId != "node-1" {
+		t.Errorf("UnavailableMirrors = %+v, want fed-local reported by node-1", unavailable)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/federation", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Status of POST = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
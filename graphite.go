@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/graphite"
+)
+
+// graphiteLogger adapts slog to the logger of the Graphite bridge.
+type graphiteLogger struct {
+	logger *slog.Logger
+}
+
+func (l graphiteLogger) Println(v ...any) {
+	l.logger.Warn(
+		"Error pushing metrics to Graphite",
+		"err", fmt.Sprint(v...),
+	)
+}
+
+// newGraphiteBridge returns a bridge pushing the metrics gathered by gatherer
+// to the Graphite server at address every interval. Errors of single metrics
+// are logged and don't abort the push of the others.
+func newGraphiteBridge(address string, prefix string, interval time.Duration, gatherer prometheus.Gatherer, logger *slog.Logger) (*graphite.Bridge, error) {
+	return graphite.NewBridge(&graphite.Config{
+		URL:           address,
+		Gatherer:      gatherer,
+		Prefix:        prefix,
+		Interval:      interval,
+		Timeout:       interval,
+		Logger:        graphiteLogger{logger: logger},
+		ErrorHandling: graphite.ContinueOnError,
+	})
+}
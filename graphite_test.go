@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	l "github.com/peimanja/artifactory_exporter/logger"
+)
+
+func TestGraphiteBridgePush(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer ln.Close()
+	lines := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			lines <- nil
+			return
+		}
+		defer conn.Close()
+		var received []string
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			received = append(received, scanner.Text())
+		}
+		lines <- received
+	}()
+
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "artifactory_storage_repo_used_bytes", Help: "Test gauge."}, []string{"name"})
+	gauge.WithLabelValues("libs-release").Set(1024)
+	registry.MustRegister(gauge)
+
+	bridge, err := newGraphiteBridge(ln.Addr().String(), "exporter", time.Minute, registry, l.New(l.EmptyConfig))
+	if err != nil {
+		t.Fatalf("Failed to create the Graphite bridge: %v", err)
+	}
+	if err := bridge.Push(); err != nil {
+		t.Fatalf("Failed to push: %v", err)
+	}
+
+	select {
+	case received := <-lines:
+		if len(received) != 1 {
+			t.Fatalf("Received %d lines, want 1: %v", len(received), received)
+		}
+		fields := strings.Fields(received[0])
+		if len(fields) != 3 {
+			t.Fatalf("Line %q isn't of the form <path> <value> <timestamp>", received[0])
+		}
+		if want := "exporter.artifactory_storage_repo_used_bytes.name.libs-release"; fields[0] != want {
+			t.Errorf("Pushed path %q, want %q", fields[0], want)
+		}
+		if fields[1] != "1024" {
+			t.Errorf("Pushed value %q, want 1024", fields[1])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for pushed metrics")
+	}
+}
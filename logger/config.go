@@ -5,6 +5,16 @@ import "log/slog"
 type Config struct {
 	Format string
 	Level  string
+	// LevelVar, if set, is initialised with Level and controls the level of
+	// the logger, so the level can be changed at runtime.
+	LevelVar *slog.LevelVar
+	// Redact masks credentials, e.g. tokens in query parameters of logged
+	// URLs and Authorization headers, before they are logged.
+	Redact bool
+	// SensitiveHeaders are HTTP headers carrying credentials, e.g. a custom
+	// auth header, whose values are redacted on top of the Authorization
+	// header.
+	SensitiveHeaders []string
 }
 
 const (
@@ -23,6 +33,10 @@ const (
 	LevelFlagHelp = "Only log messages with the given severity or above. One of: [debug, info, warn, error]"
 	LevelFlagName = "log.level"
 )
+const (
+	RedactFlagHelp = "Mask credentials, e.g. tokens in query parameters of URLs and Authorization headers, in log messages."
+	RedactFlagName = "log.redact"
+)
 
 var (
 	EmptyConfig      = Config{}
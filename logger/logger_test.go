@@ -2,6 +2,7 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
@@ -435,3 +436,35 @@ func TestLoggerEdgeCases(t *testing.T) {
 		logger.Info("test message")
 	})
 }
+
+func TestParseLevel(t *testing.T) {
+	for _, name := range LevelsAvailable {
+		lvl, err := ParseLevel(name)
+		if err != nil {
+			t.Errorf("ParseLevel(%q) error = %v", name, err)
+			continue
+		}
+		if LevelName(lvl) != name {
+			t.Errorf("LevelName(ParseLevel(%q)) = %q", name, LevelName(lvl))
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel(\"verbose\") expected error but got none")
+	}
+}
+
+func TestLevelVar(t *testing.T) {
+	levelVar := new(slog.LevelVar)
+	logger := New(Config{Level: "warn", LevelVar: levelVar})
+
+	if levelVar.Level() != slog.LevelWarn {
+		t.Errorf("LevelVar = %v, want %v", levelVar.Level(), slog.LevelWarn)
+	}
+	if logger.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Logger should not log info messages at warn level")
+	}
+	levelVar.Set(slog.LevelDebug)
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Logger should log debug messages after the level was changed to debug")
+	}
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// redacted replaces sensitive values in log messages.
+const redacted = "REDACTED"
+
+// sensitiveKeys are the attribute keys whose values are always redacted.
+var sensitiveKeys = []string{"authorization", "password", "access_token", "api_key", "x-jfrog-art-api"}
+
+// sensitiveHeaders are the HTTP headers whose values are redacted.
+var sensitiveHeaders = []string{"Authorization", "X-Jfrog-Art-Api", "Cookie", "Set-Cookie"}
+
+var (
+	// reSensitiveQueryParam matches the values of query parameters of URLs
+	// which may carry credentials, e.g. tokens or presigned signatures.
+	reSensitiveQueryParam = regexp.MustCompile(`(?i)([?&](?:access_token|token|api_key|apikey|password|secret|signature|x-amz-signature|x-amz-credential|x-amz-security-token)=)[^&\s"']*`)
+	// reCredentialsScheme matches the credentials of Authorization header values.
+	reCredentialsScheme = regexp.MustCompile(`(?i)\b(Bearer|Basic)\s+[A-Za-z0-9._~+/=-]+`)
+	// reURLUserinfo matches the password of credentials embedded in URLs.
+	reURLUserinfo = regexp.MustCompile(`(://[^/@:\s"']+:)[^/@\s"']+@`)
+)
+
+// redactHandler masks credentials in the message and attributes of log
+// records before passing them to the wrapped handler.
+type redactHandler struct {
+	slog.Handler
+	// headers are the HTTP headers whose values are redacted.
+	headers []string
+}
+
+// newRedactHandler returns a redactHandler wrapping h which additionally
+// redacts the values of headers, e.g. of a custom auth header.
+func newRedactHandler(h slog.Handler, headers []string) redactHandler {
+	return redactHandler{Handler: h, headers: append(slices.Clone(sensitiveHeaders), headers...)}
+}
+
+func (h redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	record := slog.NewRecord(r.Time, r.Level, redactString(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		record.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redactedAttrs[i] = h.redactAttr(a)
+	}
+	return redactHandler{h.Handler.WithAttrs(redactedAttrs), h.headers}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{h.Handler.WithGroup(name), h.headers}
+}
+
+// redactAttr masks the value of a with sensitive key or header name and
+// credentials found in its value.
+func (h redactHandler) redactAttr(a slog.Attr) slog.Attr {
+	if slices.Contains(sensitiveKeys, strings.ToLower(a.Key)) || slices.ContainsFunc(h.headers, func(name string) bool { return strings.EqualFold(name, a.Key) }) {
+		return slog.String(a.Key, redacted)
+	}
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redactString(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redactedGroup := make([]any, len(group))
+		for i, groupAttr := range group {
+			redactedGroup[i] = h.redactAttr(groupAttr)
+		}
+		return slog.Group(a.Key, redactedGroup...)
+	case slog.KindAny:
+		switch v := value.Any().(type) {
+		case error:
+			return slog.String(a.Key, redactString(v.Error()))
+		case http.Header:
+			header := v.Clone()
+			for _, name := range h.headers {
+				if header.Get(name) != "" {
+					header.Set(name, redacted)
+				}
+			}
+			return slog.Any(a.Key, header)
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: value}
+}
+
+// redactString masks sensitive query parameters, credentials embedded in URLs
+// and Authorization header values found in s.
+func redactString(s string) string {
+	s = reSensitiveQueryParam.ReplaceAllString(s, "${1}"+redacted)
+	s = reURLUserinfo.ReplaceAllString(s, "${1}"+redacted+"@")
+	return reCredentialsScheme.ReplaceAllString(s, "${1} "+redacted)
+}
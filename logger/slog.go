@@ -1,8 +1,10 @@
 package logger
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 )
 
 // New returns configured instance of `log/slog`
@@ -12,24 +14,28 @@ import (
 // values `FormatDefault` or `LevelDefault` will be assumed.
 func New(c Config) *slog.Logger {
 
-	lvl := lvlFromConfig(c)
+	var lvl slog.Leveler = lvlFromConfig(c)
+	if c.LevelVar != nil {
+		c.LevelVar.Set(lvlFromConfig(c))
+		lvl = c.LevelVar
+	}
 
+	var logger *slog.Logger
 	switch lf := fmtFromConfig(c); lf {
 	case fmtTXT:
-		return newTXTLogger(lvl)
+		logger = newTXTLogger(lvl)
 	case fmtJSON:
-		return newJSONLogger(lvl)
+		logger = newJSONLogger(lvl)
 	default:
 		l := newTXTLogger(lvl)
 		l.Error("We should never have ended up here! Plase report an issue")
 		os.Exit(1)
 	}
 
-	/*
-	 * The following should never happen and is only there
-	 * to satisfy the compiler's formal requirements.
-	 */
-	return newTXTLogger(lvl)
+	if c.Redact {
+		return slog.New(newRedactHandler(logger.Handler(), c.SensitiveHeaders))
+	}
+	return logger
 }
 
 // formatsAvailableMap provides O(1) lookup for format validation
@@ -58,7 +64,26 @@ func lvlFromConfig(c Config) slog.Level {
 	return levelsFlagToSlog[lvlFromFlag]
 }
 
-func newJSONLogger(l slog.Level) *slog.Logger {
+// ParseLevel returns the level with the given name. See LevelsAvailable.
+func ParseLevel(name string) (slog.Level, error) {
+	lvl, ok := levelsFlagToSlog[name]
+	if !ok {
+		return lvl, fmt.Errorf("unknown log level: %q. One of: %v", name, LevelsAvailable)
+	}
+	return lvl, nil
+}
+
+// LevelName returns the name of the given level as accepted by ParseLevel.
+func LevelName(lvl slog.Level) string {
+	for name, l := range levelsFlagToSlog {
+		if l == lvl {
+			return name
+		}
+	}
+	return strings.ToLower(lvl.String())
+}
+
+func newJSONLogger(l slog.Leveler) *slog.Logger {
 	h := slog.NewJSONHandler(
 		os.Stderr, // Please read the explanation in the `doc.go` file.
 		&slog.HandlerOptions{
@@ -68,7 +93,7 @@ func newJSONLogger(l slog.Level) *slog.Logger {
 	return slog.New(h)
 }
 
-func newTXTLogger(l slog.Level) *slog.Logger {
+func newTXTLogger(l slog.Leveler) *slog.Logger {
 	h := slog.NewTextHandler(
 		os.Stderr, // Please read the explanation in the `doc.go` file.
 		&slog.HandlerOptions{
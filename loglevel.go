@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/peimanja/artifactory_exporter/logger"
+)
+
+// logLevelHandler returns the current log level on GET and changes it to
+// the level given in the request body on PUT.
+func logLevelHandler(level *slog.LevelVar, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintln(w, logger.LevelName(level.Level()))
+		case http.MethodPut:
+			body, err := io.ReadAll(io.LimitReader(r.Body, 64))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			newLevel, err := logger.ParseLevel(strings.TrimSpace(string(body)))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Info(
+				"Changing log level",
+				"from", logger.LevelName(level.Level()),
+				"to", logger.LevelName(newLevel),
+				"remote", r.RemoteAddr,
+			)
+			level.Set(newLevel)
+			fmt.Fprintln(w, logger.LevelName(newLevel))
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/peimanja/artifactory_exporter/logger"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	level := new(slog.LevelVar)
+	log := logger.New(logger.Config{Level: "info", LevelVar: level})
+	handler := logLevelHandler(level, log)
+
+	tests := []struct {
+		name          string
+		method        string
+		body          string
+		expectedCode  int
+		expectedBody  string
+		expectedLevel slog.Level
+	}{
+		{
+			name:          "Get current level",
+			method:        http.MethodGet,
+			expectedCode:  http.StatusOK,
+			expectedBody:  "info",
+			expectedLevel: slog.LevelInfo,
+		},
+		{
+			name:          "Change level",
+			method:        http.MethodPut,
+			body:          "debug\n",
+			expectedCode:  http.StatusOK,
+			expectedBody:  "debug",
+			expectedLevel: slog.LevelDebug,
+		},
+		{
+			name:          "Invalid level",
+			method:        http.MethodPut,
+			body:          "verbose",
+			expectedCode:  http.StatusBadRequest,
+			expectedLevel: slog.LevelDebug,
+		},
+		{
+			name:          "Unsupported method",
+			method:        http.MethodPost,
+			body:          "error",
+			expectedCode:  http.StatusMethodNotAllowed,
+			expectedLevel: slog.LevelDebug,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(tt.method, "/-/loglevel", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("Status code = %d, want %d", rec.Code, tt.expectedCode)
+			}
+			if tt.expectedBody != "" && strings.TrimSpace(rec.Body.String()) != tt.expectedBody {
+				t.Errorf("Body = %q, want %q", rec.Body.String(), tt.expectedBody)
+			}
+			if level.Level() != tt.expectedLevel {
+				t.Errorf("Level = %v, want %v", level.Level(), tt.expectedLevel)
+			}
+		})
+	}
+
+	if !log.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Logger doesn't log debug messages after the level was changed to debug")
+	}
+}
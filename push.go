@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// pushMetrics gathers the metrics once and pushes them to the Pushgateway at
+// url, replacing the metrics previously pushed with the same job and grouping
+// labels.
+func pushMetrics(ctx context.Context, url string, job string, grouping map[string]string, gatherer prometheus.Gatherer) error {
+	pusher := push.New(url, job).Gatherer(gatherer)
+	for name, value := range grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+	return pusher.PushContext(ctx)
+}
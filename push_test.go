@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestPushMetrics(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "artifactory_up", Help: "Test gauge."})
+	gauge.Set(1)
+	registry.MustRegister(gauge)
+
+	err := pushMetrics(context.Background(), server.URL, "artifactory_exporter", map[string]string{"instance": "artifactory-prod"}, registry)
+	if err != nil {
+		t.Fatalf("pushMetrics() error = %v", err)
+	}
+	if method != http.MethodPut {
+		t.Errorf("Pushed with method %s, want %s", method, http.MethodPut)
+	}
+	if want := "/metrics/job/artifactory_exporter/instance/artifactory-prod"; path != want {
+		t.Errorf("Pushed to path %s, want %s", path, want)
+	}
+	if !strings.Contains(body, "artifactory_up") {
+		t.Errorf("Pushed body doesn't contain artifactory_up:\n%q", body)
+	}
+}
+
+func TestPushMetricsFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "push rejected", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := pushMetrics(context.Background(), server.URL, "artifactory_exporter", nil, prometheus.NewRegistry())
+	if err == nil {
+		t.Fatal("pushMetrics() error = nil, want error")
+	}
+}
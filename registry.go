@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsHandler returns the handler serving the metrics of gatherer and the
+// gatherer the metrics are served from. Unless disableDefaultMetrics is set,
+// the metrics of the default registry are served as well, which includes the
+// Go runtime and process collectors.
+func metricsHandler(disableDefaultMetrics bool, gatherer prometheus.Gatherer) (http.Handler, prometheus.Gatherer) {
+	if !disableDefaultMetrics {
+		gatherer = prometheus.Gatherers{prometheus.DefaultGatherer, gatherer}
+		return promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})), gatherer
+	}
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}), gatherer
+}
+
+// newRegistry returns a registry of collectors. The constant labels are added
+// to every metric of the collectors.
+func newRegistry(constLabels prometheus.Labels, collectors ...prometheus.Collector) (*prometheus.Registry, error) {
+	registry := prometheus.NewRegistry()
+	registerer := prometheus.WrapRegistererWith(constLabels, registry)
+	for _, c := range collectors {
+		if err := registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// handleMetricsPaths registers handler on mux for every path the metrics are
+// exposed under.
+func handleMetricsPaths(mux *http.ServeMux, paths []string, handler http.Handler) {
+	for _, path := range paths {
+		mux.Handle(path, handler)
+	}
+}
+
+// repoQueryHandler serves scrapes with a repo query parameter from the
+// collector returned by forRepo for that repository, using a registry of its
+// own which adds the constant labels. Other scrapes are served by handler.
+func repoQueryHandler(handler http.Handler, constLabels prometheus.Labels, forRepo func(repo string) prometheus.Collector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo := r.URL.Query().Get("repo")
+		if repo == "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		registry := prometheus.NewRegistry()
+		prometheus.WrapRegistererWith(constLabels, registry).MustRegister(forRepo(repo))
+		promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMetricsHandlerWithoutDefaultCollectors(t *testing.T) {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge", Help: "Test gauge."})
+	handler, _ := metricsHandler(true, testRegistry(t, nil, gauge))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	if !strings.Contains(string(body), "test_gauge 0") {
+		t.Errorf("Response doesn't contain the registered collector:\n%s", body)
+	}
+	for _, prefix := range []string{"go_", "process_", "promhttp_"} {
+		if strings.Contains(string(body), "\n"+prefix) || strings.HasPrefix(string(body), prefix) {
+			t.Errorf("Response contains %s* metrics although default collectors are disabled", prefix)
+		}
+	}
+}
+
+func TestRepoQueryHandler(t *testing.T) {
+	defaultGauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "default_gauge", Help: "Default gauge."})
+	var requestedRepo string
+	defaultHandler, _ := metricsHandler(true, testRegistry(t, nil, defaultGauge))
+	handler := repoQueryHandler(defaultHandler, nil, func(repo string) prometheus.Collector {
+		requestedRepo = repo
+		return prometheus.NewGauge(prometheus.GaugeOpts{Name: "repo_gauge", Help: "Repo gauge."})
+	})
+
+	tests := []struct {
+		name         string
+		target       string
+		expectedRepo string
+		expected     string
+		unexpected   string
+	}{
+		{
+			name:       "Without repo",
+			target:     "/metrics",
+			expected:   "default_gauge 0",
+			unexpected: "repo_gauge",
+		},
+		{
+			name:         "With repo",
+			target:       "/metrics?repo=libs-release",
+			expectedRepo: "libs-release",
+			expected:     "repo_gauge 0",
+			unexpected:   "default_gauge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestedRepo = ""
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			body, _ := io.ReadAll(rec.Body)
+
+			if requestedRepo != tt.expectedRepo {
+				t.Errorf("Collector requested for repo %q, want %q", requestedRepo, tt.expectedRepo)
+			}
+			if !strings.Contains(string(body), tt.expected) {
+				t.Errorf("Response doesn't contain %s:\n%s", tt.expected, body)
+			}
+			if strings.Contains(string(body), tt.unexpected) {
+				t.Errorf("Response contains %s:\n%s", tt.unexpected, body)
+			}
+		})
+	}
+}
+
+func TestHandleMetricsPaths(t *testing.T) {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge", Help: "Test gauge."})
+	handler, _ := metricsHandler(true, testRegistry(t, nil, gauge))
+	mux := http.NewServeMux()
+	handleMetricsPaths(mux, []string{"/metrics", "/artifactory/metrics"}, handler)
+
+	for _, path := range []string{"/metrics", "/artifactory/metrics"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		body, _ := io.ReadAll(rec.Body)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s returned status %d, want %d", path, rec.Code, http.StatusOK)
+		}
+		if !strings.Contains(string(body), "test_gauge 0") {
+			t.Errorf("GET %s doesn't contain the registered collector:\n%s", path, body)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/other", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /other returned status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestMetricsHandlerInstanceLabel(t *testing.T) {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge", Help: "Test gauge."})
+	handler, _ := metricsHandler(true, testRegistry(t, prometheus.Labels{"instance_name": "prod"}, gauge))
+	repoHandler := repoQueryHandler(handler, prometheus.Labels{"instance_name": "prod"}, func(repo string) prometheus.Collector {
+		return prometheus.NewGauge(prometheus.GaugeOpts{Name: "repo_gauge", Help: "Repo gauge."})
+	})
+
+	for target, expected := range map[string]string{
+		"/metrics":                   `test_gauge{instance_name="prod"} 0`,
+		"/metrics?repo=libs-release": `repo_gauge{instance_name="prod"} 0`,
+	} {
+		rec := httptest.NewRecorder()
+		repoHandler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		body, _ := io.ReadAll(rec.Body)
+
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("GET %s doesn't contain %s:\n%s", target, expected, body)
+		}
+	}
+}
+
+// testRegistry returns a registry of collectors with the constant labels.
+func testRegistry(t *testing.T, constLabels prometheus.Labels, collectors ...prometheus.Collector) *prometheus.Registry {
+	t.Helper()
+	registry, err := newRegistry(constLabels, collectors...)
+	if err != nil {
+		t.Fatalf("newRegistry() error = %v", err)
+	}
+	return registry
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus"
+	versioncollector "github.com/prometheus/client_golang/prometheus/collectors/version"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/collector"
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+// reloadableExporter serves the metrics of an exporter which is replaced when
+// the config is reloaded. Reloads wait for in-flight scrapes to complete.
+type reloadableExporter struct {
+	mu          sync.RWMutex
+	constLabels prometheus.Labels
+	namespace   string
+	registry    *prometheus.Registry
+	exporter    atomic.Pointer[collector.Exporter]
+}
+
+// newReloadableExporter returns a reloadable exporter serving the metrics of
+// an exporter created from conf.
+func newReloadableExporter(conf *config.Config) (*reloadableExporter, error) {
+	r := &reloadableExporter{
+		constLabels: conf.InstanceLabel,
+		namespace:   conf.MetricsNamespace,
+	}
+	if err := r.load(conf); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load creates an exporter from conf and swaps it in. The requests of the
+// replaced exporter are cancelled.
+func (r *reloadableExporter) load(conf *config.Config) error {
+	exporter, err := collector.NewExporter(conf)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	registry, err := newRegistry(r.constLabels, exporter, versioncollector.NewCollector(r.namespace+"_exporter"))
+	if err != nil {
+		exporter.CancelRequests()
+		return err
+	}
+	r.registry = registry
+	if previous := r.exporter.Swap(exporter); previous != nil {
+		previous.CancelRequests()
+	}
+	return nil
+}
+
+// Exporter returns the current exporter.
+func (r *reloadableExporter) Exporter() *collector.Exporter {
+	return r.exporter.Load()
+}
+
+// Gather gathers the metrics of the current exporter.
+func (r *reloadableExporter) Gather() ([]*dto.MetricFamily, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.registry.Gather()
+}
+
+// ForRepo returns a collector of the metrics of a single repository of the
+// current exporter.
+func (r *reloadableExporter) ForRepo(repo string) prometheus.Collector {
+	return lockedCollector{mu: &r.mu, Collector: r.Exporter().ForRepo(repo)}
+}
+
+// CancelRequests aborts all in-flight requests of the current exporter.
+func (r *reloadableExporter) CancelRequests() {
+	r.Exporter().CancelRequests()
+}
+
+// lockedCollector collects the metrics of a collector while holding the read
+// lock of a reloadable exporter.
+type lockedCollector struct {
+	mu *sync.RWMutex
+	prometheus.Collector
+}
+
+func (c lockedCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	c.Collector.Describe(ch)
+}
+
+func (c lockedCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	c.Collector.Collect(ch)
+}
+
+// reloadOnSignal reloads the config on SIGHUP until ctx is cancelled and swaps
+// in an exporter created from it. A config which fails to load is logged and
+// the current exporter is kept.
+func reloadOnSignal(ctx context.Context, conf *config.Config, r *reloadableExporter) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		}
+		conf.Logger.Info("Reloading the config")
+		reloaded, err := conf.Reload()
+		if err != nil {
+			conf.Logger.Error(
+				"Couldn't reload the config, keeping the current config",
+				"err", err.Error(),
+			)
+			continue
+		}
+		if err := r.load(reloaded); err != nil {
+			conf.Logger.Error(
+				"Couldn't create an exporter from the reloaded config, keeping the current config",
+				"err", err.Error(),
+			)
+			continue
+		}
+		conf = reloaded
+		conf.Logger.Info("Reloaded the config")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/peimanja/artifactory_exporter/config"
+	"github.com/peimanja/artifactory_exporter/logger"
+)
+
+func TestReloadableExporter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	newConf := func(instance string) *config.Config {
+		return &config.Config{
+			ArtiScrapeURI:         server.URL + "/artifactory",
+			ArtiTimeout:           5 * time.Second,
+			MetricsNamespace:      "artifactory",
+			InstanceLabel:         map[string]string{"instance_name": instance},
+			ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+			Credentials: &config.Credentials{
+				AuthMethod: "userPass",
+				Username:   "test",
+				Password:   "test",
+			},
+			Logger: logger.New(logger.Config{Level: "error"}),
+		}
+	}
+	// totalScrapes returns the number of scrapes of the current exporter and
+	// its instance label.
+	totalScrapes := func(r *reloadableExporter) (float64, string) {
+		t.Helper()
+		families, err := r.Gather()
+		if err != nil {
+			t.Fatalf("Gather() error = %v", err)
+		}
+		for _, family := range families {
+			if family.GetName() != "artifactory_exporter_total_scrapes" {
+				continue
+			}
+			metric := family.GetMetric()[0]
+			return metric.GetCounter().GetValue(), metric.GetLabel()[0].GetValue()
+		}
+		t.Fatal("artifactory_exporter_total_scrapes not gathered")
+		return 0, ""
+	}
+
+	r, err := newReloadableExporter(newConf("prod"))
+	if err != nil {
+		t.Fatalf("newReloadableExporter() error = %v", err)
+	}
+	previous := r.Exporter()
+	totalScrapes(r)
+	if scrapes, _ := totalScrapes(r); scrapes != 2 {
+		t.Fatalf("Total scrapes = %v, want 2", scrapes)
+	}
+
+	// The constant labels require a restart, so they are kept on reload.
+	if err := r.load(newConf("staging")); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if r.Exporter() == previous {
+		t.Error("Exporter wasn't replaced on reload")
+	}
+	scrapes, instance := totalScrapes(r)
+	if scrapes != 1 {
+		t.Errorf("Total scrapes after reload = %v, want 1 of the new exporter", scrapes)
+	}
+	if instance != "prod" {
+		t.Errorf("Instance label after reload = %s, want prod", instance)
+	}
+}
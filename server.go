@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// listener is an HTTP server and the listener it serves on.
+type listener struct {
+	srv *http.Server
+	ln  net.Listener
+}
+
+// runServers serves HTTP requests on every listener until ctx is cancelled.
+// On cancellation the servers stop accepting new requests and wait up to
+// shutdownTimeout for in-flight requests to complete. If the grace period
+// expires, cancelInFlight is called to abort outstanding work and the
+// remaining connections are closed, which is logged but not an error. If a
+// server fails, all are closed.
+func runServers(ctx context.Context, listeners []listener, shutdownTimeout time.Duration, cancelInFlight func(), logger *slog.Logger) error {
+	errCh := make(chan error, len(listeners))
+	for _, l := range listeners {
+		go func() {
+			errCh <- l.srv.Serve(l.ln)
+		}()
+	}
+
+	select {
+	case err := <-errCh:
+		for _, l := range listeners {
+			l.srv.Close()
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info(
+		"Shutting down, waiting for in-flight scrapes to complete",
+		"timeout", shutdownTimeout,
+	)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	shutdownErrCh := make(chan error, len(listeners))
+	for _, l := range listeners {
+		go func() {
+			shutdownErrCh <- l.srv.Shutdown(shutdownCtx)
+		}()
+	}
+	var shutdownErr error
+	for range listeners {
+		if err := <-shutdownErrCh; err != nil && shutdownErr == nil {
+			shutdownErr = err
+		}
+	}
+	if shutdownErr != nil {
+		logger.Warn(
+			"Shutdown grace period expired, cancelling in-flight scrapes",
+			"err", shutdownErr.Error(),
+		)
+		cancelInFlight()
+		for _, l := range listeners {
+			l.srv.Close()
+		}
+		return nil
+	}
+	for range listeners {
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleProbes registers the health and readiness probe endpoints on mux.
+func handleProbes(mux *http.ServeMux) {
+	mux.HandleFunc("/-/healthy", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "OK")
+	})
+	mux.HandleFunc("/-/ready", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "OK")
+	})
+}
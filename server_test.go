@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	l "github.com/peimanja/artifactory_exporter/logger"
+)
+
+func startTestServer(t *testing.T, handler http.Handler, shutdownTimeout time.Duration, cancelInFlight func()) (string, context.CancelFunc, chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &http.Server{Handler: handler}
+	done := make(chan error, 1)
+	go func() {
+		done <- runServers(ctx, []listener{{srv: srv, ln: ln}}, shutdownTimeout, cancelInFlight, l.New(l.Config{Level: "error"}))
+	}()
+	return "http://" + ln.Addr().String(), cancel, done
+}
+
+func TestRunServerDrainsInFlightScrape(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("metrics"))
+	})
+	url, shutdown, done := startTestServer(t, handler, 5*time.Second, func() {
+		t.Error("In-flight requests should not be cancelled within the grace period")
+	})
+
+	respCh := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(url + "/metrics")
+		if err != nil {
+			t.Errorf("Scrape failed: %v", err)
+			respCh <- ""
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		respCh <- string(body)
+	}()
+
+	<-started
+	shutdown()
+
+	if body := <-respCh; body != "metrics" {
+		t.Errorf("Scrape body = %q, want %q", body, "metrics")
+	}
+	if err := <-done; err != nil {
+		t.Errorf("runServers() error = %v", err)
+	}
+}
+
+func TestRunServerCancelsScrapeAfterGracePeriod(t *testing.T) {
+	started := make(chan struct{})
+	inFlight, cancelInFlight := context.WithCancel(context.Background())
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-inFlight.Done():
+		case <-time.After(5 * time.Second):
+			t.Error("In-flight scrape was not cancelled")
+		}
+	})
+	url, shutdown, done := startTestServer(t, handler, 50*time.Millisecond, cancelInFlight)
+
+	go http.Get(url + "/metrics")
+
+	<-started
+	shutdown()
+
+	if err := <-done; err != nil {
+		t.Errorf("runServers() error = %v, want nil when the grace period expires", err)
+	}
+	if inFlight.Err() == nil {
+		t.Error("In-flight requests should be cancelled when the grace period expires")
+	}
+}
+
+func TestRunServersHealthListener(t *testing.T) {
+	metricsMux := http.NewServeMux()
+	metricsMux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("metrics"))
+	})
+	probeMux := http.NewServeMux()
+	handleProbes(probeMux)
+
+	var listeners []listener
+	var urls []string
+	for _, handler := range []http.Handler{metricsMux, probeMux} {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("net.Listen() error = %v", err)
+		}
+		listeners = append(listeners, listener{srv: &http.Server{Handler: handler}, ln: ln})
+		urls = append(urls, "http://"+ln.Addr().String())
+	}
+	ctx, shutdown := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- runServers(ctx, listeners, 5*time.Second, func() {}, l.New(l.Config{Level: "error"}))
+	}()
+	metricsURL, healthURL := urls[0], urls[1]
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	for _, tt := range []struct {
+		url    string
+		status int
+	}{
+		{metricsURL + "/metrics", http.StatusOK},
+		{metricsURL + "/-/ready", http.StatusNotFound},
+		{healthURL + "/-/ready", http.StatusOK},
+		{healthURL + "/-/healthy", http.StatusOK},
+		{healthURL + "/metrics", http.StatusNotFound},
+	} {
+		resp, err := client.Get(tt.url)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", tt.url, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("GET %s returned status %d, want %d", tt.url, resp.StatusCode, tt.status)
+		}
+	}
+
+	shutdown()
+	if err := <-done; err != nil {
+		t.Errorf("runServers() error = %v", err)
+	}
+	for _, url := range urls {
+		if _, err := client.Get(url + "/-/healthy"); err == nil {
+			t.Errorf("Server at %s still serving after shutdown", url)
+		}
+	}
+}
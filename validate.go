@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+// validate checks that credentials, TLS settings and the scrape URI work
+// by pinging Artifactory and fetching its version. The result is reported
+// to w and an error is returned if Artifactory could not be reached.
+func validate(conf *config.Config, w io.Writer) error {
+	client := artifactory.NewClient(conf)
+
+	health, err := client.FetchHealth()
+	if err != nil {
+		return fmt.Errorf("could not ping JFrog Artifactory at %s: %w", conf.ArtiScrapeURI, err)
+	}
+	if !health.Healthy {
+		return fmt.Errorf("JFrog Artifactory at %s did not respond to ping with OK", conf.ArtiScrapeURI)
+	}
+
+	buildInfo, err := client.FetchBuildInfo()
+	if err != nil {
+		return fmt.Errorf("could not fetch JFrog Artifactory version: %w", err)
+	}
+
+	optionalMetrics := conf.ExporterRuntimeConfig.OptionalMetrics.Enabled()
+	if len(optionalMetrics) == 0 {
+		optionalMetrics = []string{"none"}
+	}
+
+	fmt.Fprintf(w, "Successfully connected to JFrog Artifactory at %s\n", conf.ArtiScrapeURI)
+	fmt.Fprintf(w, "Version: %s (revision %s)\n", buildInfo.Version, buildInfo.Revision)
+	fmt.Fprintf(w, "Authentication method: %s\n", conf.Credentials.AuthMethod)
+	fmt.Fprintf(w, "Enabled optional metrics: %s\n", strings.Join(optionalMetrics, ", "))
+	return nil
+}